@@ -0,0 +1,63 @@
+package access
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CLI returns the IOS-XE CLI lines of the access and trunk interfaces
+func (v *CiscoIOSXENativeAccessInterfaces) CLI() []string {
+	var lines []string
+	types := make([]string, 0, len(v.Interface))
+	for t := range v.Interface {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		for _, i := range v.Interface[t] {
+			lines = append(lines, fmt.Sprintf("interface %v%v", t, i.Name))
+			lines = append(lines, i.CLI()...)
+		}
+	}
+	return lines
+}
+
+// CLI returns the IOS-XE CLI lines of the interface config
+func (i *CiscoIOSXENativeAccessInterface) CLI() []string {
+	var lines []string
+	if i.Description != "" {
+		lines = append(lines, fmt.Sprintf(" description %v", i.Description))
+	}
+	if i.SwitchportConf.Switchport {
+		lines = append(lines, " switchport")
+	}
+	sp := i.Switchport
+	if sp.Access != nil {
+		lines = append(lines, fmt.Sprintf(" switchport access vlan %v", sp.Access.Vlan.Vlan))
+	}
+	if sp.Trunk != nil && sp.Trunk.Native != nil {
+		lines = append(lines, fmt.Sprintf(" switchport trunk native vlan %v", sp.Trunk.Native.Vlan.VlanID))
+	}
+	if sp.Trunk != nil && sp.Trunk.Allowed != nil {
+		lines = append(lines, fmt.Sprintf(" switchport trunk allowed vlan %v", sp.Trunk.Allowed.Vlan.Vlans))
+	}
+	switch {
+	case sp.Mode.Access != nil:
+		lines = append(lines, " switchport mode access")
+	case sp.Mode.Trunk != nil:
+		lines = append(lines, " switchport mode trunk")
+	}
+	if st := i.SpanningTree; st != nil {
+		if st.Portfast != nil {
+			if st.Portfast.Trunk != nil {
+				lines = append(lines, " spanning-tree portfast trunk")
+			} else {
+				lines = append(lines, " spanning-tree portfast")
+			}
+		}
+		if st.Bpduguard != nil {
+			lines = append(lines, " spanning-tree bpduguard enable")
+		}
+	}
+	return lines
+}
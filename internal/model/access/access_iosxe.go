@@ -0,0 +1,71 @@
+package access
+
+// CiscoIOSXENativeAccessInterfaces is the switchport config of interfaces per interface type (e.g. GigabitEthernet)
+type CiscoIOSXENativeAccessInterfaces struct {
+	Interface map[string][]CiscoIOSXENativeAccessInterface `json:"Cisco-IOS-XE-native:interface"`
+}
+
+type CiscoIOSXENativeAccessInterface struct {
+	Name           interface{}                    `json:"name"`
+	Description    string                         `json:"description,omitempty"`
+	SwitchportConf CiscoIOSXENativeSwitchportConf `json:"switchport-conf"`
+	Switchport     CiscoIOSXENativeSwitchport     `json:"switchport"`
+	SpanningTree   *CiscoIOSXENativeSpanningTree  `json:"spanning-tree,omitempty"`
+}
+
+type CiscoIOSXENativeSwitchportConf struct {
+	Switchport bool `json:"switchport"`
+}
+
+type CiscoIOSXESwitchMode struct {
+	Access *struct{} `json:"access,omitempty"`
+	Trunk  *struct{} `json:"trunk,omitempty"`
+}
+
+type CiscoIOSXESwitchAccessVlan struct {
+	Vlan int `json:"vlan"`
+}
+
+type CiscoIOSXESwitchAccess struct {
+	Vlan CiscoIOSXESwitchAccessVlan `json:"vlan"`
+}
+
+type CiscoIOSXESwitchTrunkAllowedVlan struct {
+	Vlans string `json:"vlans"`
+}
+
+type CiscoIOSXESwitchTrunkAllowed struct {
+	Vlan CiscoIOSXESwitchTrunkAllowedVlan `json:"vlan"`
+}
+
+type CiscoIOSXESwitchTrunkNativeVlan struct {
+	VlanID int `json:"vlan-id"`
+}
+
+type CiscoIOSXESwitchTrunkNative struct {
+	Vlan CiscoIOSXESwitchTrunkNativeVlan `json:"vlan"`
+}
+
+type CiscoIOSXESwitchTrunk struct {
+	Allowed *CiscoIOSXESwitchTrunkAllowed `json:"allowed,omitempty"`
+	Native  *CiscoIOSXESwitchTrunkNative  `json:"native,omitempty"`
+}
+
+type CiscoIOSXENativeSwitchport struct {
+	Mode   CiscoIOSXESwitchMode    `json:"Cisco-IOS-XE-switch:mode"`
+	Access *CiscoIOSXESwitchAccess `json:"Cisco-IOS-XE-switch:access,omitempty"`
+	Trunk  *CiscoIOSXESwitchTrunk  `json:"Cisco-IOS-XE-switch:trunk,omitempty"`
+}
+
+type CiscoIOSXESpanningTreePortfast struct {
+	Trunk []string `json:"trunk,omitempty"`
+}
+
+type CiscoIOSXESpanningTreeBpduguard struct {
+	Enable []string `json:"enable,omitempty"`
+}
+
+type CiscoIOSXENativeSpanningTree struct {
+	Portfast  *CiscoIOSXESpanningTreePortfast  `json:"Cisco-IOS-XE-spanning-tree:portfast,omitempty"`
+	Bpduguard *CiscoIOSXESpanningTreeBpduguard `json:"Cisco-IOS-XE-spanning-tree:bpduguard,omitempty"`
+}
@@ -0,0 +1,122 @@
+package bgp
+
+import "fmt"
+
+// CLI returns the IOS-XE CLI lines of the router bgp neighbors and system settings
+func (v *CiscoIOSXEBgpNeighbors) CLI() []string {
+	var lines []string
+	for _, r := range v.CiscoIOSXEBgpBgp {
+		lines = append(lines, fmt.Sprintf("router bgp %v", r.ID))
+		lines = append(lines, neighborsCLI(r)...)
+	}
+	return lines
+}
+
+// CLI returns the IOS-XE CLI lines of the router bgp system settings
+func (v *CiscoIOSXEBgpBgpSystem) CLI() []string {
+	var lines []string
+	for _, r := range v.CiscoIOSXEBgpBgp {
+		lines = append(lines, fmt.Sprintf("router bgp %v", r.ID))
+		switch {
+		case r.Bgp.RouterID.IP != "":
+			lines = append(lines, fmt.Sprintf(" bgp router-id %v", r.Bgp.RouterID.IP))
+		case r.Bgp.RouterID.Interface.Loopback != 0:
+			lines = append(lines, fmt.Sprintf(" bgp router-id interface Loopback%v", r.Bgp.RouterID.Interface.Loopback))
+		}
+		if r.Bgp.LogNeighborChanges {
+			lines = append(lines, " bgp log-neighbor-changes")
+		}
+		if !r.Bgp.Default.Ipv4Unicast {
+			lines = append(lines, " no bgp default ipv4-unicast")
+		}
+	}
+	return lines
+}
+
+// CLI returns the IOS-XE CLI lines of the vrf address families of router bgp asn
+func (v *CiscoIOSXEBgpWithVrfs) CLI(asn int) []string {
+	lines := []string{fmt.Sprintf("router bgp %v", asn)}
+	for _, af := range v.CiscoIOSXEBgpWithVrf.Ipv4 {
+		for _, vrf := range af.Vrf {
+			lines = append(lines, fmt.Sprintf(" address-family ipv4 vrf %v", vrf.Name))
+			if vrf.Ipv4Unicast.Advertise.L2Vpn.Evpn != nil {
+				lines = append(lines, "  advertise l2vpn evpn")
+			}
+			for _, n := range vrf.Ipv4Unicast.Neighbor {
+				lines = append(lines, neighborCLI("  ", n.ID, n.RemoteAs, n.Activate != nil)...)
+			}
+			if vrf.Ipv4Unicast.RedistributeVrf.Connected != nil {
+				lines = append(lines, "  redistribute connected")
+			}
+			if vrf.Ipv4Unicast.RedistributeVrf.Static != nil {
+				lines = append(lines, "  redistribute static")
+			}
+			lines = append(lines, " exit-address-family")
+		}
+	}
+	for _, af := range v.CiscoIOSXEBgpWithVrf.Ipv6 {
+		for _, vrf := range af.Vrf {
+			lines = append(lines, fmt.Sprintf(" address-family ipv6 vrf %v", vrf.Name))
+			if vrf.Ipv6Unicast.Advertise.L2Vpn.Evpn != nil {
+				lines = append(lines, "  advertise l2vpn evpn")
+			}
+			if vrf.Ipv6Unicast.RedistributeV6.Connected != nil {
+				lines = append(lines, "  redistribute connected")
+			}
+			if vrf.Ipv6Unicast.RedistributeV6.Static != nil {
+				lines = append(lines, "  redistribute static")
+			}
+			lines = append(lines, " exit-address-family")
+		}
+	}
+	return lines
+}
+
+// CLI returns the IOS-XE CLI lines of the ipv4 neighbors of the vrf of router bgp asn
+func (v *CiscoIOSXEBgpVrfIpv4Unicast) CLI(asn int, vrf string) []string {
+	lines := []string{
+		fmt.Sprintf("router bgp %v", asn),
+		fmt.Sprintf(" address-family ipv4 vrf %v", vrf),
+	}
+	for _, n := range v.CiscoIOSXEBgpIpv4Unicast.Neighbor {
+		lines = append(lines, neighborCLI("  ", n.ID, n.RemoteAs, n.Activate != nil)...)
+	}
+	return append(lines, " exit-address-family")
+}
+
+func neighborsCLI(r CiscoIOSXEBgp) []string {
+	var lines []string
+	for _, n := range r.Neighbor {
+		lines = append(lines, neighborCLI(" ", n.ID, n.RemoteAs, false)...)
+		if n.UpdateSource.Interface.Loopback != 0 {
+			lines = append(lines, fmt.Sprintf(" neighbor %v update-source Loopback%v", n.ID, n.UpdateSource.Interface.Loopback))
+		}
+	}
+	for _, af := range r.AddressFamily.NoVrf.L2Vpn {
+		lines = append(lines, fmt.Sprintf(" address-family l2vpn %v", af.AfName))
+		for _, n := range af.L2VpnEvpn.Neighbor {
+			if n.Activate != nil {
+				lines = append(lines, fmt.Sprintf("  neighbor %v activate", n.ID))
+			}
+			if n.SendCommunity.SendCommunityWhere != "" {
+				lines = append(lines, fmt.Sprintf("  neighbor %v send-community %v", n.ID, n.SendCommunity.SendCommunityWhere))
+			}
+			if n.RouteReflectorClient != nil {
+				lines = append(lines, fmt.Sprintf("  neighbor %v route-reflector-client", n.ID))
+			}
+		}
+		lines = append(lines, " exit-address-family")
+	}
+	return lines
+}
+
+func neighborCLI(indent, id string, remoteAs int, activate bool) []string {
+	var lines []string
+	if remoteAs != 0 {
+		lines = append(lines, fmt.Sprintf("%vneighbor %v remote-as %v", indent, id, remoteAs))
+	}
+	if activate {
+		lines = append(lines, fmt.Sprintf("%vneighbor %v activate", indent, id))
+	}
+	return lines
+}
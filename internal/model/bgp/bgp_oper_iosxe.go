@@ -0,0 +1,25 @@
+package bgp
+
+type CiscoIOSXEBgpOperNeighbors struct {
+	Neighbors CiscoIOSXEBgpOperNeighborList `json:"Cisco-IOS-XE-bgp-oper:neighbors"`
+}
+type CiscoIOSXEBgpOperNeighborList struct {
+	Neighbor []CiscoIOSXEBgpOperNeighbor `json:"neighbor,omitempty"`
+}
+type CiscoIOSXEBgpOperPrefixCount struct {
+	TotalPrefixes int `json:"total-prefixes,omitempty"`
+}
+type CiscoIOSXEBgpOperPrefixActivity struct {
+	Sent     CiscoIOSXEBgpOperPrefixCount `json:"sent,omitempty"`
+	Received CiscoIOSXEBgpOperPrefixCount `json:"received,omitempty"`
+}
+type CiscoIOSXEBgpOperNeighbor struct {
+	AfiSafi           string                          `json:"afi-safi,omitempty"`
+	VrfName           string                          `json:"vrf-name,omitempty"`
+	NeighborID        string                          `json:"neighbor-id,omitempty"`
+	As                int                             `json:"as,omitempty"`
+	UpTime            string                          `json:"up-time,omitempty"`
+	SessionState      string                          `json:"session-state,omitempty"`
+	InstalledPrefixes int                             `json:"installed-prefixes,omitempty"`
+	PrefixActivity    CiscoIOSXEBgpOperPrefixActivity `json:"prefix-activity,omitempty"`
+}
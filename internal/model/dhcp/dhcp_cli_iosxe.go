@@ -0,0 +1,28 @@
+package dhcp
+
+import "fmt"
+
+// CLI returns the IOS-XE CLI lines of the global DHCP settings
+func (v *CiscoIOSXENativeDhcps) CLI() []string {
+	var lines []string
+	dhcp := v.CiscoIOSXENativeDhcp
+	if s := dhcp.CiscoIOSXEDhcpCompatibility.Suboption.LinkSelection; s != "" {
+		lines = append(lines, fmt.Sprintf("ip dhcp compatibility suboption link-selection %v", s))
+	}
+	if s := dhcp.CiscoIOSXEDhcpCompatibility.Suboption.ServerOverride; s != "" {
+		lines = append(lines, fmt.Sprintf("ip dhcp compatibility suboption server-override %v", s))
+	}
+	if dhcp.CiscoIOSXEDhcpRelay.Information.Option.OptionDefault != nil {
+		lines = append(lines, "ip dhcp relay information option")
+	}
+	if dhcp.CiscoIOSXEDhcpRelay.Information.Option.Vpn != nil {
+		lines = append(lines, "ip dhcp relay information option vpn")
+	}
+	for _, vlans := range dhcp.CiscoIOSXEDhcpSnoopingConf.Snooping.VlanList {
+		lines = append(lines, fmt.Sprintf("ip dhcp snooping vlan %v", vlans.ID))
+	}
+	if dhcp.CiscoIOSXEDhcpSnooping != nil {
+		lines = append(lines, "ip dhcp snooping")
+	}
+	return lines
+}
@@ -0,0 +1,34 @@
+package ethernet_segment
+
+import "fmt"
+
+// CLI returns the IOS-XE CLI lines of the ethernet segments
+func (v *CiscoIOSXEL2VpnEthernetSegments) CLI() []string {
+	var lines []string
+	for _, es := range v.EthernetSegment {
+		lines = append(lines, fmt.Sprintf("l2vpn evpn ethernet-segment %v", es.EsValue))
+		switch es.Identifier.Type {
+		case 3:
+			lines = append(lines, fmt.Sprintf(" identifier type 3 system-mac %v", es.Identifier.SystemMac))
+		default:
+			lines = append(lines, fmt.Sprintf(" identifier type %v %v", es.Identifier.Type, es.Identifier.Esi))
+		}
+		switch {
+		case es.Redundancy.AllActive != nil:
+			lines = append(lines, " redundancy all-active")
+		case es.Redundancy.SingleActive != nil:
+			lines = append(lines, " redundancy single-active")
+		}
+	}
+	return lines
+}
+
+// CLI returns the IOS-XE CLI lines binding the ethernet segments to the Port-channels
+func (v *CiscoIOSXENativePortChannelEvpn) CLI() []string {
+	var lines []string
+	for _, p := range v.PortChannel {
+		lines = append(lines, fmt.Sprintf("interface Port-channel%v", p.Name))
+		lines = append(lines, fmt.Sprintf(" evpn ethernet-segment %v", p.Evpn.EthernetSegment))
+	}
+	return lines
+}
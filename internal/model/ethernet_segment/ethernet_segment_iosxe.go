@@ -0,0 +1,37 @@
+package ethernet_segment
+
+// CiscoIOSXEL2VpnEthernetSegments is the l2vpn evpn ethernet-segment list
+type CiscoIOSXEL2VpnEthernetSegments struct {
+	EthernetSegment []CiscoIOSXEL2VpnEthernetSegment `json:"Cisco-IOS-XE-l2vpn:ethernet-segment"`
+}
+
+type CiscoIOSXEL2VpnEthernetSegmentIdentifier struct {
+	Type      int    `json:"type"`
+	Esi       string `json:"esi,omitempty"`
+	SystemMac string `json:"system-mac,omitempty"`
+}
+
+type CiscoIOSXEL2VpnEthernetSegmentRedundancy struct {
+	AllActive    []string `json:"all-active,omitempty"`
+	SingleActive []string `json:"single-active,omitempty"`
+}
+
+type CiscoIOSXEL2VpnEthernetSegment struct {
+	EsValue    int                                      `json:"es-value"`
+	Identifier CiscoIOSXEL2VpnEthernetSegmentIdentifier `json:"identifier"`
+	Redundancy CiscoIOSXEL2VpnEthernetSegmentRedundancy `json:"redundancy"`
+}
+
+// CiscoIOSXENativePortChannelEvpn binds the ethernet segment to the Port-channel interface
+type CiscoIOSXENativePortChannelEvpn struct {
+	PortChannel []CiscoIOSXENativePortChannelEvpnInterface `json:"Cisco-IOS-XE-native:Port-channel"`
+}
+
+type CiscoIOSXEL2VpnInterfaceEvpn struct {
+	EthernetSegment int `json:"ethernet-segment"`
+}
+
+type CiscoIOSXENativePortChannelEvpnInterface struct {
+	Name int                          `json:"name"`
+	Evpn CiscoIOSXEL2VpnInterfaceEvpn `json:"Cisco-IOS-XE-l2vpn:evpn"`
+}
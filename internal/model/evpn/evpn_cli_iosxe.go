@@ -0,0 +1,27 @@
+package evpn
+
+import "fmt"
+
+// CLI returns the IOS-XE CLI lines of the global l2vpn evpn settings
+func (v *CiscoIOSXEL2Evpn) CLI() []string {
+	e := v.CiscoIOSXEL2VpnEvpn
+	lines := []string{"l2vpn evpn"}
+	if e.ReplicationType.Static != nil {
+		lines = append(lines, " replication-type static")
+	}
+	lines = append(lines,
+		fmt.Sprintf(" mac duplication limit %v time %v", e.Mac.Duplication.Limit, e.Mac.Duplication.Time),
+		fmt.Sprintf(" ip duplication limit %v time %v", e.IP.Duplication.Limit, e.IP.Duplication.Time),
+		fmt.Sprintf(" router-id Loopback%v", e.RouterID.Interface.Loopback),
+	)
+	if e.DefaultGateway.Advertise != nil {
+		lines = append(lines, " default-gateway advertise")
+	}
+	if e.Logging.Peer.State != nil {
+		lines = append(lines, " logging peer state")
+	}
+	if e.RouteTarget.Auto.Vni != nil {
+		lines = append(lines, " route-target auto vni")
+	}
+	return lines
+}
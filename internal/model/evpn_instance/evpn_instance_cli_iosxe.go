@@ -0,0 +1,39 @@
+package evpn_instance
+
+import "fmt"
+
+// CLI returns the IOS-XE CLI lines of the l2vpn evpn instances
+func (v *CiscoIOSXEL2VpnInstanceCiscoIOSXEL2VpnInstanceEvpn) CLI() []string {
+	var lines []string
+	for _, i := range v.CiscoIOSXEL2VpnInstance.Instance {
+		vb := i.VlanBased
+		if vb.DefaultGateway.Advertise == "" {
+			lines = append(lines, fmt.Sprintf("l2vpn evpn instance %v", i.EvpnInstanceNum))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("l2vpn evpn instance %v vlan-based", i.EvpnInstanceNum))
+		if vb.Encapsulation != "" {
+			lines = append(lines, fmt.Sprintf(" encapsulation %v", vb.Encapsulation))
+		}
+		switch {
+		case vb.ReplicationType.Static != nil:
+			lines = append(lines, " replication-type static")
+		case vb.ReplicationType.Ingress != nil:
+			lines = append(lines, " replication-type ingress")
+		}
+		if vb.Rd.RdValue != "" {
+			lines = append(lines, fmt.Sprintf(" rd %v", vb.Rd.RdValue))
+		}
+		if vb.RouteTarget.Both.RtValue != "" {
+			lines = append(lines, fmt.Sprintf(" route-target both %v", vb.RouteTarget.Both.RtValue))
+		}
+		if vb.IP.LocalLearning.Disable != nil {
+			lines = append(lines, " ip local-learning disable")
+		}
+		lines = append(lines, fmt.Sprintf(" default-gateway advertise %v", vb.DefaultGateway.Advertise))
+		if vb.ReOriginate.RouteType5 != nil {
+			lines = append(lines, " re-originate route-type5")
+		}
+	}
+	return lines
+}
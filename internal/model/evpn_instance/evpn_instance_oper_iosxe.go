@@ -0,0 +1,13 @@
+package evpn_instance
+
+type CiscoIOSXEEvpnOperInstances struct {
+	EvpnInstance []CiscoIOSXEEvpnOperInstance `json:"Cisco-IOS-XE-evpn-oper:evpn-instance,omitempty"`
+}
+type CiscoIOSXEEvpnOperInstance struct {
+	Evi           int    `json:"evi,omitempty"`
+	Type          string `json:"type,omitempty"`
+	Encapsulation string `json:"encapsulation,omitempty"`
+	State         string `json:"state,omitempty"`
+	Rd            string `json:"rd,omitempty"`
+	Vlan          int    `json:"vlan,omitempty"`
+}
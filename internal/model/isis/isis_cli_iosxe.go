@@ -0,0 +1,56 @@
+package isis
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CLI returns the IOS-XE CLI lines of the IS-IS processes
+func (v *CiscoIOSXENativeRouterIsis) CLI() []string {
+	var lines []string
+	for _, r := range v.CiscoIOSXEIsis {
+		lines = append(lines, fmt.Sprintf("router isis %v", r.AreaTag))
+		for _, n := range r.Net {
+			lines = append(lines, fmt.Sprintf(" net %v", n.Tag))
+		}
+		if r.IsType != "" {
+			lines = append(lines, fmt.Sprintf(" is-type %v", r.IsType))
+		}
+		if r.Bfd != nil && r.Bfd.AllInterfaces != nil {
+			lines = append(lines, " bfd all-interfaces")
+		}
+		if r.PassiveInterface == nil {
+			continue
+		}
+		for _, i := range r.PassiveInterface.Interface {
+			lines = append(lines, fmt.Sprintf(" passive-interface %v", i))
+		}
+	}
+	return lines
+}
+
+// CLI returns the IOS-XE CLI lines of the IS-IS interfaces
+func (v *CiscoIOSXENativeIsisInterfaces) CLI() []string {
+	var lines []string
+	types := make([]string, 0, len(v.Interface))
+	for t := range v.Interface {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		for _, i := range v.Interface[t] {
+			lines = append(lines, fmt.Sprintf("interface %v%v", t, i.Name))
+			lines = append(lines, fmt.Sprintf(" ip router isis %v", i.IP.Router.Isis.Tag))
+			if i.Isis == nil {
+				continue
+			}
+			if i.Isis.CircuitType != "" {
+				lines = append(lines, fmt.Sprintf(" isis circuit-type %v", i.Isis.CircuitType))
+			}
+			if i.Isis.Network != nil && i.Isis.Network.PointToPoint != nil {
+				lines = append(lines, " isis network point-to-point")
+			}
+		}
+	}
+	return lines
+}
@@ -0,0 +1,65 @@
+package isis
+
+type CiscoIOSXENativeRouterIsis struct {
+	CiscoIOSXEIsis []CiscoIOSXEIsis `json:"Cisco-IOS-XE-isis:isis"`
+}
+
+type CiscoIOSXEIsisNet struct {
+	Tag string `json:"tag"`
+}
+
+type CiscoIOSXEIsisBfd struct {
+	AllInterfaces []string `json:"all-interfaces,omitempty"`
+}
+
+type CiscoIOSXEIsisPassiveInterface struct {
+	Interface []string `json:"interface,omitempty"`
+}
+
+type CiscoIOSXEIsis struct {
+	AreaTag          string                          `json:"area-tag"`
+	Net              []CiscoIOSXEIsisNet             `json:"net,omitempty"`
+	IsType           string                          `json:"is-type,omitempty"`
+	Bfd              *CiscoIOSXEIsisBfd              `json:"bfd,omitempty"`
+	PassiveInterface *CiscoIOSXEIsisPassiveInterface `json:"passive-interface,omitempty"`
+}
+
+// CiscoIOSXENativeIsisInterfaces is the IS-IS config of interfaces per interface type (e.g. TenGigabitEthernet)
+type CiscoIOSXENativeIsisInterfaces struct {
+	Interface map[string][]CiscoIOSXENativeIsisInterface `json:"Cisco-IOS-XE-native:interface"`
+}
+
+type CiscoIOSXENativeIsisInterface struct {
+	Name interface{}                     `json:"name"`
+	IP   CiscoIOSXENativeIsisInterfaceIP `json:"ip"`
+	Isis *CiscoIOSXEIsisInterface        `json:"Cisco-IOS-XE-isis:isis,omitempty"`
+}
+
+type CiscoIOSXENativeIsisInterfaceIP struct {
+	Router CiscoIOSXENativeIsisInterfaceRouter `json:"router"`
+}
+
+type CiscoIOSXENativeIsisInterfaceRouter struct {
+	Isis CiscoIOSXEIsisInterfaceRouter `json:"Cisco-IOS-XE-isis:isis"`
+}
+
+type CiscoIOSXEIsisInterfaceRouter struct {
+	Tag string `json:"tag"`
+}
+
+type CiscoIOSXEIsisInterfaceNetwork struct {
+	PointToPoint []string `json:"point-to-point,omitempty"`
+}
+
+type CiscoIOSXEIsisInterface struct {
+	CircuitType string                          `json:"circuit-type,omitempty"`
+	Network     *CiscoIOSXEIsisInterfaceNetwork `json:"network,omitempty"`
+}
+
+type CiscoIOSXEIsisInterfaceRouters struct {
+	Isis CiscoIOSXEIsisInterfaceRouter `json:"Cisco-IOS-XE-isis:isis"`
+}
+
+type CiscoIOSXEIsisInterfaces struct {
+	Isis CiscoIOSXEIsisInterface `json:"Cisco-IOS-XE-isis:isis"`
+}
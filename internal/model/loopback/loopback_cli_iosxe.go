@@ -0,0 +1,21 @@
+package loopback
+
+import "fmt"
+
+// CLI returns the IOS-XE CLI lines of the loopback interfaces
+func (v *CiscoIOSXENativeLoopbackInterface) CLI() []string {
+	var lines []string
+	for _, lp := range v.CiscoIOSXENativeLoopback {
+		lines = append(lines, fmt.Sprintf("interface Loopback%v", lp.Name))
+		if lp.Description != "" {
+			lines = append(lines, fmt.Sprintf(" description %v", lp.Description))
+		}
+		if p := lp.IP.Address.Primary; p.Address != "" {
+			lines = append(lines, fmt.Sprintf(" ip address %v %v", p.Address, p.Mask))
+		}
+		if lp.IP.Pim.CiscoIOSXEMulticastPimModeChoiceCfg.SparseMode != nil {
+			lines = append(lines, " ip pim sparse-mode")
+		}
+	}
+	return lines
+}
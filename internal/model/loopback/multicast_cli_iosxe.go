@@ -0,0 +1,44 @@
+package loopback
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CLI returns the IOS-XE CLI lines of the global multicast config
+func (v *CiscoIOSXENativeIPMulticast) CLI() []string {
+	var lines []string
+	ip := v.CiscoIOSXENativeIP
+	if ip.MulticastRouting != nil {
+		lines = append(lines, "ip multicast-routing")
+	}
+	if ip.Pim != nil {
+		lines = append(lines, fmt.Sprintf("ip pim rp-address %v", ip.Pim.RpAddress.Address))
+	}
+	if ip.Msdp != nil {
+		for _, p := range ip.Msdp.Peer {
+			lines = append(lines, fmt.Sprintf("ip msdp peer %v connect-source Loopback%v", p.Addr, p.ConnectSource.Loopback))
+		}
+		lines = append(lines, fmt.Sprintf("ip msdp originator-id Loopback%v", ip.Msdp.OriginatorID.Loopback))
+	}
+	return lines
+}
+
+// CLI returns the IOS-XE CLI lines of the PIM interfaces
+func (v *CiscoIOSXENativePimInterfaces) CLI() []string {
+	var lines []string
+	types := make([]string, 0, len(v.Interface))
+	for t := range v.Interface {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		for _, i := range v.Interface[t] {
+			lines = append(lines, fmt.Sprintf("interface %v%v", t, i.Name))
+			if i.IP.Pim.CiscoIOSXEMulticastPimModeChoiceCfg.SparseMode != nil {
+				lines = append(lines, " ip pim sparse-mode")
+			}
+		}
+	}
+	return lines
+}
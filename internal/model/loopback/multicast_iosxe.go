@@ -0,0 +1,66 @@
+package loopback
+
+type CiscoIOSXENativeIPMulticast struct {
+	CiscoIOSXENativeIP CiscoIOSXENativeIP `json:"Cisco-IOS-XE-native:ip"`
+}
+
+type CiscoIOSXENativeIP struct {
+	MulticastRouting *CiscoIOSXEMulticastRouting `json:"Cisco-IOS-XE-multicast:multicast-routing,omitempty"`
+	Pim              *CiscoIOSXEMulticastPim     `json:"Cisco-IOS-XE-multicast:pim,omitempty"`
+	Msdp             *CiscoIOSXEMulticastMsdp    `json:"Cisco-IOS-XE-multicast:msdp,omitempty"`
+}
+
+type CiscoIOSXEMulticastRouting struct{}
+
+type CiscoIOSXEMulticastRpAddress struct {
+	Address string `json:"address"`
+}
+
+type CiscoIOSXEMulticastPim struct {
+	RpAddress CiscoIOSXEMulticastRpAddress `json:"rp-address"`
+}
+
+type CiscoIOSXEMulticastMsdpSource struct {
+	Loopback int `json:"Loopback"`
+}
+
+type CiscoIOSXEMulticastMsdpPeer struct {
+	Addr          string                        `json:"addr"`
+	ConnectSource CiscoIOSXEMulticastMsdpSource `json:"connect-source"`
+}
+
+type CiscoIOSXEMulticastMsdp struct {
+	Peer         []CiscoIOSXEMulticastMsdpPeer `json:"peer,omitempty"`
+	OriginatorID CiscoIOSXEMulticastMsdpSource `json:"originator-id"`
+}
+
+// CiscoIOSXENativePimInterfaces is the PIM config of interfaces per interface type (e.g. TenGigabitEthernet)
+type CiscoIOSXENativePimInterfaces struct {
+	Interface map[string][]CiscoIOSXENativePimInterface `json:"Cisco-IOS-XE-native:interface"`
+}
+
+type CiscoIOSXENativePimInterface struct {
+	Name interface{}                    `json:"name"`
+	IP   CiscoIOSXENativePimInterfaceIP `json:"ip"`
+}
+
+// CiscoIOSXENativePimInterfaceIP has the same PIM config as the loopbacks
+type CiscoIOSXENativePimInterfaceIP struct {
+	Pim CiscoIOSXENativeLoopbackPim `json:"pim"`
+}
+
+type CiscoIOSXEMulticastRoutings struct {
+	MulticastRouting *CiscoIOSXEMulticastRouting `json:"Cisco-IOS-XE-multicast:multicast-routing"`
+}
+
+type CiscoIOSXEMulticastRpAddresses struct {
+	RpAddress CiscoIOSXEMulticastRpAddress `json:"Cisco-IOS-XE-multicast:rp-address"`
+}
+
+type CiscoIOSXEMulticastMsdps struct {
+	Msdp CiscoIOSXEMulticastMsdp `json:"Cisco-IOS-XE-multicast:msdp"`
+}
+
+type CiscoIOSXEInterfacePims struct {
+	Pim CiscoIOSXENativeLoopbackPim `json:"Cisco-IOS-XE-native:pim"`
+}
@@ -0,0 +1,37 @@
+package nve
+
+import "fmt"
+
+// CLI returns the IOS-XE CLI lines of the nve interfaces
+func (v *CiscoIOSXENativeNves) CLI() []string {
+	var lines []string
+	for _, n := range v.CiscoIOSXENativeNve {
+		lines = append(lines, fmt.Sprintf("interface nve%v", n.Name))
+		if n.Description != "" {
+			lines = append(lines, fmt.Sprintf(" description %v", n.Description))
+		}
+		lines = append(lines, " no ip address")
+		if n.SourceInterface.Loopback != 0 {
+			lines = append(lines, fmt.Sprintf(" source-interface Loopback%v", n.SourceInterface.Loopback))
+		}
+		if n.HostReachability.Protocol.Bgp != nil {
+			lines = append(lines, " host-reachability protocol bgp")
+		}
+		for _, vni := range n.MemberInOneLine.Member.Vni {
+			lines = append(lines, fmt.Sprintf(" member vni %v vrf %v", vni.VniRange, vni.Vrf))
+		}
+		for _, vni := range n.Member.Vni {
+			switch {
+			case vni.McastGroup != nil:
+				lines = append(lines, fmt.Sprintf(" member vni %v mcast-group %v", vni.VniRange, vni.McastGroup.MulticastGroupMin))
+			case vni.IrCpConfig != nil:
+				lines = append(lines, fmt.Sprintf(" member vni %v ingress-replication", vni.VniRange))
+			case vni.Vrf != "":
+				lines = append(lines, fmt.Sprintf(" member vni %v vrf %v", vni.VniRange, vni.Vrf))
+			default:
+				lines = append(lines, fmt.Sprintf(" member vni %v", vni.VniRange))
+			}
+		}
+	}
+	return lines
+}
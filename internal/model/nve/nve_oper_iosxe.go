@@ -0,0 +1,27 @@
+package nve
+
+type CiscoIOSXENveOperData struct {
+	NveOperData NveOperData `json:"Cisco-IOS-XE-nve-oper:nve-oper-data"`
+}
+type NveOperData struct {
+	NveVni   []NveOperVni  `json:"nve-vni,omitempty"`
+	NvePeers []NveOperPeer `json:"nve-peers,omitempty"`
+}
+type NveOperVni struct {
+	NveIfName string `json:"nve-if-name,omitempty"`
+	Vni       int    `json:"vni,omitempty"`
+	McastAddr string `json:"mcast-addr,omitempty"`
+	VniState  string `json:"vni-state,omitempty"`
+	Mode      string `json:"mode,omitempty"`
+	Vlan      int    `json:"vlan,omitempty"`
+	CfgSrc    string `json:"cfg-src,omitempty"`
+	Vrf       string `json:"vrf,omitempty"`
+}
+type NveOperPeer struct {
+	NveIfName string `json:"nve-if-name,omitempty"`
+	Vni       int    `json:"vni,omitempty"`
+	PeerIP    string `json:"peer-ip,omitempty"`
+	PeerState string `json:"peer-state,omitempty"`
+	LearnType string `json:"learn-type,omitempty"`
+	Uptime    string `json:"uptime,omitempty"`
+}
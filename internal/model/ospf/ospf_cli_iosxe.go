@@ -0,0 +1,46 @@
+package ospf
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CLI returns the IOS-XE CLI lines of the OSPF processes
+func (v *CiscoIOSXENativeRouterOspf) CLI() []string {
+	var lines []string
+	for _, p := range v.CiscoIOSXEOspfRouterOspf.Ospf.ProcessID {
+		lines = append(lines, fmt.Sprintf("router ospf %v", p.ID))
+		if p.RouterID != "" {
+			lines = append(lines, fmt.Sprintf(" router-id %v", p.RouterID))
+		}
+		for _, i := range p.PassiveInterface.Interface {
+			lines = append(lines, fmt.Sprintf(" passive-interface %v", i))
+		}
+	}
+	return lines
+}
+
+// CLI returns the IOS-XE CLI lines of the OSPF interfaces
+func (v *CiscoIOSXENativeOspfInterfaces) CLI() []string {
+	var lines []string
+	types := make([]string, 0, len(v.Interface))
+	for t := range v.Interface {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		for _, i := range v.Interface[t] {
+			lines = append(lines, fmt.Sprintf("interface %v%v", t, i.Name))
+			ospf := i.IP.RouterOspf.Ospf
+			for _, p := range ospf.ProcessID {
+				for _, a := range p.Area {
+					lines = append(lines, fmt.Sprintf(" ip ospf %v area %v", p.ID, a.AreaID))
+				}
+			}
+			if ospf.NetworkType != nil && ospf.NetworkType.PointToPoint != nil {
+				lines = append(lines, " ip ospf network point-to-point")
+			}
+		}
+	}
+	return lines
+}
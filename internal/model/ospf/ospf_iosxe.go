@@ -0,0 +1,67 @@
+package ospf
+
+type CiscoIOSXENativeRouterOspf struct {
+	CiscoIOSXEOspfRouterOspf CiscoIOSXEOspfRouterOspf `json:"Cisco-IOS-XE-ospf:router-ospf"`
+}
+
+type CiscoIOSXEOspfRouterOspf struct {
+	Ospf CiscoIOSXEOspfOspf `json:"ospf"`
+}
+
+type CiscoIOSXEOspfOspf struct {
+	ProcessID []CiscoIOSXEOspfProcessID `json:"process-id,omitempty"`
+}
+
+type CiscoIOSXEOspfPassiveInterface struct {
+	Interface []string `json:"interface,omitempty"`
+}
+
+type CiscoIOSXEOspfProcessID struct {
+	ID               int                            `json:"id"`
+	RouterID         string                         `json:"router-id,omitempty"`
+	PassiveInterface CiscoIOSXEOspfPassiveInterface `json:"passive-interface,omitempty"`
+}
+
+// CiscoIOSXENativeOspfInterfaces is the OSPF config of interfaces per interface type (e.g. Loopback, TenGigabitEthernet)
+type CiscoIOSXENativeOspfInterfaces struct {
+	Interface map[string][]CiscoIOSXENativeOspfInterface `json:"Cisco-IOS-XE-native:interface"`
+}
+
+type CiscoIOSXENativeOspfInterface struct {
+	Name interface{}                     `json:"name"`
+	IP   CiscoIOSXENativeOspfInterfaceIP `json:"ip"`
+}
+
+type CiscoIOSXENativeOspfInterfaceIP struct {
+	RouterOspf CiscoIOSXEOspfInterfaceRouterOspf `json:"Cisco-IOS-XE-ospf:router-ospf"`
+}
+
+type CiscoIOSXEOspfInterfaceRouterOspf struct {
+	Ospf CiscoIOSXEOspfInterfaceOspf `json:"ospf"`
+}
+
+type CiscoIOSXEOspfInterfaceArea struct {
+	AreaID int `json:"area-id"`
+}
+
+type CiscoIOSXEOspfInterfaceProcessID struct {
+	ID   int                           `json:"id"`
+	Area []CiscoIOSXEOspfInterfaceArea `json:"area,omitempty"`
+}
+
+type CiscoIOSXEOspfInterfaceNetworkType struct {
+	PointToPoint []string `json:"point-to-point,omitempty"`
+}
+
+type CiscoIOSXEOspfInterfaceOspf struct {
+	ProcessID   []CiscoIOSXEOspfInterfaceProcessID  `json:"process-id,omitempty"`
+	NetworkType *CiscoIOSXEOspfInterfaceNetworkType `json:"network-type,omitempty"`
+}
+
+type CiscoIOSXEOspfProcessIDs struct {
+	ProcessID []CiscoIOSXEOspfProcessID `json:"Cisco-IOS-XE-ospf:process-id"`
+}
+
+type CiscoIOSXEOspfInterfaceRouterOspfs struct {
+	CiscoIOSXEOspfRouterOspf CiscoIOSXEOspfInterfaceRouterOspf `json:"Cisco-IOS-XE-ospf:router-ospf"`
+}
@@ -0,0 +1,55 @@
+package port_channel
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CLI returns the IOS-XE CLI lines of the Port-channel interfaces
+func (v *CiscoIOSXENativePortChannel) CLI() []string {
+	var lines []string
+	for _, i := range v.PortChannel {
+		lines = append(lines, fmt.Sprintf("interface Port-channel%v", i.Name))
+		if i.Description != "" {
+			lines = append(lines, fmt.Sprintf(" description %v", i.Description))
+		}
+		lines = append(lines, switchportCLI(i.Switchport))
+		if p := i.IP.PrimaryAddress(); p.Address != "" {
+			lines = append(lines, fmt.Sprintf(" ip address %v %v", p.Address, p.Mask))
+		}
+		if i.PortChannel != nil && i.PortChannel.MinLinks != 0 {
+			lines = append(lines, fmt.Sprintf(" port-channel min-links %v", i.PortChannel.MinLinks))
+		}
+	}
+	return lines
+}
+
+// CLI returns the IOS-XE CLI lines of the member interfaces
+func (v *CiscoIOSXENativePortChannelMembers) CLI() []string {
+	var lines []string
+	types := make([]string, 0, len(v.Interface))
+	for t := range v.Interface {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		for _, i := range v.Interface[t] {
+			lines = append(lines, fmt.Sprintf("interface %v%v", t, i.Name))
+			if i.Description != "" {
+				lines = append(lines, fmt.Sprintf(" description %v", i.Description))
+			}
+			lines = append(lines, switchportCLI(i.Switchport))
+			if i.ChannelGroup != nil {
+				lines = append(lines, fmt.Sprintf(" channel-group %v mode %v", i.ChannelGroup.Number, i.ChannelGroup.Mode))
+			}
+		}
+	}
+	return lines
+}
+
+func switchportCLI(conf CiscoIOSXENativeSwitchportConf) string {
+	if conf.Switchport {
+		return " switchport"
+	}
+	return " no switchport"
+}
@@ -0,0 +1,60 @@
+package port_channel
+
+// CiscoIOSXENativePortChannel is the Port-channel interface list
+type CiscoIOSXENativePortChannel struct {
+	PortChannel []CiscoIOSXENativePortChannelInterface `json:"Cisco-IOS-XE-native:Port-channel"`
+}
+
+type CiscoIOSXENativeSwitchportConf struct {
+	Switchport bool `json:"switchport"`
+}
+
+type CiscoIOSXENativePortChannelPrimary struct {
+	Address string `json:"address"`
+	Mask    string `json:"mask"`
+}
+
+type CiscoIOSXENativePortChannelAddress struct {
+	Primary CiscoIOSXENativePortChannelPrimary `json:"primary"`
+}
+
+type CiscoIOSXENativePortChannelIP struct {
+	Address *CiscoIOSXENativePortChannelAddress `json:"address,omitempty"`
+}
+
+type CiscoIOSXEEthernetPortChannel struct {
+	MinLinks int `json:"min-links,omitempty"`
+}
+
+type CiscoIOSXENativePortChannelInterface struct {
+	Name        int                            `json:"name"`
+	Description string                         `json:"description,omitempty"`
+	Switchport  CiscoIOSXENativeSwitchportConf `json:"switchport-conf"`
+	IP          CiscoIOSXENativePortChannelIP  `json:"ip"`
+	PortChannel *CiscoIOSXEEthernetPortChannel `json:"Cisco-IOS-XE-ethernet:port-channel,omitempty"`
+}
+
+// CiscoIOSXENativePortChannelMembers is the member interfaces per interface type (e.g. TenGigabitEthernet)
+type CiscoIOSXENativePortChannelMembers struct {
+	Interface map[string][]CiscoIOSXENativePortChannelMember `json:"Cisco-IOS-XE-native:interface"`
+}
+
+type CiscoIOSXEEthernetChannelGroup struct {
+	Number int    `json:"number"`
+	Mode   string `json:"mode"`
+}
+
+type CiscoIOSXENativePortChannelMember struct {
+	Name         interface{}                     `json:"name"`
+	Description  string                          `json:"description,omitempty"`
+	Switchport   CiscoIOSXENativeSwitchportConf  `json:"switchport-conf"`
+	ChannelGroup *CiscoIOSXEEthernetChannelGroup `json:"Cisco-IOS-XE-ethernet:channel-group,omitempty"`
+}
+
+// PrimaryAddress returns the primary address, empty without address
+func (ip *CiscoIOSXENativePortChannelIP) PrimaryAddress() CiscoIOSXENativePortChannelPrimary {
+	if ip.Address == nil {
+		return CiscoIOSXENativePortChannelPrimary{}
+	}
+	return ip.Address.Primary
+}
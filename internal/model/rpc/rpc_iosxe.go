@@ -0,0 +1,27 @@
+package rpc
+
+type CiscoIOSXERpcCopy struct {
+	Input CiscoIOSXERpcCopyInput `json:"Cisco-IOS-XE-rpc:input"`
+}
+
+type CiscoIOSXERpcCopyInput struct {
+	SourceDropNodeName      string `json:"source-drop-node-name"`
+	DestinationDropNodeName string `json:"destination-drop-node-name"`
+}
+
+type CiscoIOSXERpcDelete struct {
+	Input CiscoIOSXERpcDeleteInput `json:"Cisco-IOS-XE-rpc:input"`
+}
+
+type CiscoIOSXERpcDeleteInput struct {
+	FilenameDropNodeName string `json:"filename-drop-node-name"`
+}
+
+type CiscoIARollback struct {
+	Input CiscoIARollbackInput `json:"cisco-ia:input"`
+}
+
+type CiscoIARollbackInput struct {
+	TargetURL string `json:"target-url"`
+	Verbose   bool   `json:"verbose,omitempty"`
+}
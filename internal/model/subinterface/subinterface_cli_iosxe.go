@@ -0,0 +1,49 @@
+package subinterface
+
+import "fmt"
+
+// CLI returns the IOS-XE CLI lines of the ethernet and Port-channel (sub)interfaces
+func (v *CiscoIOSXENativeEthernet) CLI() []string {
+	var lines []string
+	for _, t := range []struct {
+		name       string
+		interfaces []CiscoIOSXENativeEthernetInterface
+	}{
+		{"GigabitEthernet", v.One},
+		{"TenGigabitEthernet", v.Ten},
+		{"TwentyFiveGigE", v.TwentyFive},
+		{"FortyGigabitEthernet", v.Forty},
+		{"HundredGigE", v.Hundred},
+		{"FourHundredGigE", v.FourHundred},
+		{"Port-channel", v.PortChannel},
+	} {
+		for _, i := range t.interfaces {
+			lines = append(lines, fmt.Sprintf("interface %v%v", t.name, i.Name))
+			if i.Description != "" {
+				lines = append(lines, fmt.Sprintf(" description %v", i.Description))
+			}
+			if i.Encapsulation.Dot1Q.VlanID != 0 {
+				lines = append(lines, fmt.Sprintf(" encapsulation dot1Q %v", i.Encapsulation.Dot1Q.VlanID))
+			}
+			if i.Switchport != nil && !i.Switchport.Switchport {
+				lines = append(lines, " no switchport")
+			}
+			if i.Mtu != 0 {
+				lines = append(lines, fmt.Sprintf(" mtu %v", i.Mtu))
+			}
+			if i.Vrf.Forwarding != "" {
+				lines = append(lines, fmt.Sprintf(" vrf forwarding %v", i.Vrf.Forwarding))
+			}
+			if p := i.IP.PrimaryAddress(); p.Address != "" {
+				lines = append(lines, fmt.Sprintf(" ip address %v %v", p.Address, p.Mask))
+			}
+			if i.IP.Unnumbered != "" {
+				lines = append(lines, fmt.Sprintf(" ip unnumbered %v", i.IP.Unnumbered))
+			}
+			if i.IP.Pim != nil && i.IP.Pim.CiscoIOSXEMulticastPimModeChoiceCfg.SparseMode != nil {
+				lines = append(lines, " ip pim sparse-mode")
+			}
+		}
+	}
+	return lines
+}
@@ -7,6 +7,7 @@ type CiscoIOSXENativeEthernet struct {
 	TwentyFive  []CiscoIOSXENativeEthernetInterface `json:"Cisco-IOS-XE-native:TwentyFiveGigE,omitempty"`
 	Ten         []CiscoIOSXENativeEthernetInterface `json:"Cisco-IOS-XE-native:TenGigabitEthernet,omitempty"`
 	One         []CiscoIOSXENativeEthernetInterface `json:"Cisco-IOS-XE-native:GigabitEthernet,omitempty"`
+	PortChannel []CiscoIOSXENativeEthernetInterface `json:"Cisco-IOS-XE-native:Port-channel,omitempty"`
 }
 type CiscoIOSXENativeEthernetInterfaceDot1Q struct {
 	VlanID int `json:"vlan-id,omitempty"`
@@ -24,13 +25,34 @@ type CiscoIOSXENativeEthernetInterfacePrimary struct {
 type CiscoIOSXENativeEthernetInterfaceAddress struct {
 	Primary CiscoIOSXENativeEthernetInterfacePrimary `json:"primary,omitempty"`
 }
+type CiscoIOSXEMulticastPimModeChoiceCfg struct {
+	SparseMode interface{} `json:"sparse-mode,omitempty"`
+}
+type CiscoIOSXENativeEthernetInterfacePim struct {
+	CiscoIOSXEMulticastPimModeChoiceCfg CiscoIOSXEMulticastPimModeChoiceCfg `json:"Cisco-IOS-XE-multicast:pim-mode-choice-cfg,omitempty"`
+}
 type CiscoIOSXENativeEthernetInterfaceIP struct {
-	Address CiscoIOSXENativeEthernetInterfaceAddress `json:"address,omitempty"`
+	Address    *CiscoIOSXENativeEthernetInterfaceAddress `json:"address,omitempty"`
+	Unnumbered string                                    `json:"unnumbered,omitempty"`
+	Pim        *CiscoIOSXENativeEthernetInterfacePim     `json:"pim,omitempty"`
+}
+type CiscoIOSXENativeEthernetInterfaceSwitchportConf struct {
+	Switchport bool `json:"switchport"`
 }
 type CiscoIOSXENativeEthernetInterface struct {
-	Name          string                                         `json:"name,omitempty"`
-	Description   string                                         `json:"description,omitempty"`
-	Encapsulation CiscoIOSXENativeEthernetInterfaceEncapsulation `json:"encapsulation,omitempty"`
-	Vrf           CiscoIOSXENativeEthernetInterfaceVrf           `json:"vrf,omitempty"`
-	IP            CiscoIOSXENativeEthernetInterfaceIP            `json:"ip,omitempty"`
+	Name          string                                           `json:"name,omitempty"`
+	Description   string                                           `json:"description,omitempty"`
+	Encapsulation CiscoIOSXENativeEthernetInterfaceEncapsulation   `json:"encapsulation,omitempty"`
+	Mtu           int                                              `json:"mtu,omitempty"`
+	Switchport    *CiscoIOSXENativeEthernetInterfaceSwitchportConf `json:"switchport-conf,omitempty"`
+	Vrf           CiscoIOSXENativeEthernetInterfaceVrf             `json:"vrf,omitempty"`
+	IP            CiscoIOSXENativeEthernetInterfaceIP              `json:"ip,omitempty"`
+}
+
+// PrimaryAddress returns the primary address, empty without address
+func (ip *CiscoIOSXENativeEthernetInterfaceIP) PrimaryAddress() CiscoIOSXENativeEthernetInterfacePrimary {
+	if ip.Address == nil {
+		return CiscoIOSXENativeEthernetInterfacePrimary{}
+	}
+	return ip.Address.Primary
 }
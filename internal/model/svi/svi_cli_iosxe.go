@@ -0,0 +1,46 @@
+package svi
+
+import "fmt"
+
+// CLI returns the IOS-XE CLI lines of the vlan interfaces
+func (v *CiscoIOSXENativeSvis) CLI() []string {
+	var lines []string
+	for _, s := range v.CiscoIOSXENativeVlan {
+		lines = append(lines, fmt.Sprintf("interface Vlan%v", s.Name))
+		if s.Description != "" {
+			lines = append(lines, fmt.Sprintf(" description %v", s.Description))
+		}
+		if s.Vrf != nil {
+			lines = append(lines, fmt.Sprintf(" vrf forwarding %v", s.Vrf.Forwarding))
+		}
+		switch {
+		case s.IP.Address != nil:
+			lines = append(lines, fmt.Sprintf(" ip address %v %v", s.IP.Address.Primary.Address, s.IP.Address.Primary.Mask))
+		case s.IP.Unnumbered != "":
+			lines = append(lines, fmt.Sprintf(" ip unnumbered %v", s.IP.Unnumbered))
+		}
+		if !s.AutoState {
+			lines = append(lines, " no autostate")
+		}
+	}
+	return lines
+}
+
+// CLI returns the IOS-XE CLI lines of the DHCP relay of the vlan interfaces
+func (v *CiscoIOSXENativeVlanDhcpHelper) CLI() []string {
+	var lines []string
+	for _, s := range v.CiscoIOSXENativeVlan {
+		lines = append(lines, fmt.Sprintf("interface Vlan%v", s.Name))
+		if src := s.IP.Dhcp.CiscoIOSXEDhcpRelay.SourceInterface; src != "" {
+			lines = append(lines, fmt.Sprintf(" ip dhcp relay source-interface %v", src))
+		}
+		for _, h := range s.IP.HelperAddress {
+			if h.Global != nil {
+				lines = append(lines, fmt.Sprintf(" ip helper-address global %v", h.Address))
+			} else {
+				lines = append(lines, fmt.Sprintf(" ip helper-address %v", h.Address))
+			}
+		}
+	}
+	return lines
+}
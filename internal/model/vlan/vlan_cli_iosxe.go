@@ -0,0 +1,24 @@
+package vlan
+
+import "fmt"
+
+// CLI returns the IOS-XE CLI lines of the vlan configurations and vlans
+func (v *CiscoIOSXENativeVlans) CLI() []string {
+	var lines []string
+	for _, e := range v.CiscoIOSXENativeVlan.CiscoIOSXEVlanConfigurationEntry {
+		lines = append(lines, fmt.Sprintf("vlan configuration %v", e.VlanID))
+		switch {
+		case e.Member.EvpnInstance != nil:
+			lines = append(lines, fmt.Sprintf(" member evpn-instance %v vni %v", e.Member.EvpnInstance.EvpnInstance, e.Member.EvpnInstance.Vni))
+		case e.Member.Vni != 0:
+			lines = append(lines, fmt.Sprintf(" member vni %v", e.Member.Vni))
+		}
+	}
+	for _, l := range v.CiscoIOSXENativeVlan.CiscoIOSXEVlanVlanList {
+		lines = append(lines, fmt.Sprintf("vlan %v", l.ID))
+		if l.Name != "" {
+			lines = append(lines, fmt.Sprintf(" name %v", l.Name))
+		}
+	}
+	return lines
+}
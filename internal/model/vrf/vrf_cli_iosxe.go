@@ -0,0 +1,38 @@
+package vrf
+
+import "fmt"
+
+// CLI returns the IOS-XE CLI lines of the VRF definitions
+func (v *CiscoIOSXENativeVrf) CLI() []string {
+	var lines []string
+	for _, def := range v.CiscoIOSXENativeDefinition {
+		lines = append(lines, fmt.Sprintf("vrf definition %v", def.Name))
+		if def.Rd != "" {
+			lines = append(lines, fmt.Sprintf(" rd %v", def.Rd))
+		}
+		lines = append(lines, addressFamilyCLI("ipv4", def.AddressFamily.Ipv4.RouteTarget)...)
+		lines = append(lines, addressFamilyCLI("ipv6", def.AddressFamily.Ipv6.RouteTarget)...)
+	}
+	return lines
+}
+
+func addressFamilyCLI(af string, rt CiscoIOSXENativeDefinitionRouteTarget) []string {
+	var lines []string
+	for _, t := range rt.ExportRouteTarget.WithoutStitching {
+		lines = append(lines, fmt.Sprintf("  route-target export %v", t.AsnIP))
+	}
+	for _, t := range rt.ImportRouteTarget.WithoutStitching {
+		lines = append(lines, fmt.Sprintf("  route-target import %v", t.AsnIP))
+	}
+	for _, t := range rt.ExportRouteTarget.WithStitching {
+		lines = append(lines, fmt.Sprintf("  route-target export %v stitching", t.AsnIP))
+	}
+	for _, t := range rt.ImportRouteTarget.WithStitching {
+		lines = append(lines, fmt.Sprintf("  route-target import %v stitching", t.AsnIP))
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	lines = append([]string{fmt.Sprintf(" address-family %v", af)}, lines...)
+	return append(lines, " exit-address-family")
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// TestAccCassetteReplay records an apply against the simulator and replays it with the simulator stopped
+func TestAccCassetteReplay(t *testing.T) {
+	f := newTestAccFabric(t, 1, 1)
+	dir := t.TempDir()
+	cassette := filepath.Join(dir, strings.ReplaceAll(f.Leafs[0].Host(), ":", "_")+".json")
+
+	f.Settings = fmt.Sprintf("  cassette_mode = \"record\"\n  cassette_dir  = %q", dir)
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnLoopbackConfig(f, "100.119.11.11"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_loopback.test", "ipv4_address", "100.119.11.11"),
+					testAccCheckCassette(cassette, `"method": "PATCH"`, `100.119.11.11`, `"status": 204`),
+				),
+			},
+		},
+	})
+
+	for _, d := range f.Devices() {
+		d.Close()
+	}
+	f.Settings = fmt.Sprintf("  cassette_mode = \"replay\"\n  cassette_dir  = %q", dir)
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnLoopbackConfig(f, "100.119.11.11"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_loopback.test", "interface_name", "Loopback100"),
+					resource.TestCheckResourceAttr("ciscoevpn_loopback.test", "ipv4_address", "100.119.11.11"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckCassette checks the cassette contains the values and no credentials
+func testAccCheckCassette(file string, values ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if strings.Contains(string(b), testAccPassword) {
+			return fmt.Errorf("%v contains the password", file)
+		}
+		for _, v := range values {
+			if !strings.Contains(string(b), v) {
+				return fmt.Errorf("%v doesn't contain %v", file, v)
+			}
+		}
+		return nil
+	}
+}
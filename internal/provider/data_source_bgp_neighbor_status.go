@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/bgp"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/iosxe"
+)
+
+func dataSourceCiscoBgpNeighborStatus() *schema.Resource {
+	return &schema.Resource{
+		Description: "Cisco BGP neighbor session state and prefix counts of a host or the devices of a role",
+		ReadContext: dataSourceCiscoBgpNeighborStatusRead,
+		Schema: dataSourceSchema(map[string]*schema.Schema{
+			"established": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "All neighbors are established, false without neighbors.",
+			},
+			"neighbors": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"neighbor_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"afi_safi": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vrf": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"remote_as": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"session_state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"up_time": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"installed_prefixes": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"prefixes_received": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"prefixes_sent": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		}, map[string]*schema.Schema{
+			"afi_safi": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only neighbors of the address family, e.g. `l2vpn-evpn`.",
+			},
+			"vrf": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only neighbors of the VRF, e.g. `default`.",
+			},
+		}),
+	}
+}
+
+func dataSourceCiscoBgpNeighborStatusRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco BGP NEIGHBOR STATUS DATA SOURCE READ")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	bodies, hosts, err := c.readTarget(ctx, d, "/data/Cisco-IOS-XE-bgp-oper:bgp-state-data/neighbors")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	afiSafi := d.Get("afi_safi").(string)
+	vrf := d.Get("vrf").(string)
+	devices := []interface{}{}
+	for _, host := range hosts {
+		data := &bgp.CiscoIOSXEBgpOperNeighbors{}
+		if err = unmarshalBody(bodies[host], data); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		established := true
+		neighbors := []interface{}{}
+		for _, n := range data.Neighbors.Neighbor {
+			if (afiSafi != "" && n.AfiSafi != afiSafi) || (vrf != "" && n.VrfName != vrf) {
+				continue
+			}
+			established = established && operUp(n.SessionState)
+			neighbors = append(neighbors, map[string]interface{}{
+				"neighbor_id":        n.NeighborID,
+				"afi_safi":           n.AfiSafi,
+				"vrf":                n.VrfName,
+				"remote_as":          n.As,
+				"session_state":      n.SessionState,
+				"up_time":            n.UpTime,
+				"installed_prefixes": n.InstalledPrefixes,
+				"prefixes_received":  n.PrefixActivity.Received.TotalPrefixes,
+				"prefixes_sent":      n.PrefixActivity.Sent.TotalPrefixes,
+			})
+		}
+		devices = append(devices, map[string]interface{}{
+			"host":        host,
+			"established": established && len(neighbors) > 0,
+			"neighbors":   neighbors,
+		})
+	}
+
+	if err = d.Set("devices", devices); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(fmt.Sprintf("bgp_neighbor_status_%v", dataSourceTarget(d)))
+	return diags
+}
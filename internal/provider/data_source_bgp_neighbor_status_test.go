@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnDataSourceBgpNeighborStatus(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	// only leaf0 has operational data, the read of leaf1 returns a 404
+	if err := f.Leafs[0].Set("Cisco-IOS-XE-bgp-oper:bgp-state-data/neighbors", `{"Cisco-IOS-XE-bgp-oper:neighbors":{"neighbor":[
+		{"afi-safi":"l2vpn-evpn","vrf-name":"default","neighbor-id":"10.0.0.1","as":65001,"up-time":"1d2h","session-state":"fsm-established","installed-prefixes":4,
+			"prefix-activity":{"sent":{"total-prefixes":3},"received":{"total-prefixes":5}}},
+		{"afi-safi":"ipv4-unicast","vrf-name":"green","neighbor-id":"192.168.1.1","as":65101,"session-state":"fsm-idle"}]}}`); err != nil {
+		t.Fatal(err)
+	}
+	leaf0 := testAccDataSourceDevice(f.Leafs, f.Leafs[0])
+	leaf1 := testAccDataSourceDevice(f.Leafs, f.Leafs[1])
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: f.Config() + `
+data "ciscoevpn_bgp_neighbor_status" "test" {
+  role = "leafs"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbor_status.test", "devices.#", "2"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbor_status.test", leaf0+".host", f.Leafs[0].Host()),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbor_status.test", leaf0+".established", "false"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbor_status.test", leaf0+".neighbors.#", "2"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbor_status.test", leaf0+".neighbors.1.neighbor_id", "192.168.1.1"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbor_status.test", leaf0+".neighbors.1.vrf", "green"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbor_status.test", leaf0+".neighbors.1.session_state", "fsm-idle"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbor_status.test", leaf1+".host", f.Leafs[1].Host()),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbor_status.test", leaf1+".established", "false"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbor_status.test", leaf1+".neighbors.#", "0"),
+				),
+			},
+			{
+				Config: f.Config() + fmt.Sprintf(`
+data "ciscoevpn_bgp_neighbor_status" "test" {
+  host     = %q
+  afi_safi = "l2vpn-evpn"
+}
+`, f.Leafs[0].Host()),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbor_status.test", "devices.#", "1"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbor_status.test", "devices.0.established", "true"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbor_status.test", "devices.0.neighbors.#", "1"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbor_status.test", "devices.0.neighbors.0.neighbor_id", "10.0.0.1"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbor_status.test", "devices.0.neighbors.0.afi_safi", "l2vpn-evpn"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbor_status.test", "devices.0.neighbors.0.vrf", "default"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbor_status.test", "devices.0.neighbors.0.remote_as", "65001"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbor_status.test", "devices.0.neighbors.0.session_state", "fsm-established"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbor_status.test", "devices.0.neighbors.0.up_time", "1d2h"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbor_status.test", "devices.0.neighbors.0.installed_prefixes", "4"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbor_status.test", "devices.0.neighbors.0.prefixes_received", "5"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbor_status.test", "devices.0.neighbors.0.prefixes_sent", "3"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/bgp"
+)
+
+func dataSourceCiscoNativeBgpNeighbors() *schema.Resource {
+	return &schema.Resource{
+		Description: "Cisco BGP Neighbors of a host or the devices of a role",
+		ReadContext: dataSourceCiscoNativeBgpNeighborsRead,
+		Schema: dataSourceSchema(map[string]*schema.Schema{
+			"router_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"neighbors": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"remote_as": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"update_source": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"l2vpn_evpn": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"send_community": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"route_reflector_client": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+		}, map[string]*schema.Schema{
+			"bgp_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+		}),
+	}
+}
+
+func dataSourceCiscoNativeBgpNeighborsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco BGP NEIGHBORS DATA SOURCE READ")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	id := d.Get("bgp_id").(int)
+	bodies, hosts, err := c.readTarget(ctx, d, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v", id))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	devices := []interface{}{}
+	for _, host := range hosts {
+		data := &bgp.CiscoIOSXEBgpNeighbors{}
+		if err = unmarshalBody(bodies[host], data); err != nil || len(data.CiscoIOSXEBgpBgp) == 0 {
+			return diag.Errorf("BGP %v not found on: %v", id, host)
+		}
+		system := data.CiscoIOSXEBgpBgp[0]
+		evpnNeighbors := make(map[string]bgp.CiscoIOSXEBgpNeighborsEvpnNeighbor)
+		for _, af := range system.AddressFamily.NoVrf.L2Vpn {
+			if af.AfName == "evpn" {
+				for _, n := range af.L2VpnEvpn.Neighbor {
+					evpnNeighbors[n.ID] = n
+				}
+			}
+		}
+		neighbors := []interface{}{}
+		for _, n := range system.Neighbor {
+			evpnNeighbor, ok := evpnNeighbors[n.ID]
+			neighbors = append(neighbors, map[string]interface{}{
+				"id":                     n.ID,
+				"remote_as":              n.RemoteAs,
+				"update_source":          fmt.Sprintf("Loopback%v", n.UpdateSource.Interface.Loopback),
+				"l2vpn_evpn":             ok && evpnNeighbor.Activate != nil,
+				"send_community":         evpnNeighbor.SendCommunity.SendCommunityWhere,
+				"route_reflector_client": evpnNeighbor.RouteReflectorClient != nil,
+			})
+		}
+		routerID := system.Bgp.RouterID.IP
+		if system.Bgp.RouterID.Interface.Loopback != 0 {
+			routerID = fmt.Sprintf("Loopback%v", system.Bgp.RouterID.Interface.Loopback)
+		}
+		devices = append(devices, map[string]interface{}{
+			"host":      host,
+			"router_id": routerID,
+			"neighbors": neighbors,
+		})
+	}
+
+	if err = d.Set("devices", devices); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(fmt.Sprintf("bgp_neighbors_%v_%v", dataSourceTarget(d), id))
+	return diags
+}
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnDataSourceBgpNeighbors(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	// only leaf0 has BGP, the read of leaf1 returns a 404
+	if err := f.Leafs[0].Set("Cisco-IOS-XE-native:native/router/bgp=65001", `{"Cisco-IOS-XE-bgp:bgp":[{
+		"id":65001,"bgp":{"default":{"ipv4-unicast":false},"router-id":{"interface":{"Loopback":100}}},
+		"neighbor":[
+			{"id":"10.0.0.1","remote-as":65001,"update-source":{"interface":{"Loopback":100}}},
+			{"id":"10.0.0.2","remote-as":65001,"update-source":{"interface":{"Loopback":100}}}],
+		"address-family":{"no-vrf":{"l2vpn":[{"af-name":"evpn","l2vpn-evpn":{"neighbor":[
+			{"id":"10.0.0.1","activate":[null],"send-community":{"send-community-where":"both"}}]}}]}}}]}`); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: f.Config() + fmt.Sprintf(`
+data "ciscoevpn_bgp_neighbors" "test" {
+  host   = %q
+  bgp_id = 65001
+}
+`, f.Leafs[0].Host()),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbors.test", "devices.#", "1"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbors.test", "devices.0.host", f.Leafs[0].Host()),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbors.test", "devices.0.router_id", "Loopback100"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbors.test", "devices.0.neighbors.#", "2"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbors.test", "devices.0.neighbors.0.id", "10.0.0.1"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbors.test", "devices.0.neighbors.0.remote_as", "65001"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbors.test", "devices.0.neighbors.0.update_source", "Loopback100"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbors.test", "devices.0.neighbors.0.l2vpn_evpn", "true"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbors.test", "devices.0.neighbors.0.send_community", "both"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbors.test", "devices.0.neighbors.0.route_reflector_client", "false"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbors.test", "devices.0.neighbors.1.id", "10.0.0.2"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_bgp_neighbors.test", "devices.0.neighbors.1.l2vpn_evpn", "false"),
+				),
+			},
+			{
+				Config: f.Config() + `
+data "ciscoevpn_bgp_neighbors" "test" {
+  role   = "leafs"
+  bgp_id = 65001
+}
+`,
+				ExpectError: regexp.MustCompile(regexp.QuoteMeta("BGP 65001 not found on: " + f.Leafs[1].Host())),
+			},
+		},
+	})
+}
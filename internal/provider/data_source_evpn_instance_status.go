@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/evpn_instance"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/iosxe"
+)
+
+func dataSourceCiscoEvpnInstanceStatus() *schema.Resource {
+	return &schema.Resource{
+		Description: "Cisco EVPN instance status of a host or the devices of a role",
+		ReadContext: dataSourceCiscoEvpnInstanceStatusRead,
+		Schema: dataSourceSchema(map[string]*schema.Schema{
+			"instances_up": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "All EVPN instances are up, false without instances.",
+			},
+			"instances": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"instance_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"encapsulation": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"rd": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vlan": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		}, map[string]*schema.Schema{
+			"instance_id": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Only the EVPN instance with the ID.",
+			},
+		}),
+	}
+}
+
+func dataSourceCiscoEvpnInstanceStatusRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco EVPN INSTANCE STATUS DATA SOURCE READ")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	bodies, hosts, err := c.readTarget(ctx, d, "/data/Cisco-IOS-XE-evpn-oper:evpn-oper-data/evpn-instance")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	id := d.Get("instance_id").(int)
+	devices := []interface{}{}
+	for _, host := range hosts {
+		data := &evpn_instance.CiscoIOSXEEvpnOperInstances{}
+		if err = unmarshalBody(bodies[host], data); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		up := true
+		instances := []interface{}{}
+		for _, i := range data.EvpnInstance {
+			if id != 0 && i.Evi != id {
+				continue
+			}
+			up = up && operUp(i.State)
+			instances = append(instances, map[string]interface{}{
+				"instance_id":   i.Evi,
+				"type":          i.Type,
+				"encapsulation": i.Encapsulation,
+				"state":         i.State,
+				"rd":            i.Rd,
+				"vlan":          i.Vlan,
+			})
+		}
+		devices = append(devices, map[string]interface{}{
+			"host":         host,
+			"instances_up": up && len(instances) > 0,
+			"instances":    instances,
+		})
+	}
+
+	if err = d.Set("devices", devices); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(fmt.Sprintf("evpn_instance_status_%v", dataSourceTarget(d)))
+	return diags
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnDataSourceEvpnInstanceStatus(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	// only leaf0 has operational data, the read of leaf1 returns a 404
+	if err := f.Leafs[0].Set("Cisco-IOS-XE-evpn-oper:evpn-oper-data", `{"Cisco-IOS-XE-evpn-oper:evpn-oper-data":{"evpn-instance":[
+		{"evi":101,"type":"vlan-based","encapsulation":"vxlan","state":"evpn-instance-up","rd":"10.0.0.11:101","vlan":101},
+		{"evi":102,"type":"vlan-based","encapsulation":"vxlan","state":"evpn-instance-down","rd":"10.0.0.11:102","vlan":102}]}}`); err != nil {
+		t.Fatal(err)
+	}
+	leaf0 := testAccDataSourceDevice(f.Leafs, f.Leafs[0])
+	leaf1 := testAccDataSourceDevice(f.Leafs, f.Leafs[1])
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: f.Config() + `
+data "ciscoevpn_evpn_instance_status" "test" {
+  role = "leafs"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ciscoevpn_evpn_instance_status.test", "devices.#", "2"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_evpn_instance_status.test", leaf0+".host", f.Leafs[0].Host()),
+					resource.TestCheckResourceAttr("data.ciscoevpn_evpn_instance_status.test", leaf0+".instances_up", "false"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_evpn_instance_status.test", leaf0+".instances.#", "2"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_evpn_instance_status.test", leaf0+".instances.1.instance_id", "102"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_evpn_instance_status.test", leaf0+".instances.1.state", "evpn-instance-down"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_evpn_instance_status.test", leaf1+".host", f.Leafs[1].Host()),
+					resource.TestCheckResourceAttr("data.ciscoevpn_evpn_instance_status.test", leaf1+".instances_up", "false"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_evpn_instance_status.test", leaf1+".instances.#", "0"),
+				),
+			},
+			{
+				Config: f.Config() + fmt.Sprintf(`
+data "ciscoevpn_evpn_instance_status" "test" {
+  host        = %q
+  instance_id = 101
+}
+`, f.Leafs[0].Host()),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ciscoevpn_evpn_instance_status.test", "devices.#", "1"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_evpn_instance_status.test", "devices.0.instances_up", "true"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_evpn_instance_status.test", "devices.0.instances.#", "1"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_evpn_instance_status.test", "devices.0.instances.0.instance_id", "101"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_evpn_instance_status.test", "devices.0.instances.0.type", "vlan-based"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_evpn_instance_status.test", "devices.0.instances.0.encapsulation", "vxlan"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_evpn_instance_status.test", "devices.0.instances.0.state", "evpn-instance-up"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_evpn_instance_status.test", "devices.0.instances.0.rd", "10.0.0.11:101"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_evpn_instance_status.test", "devices.0.instances.0.vlan", "101"),
+				),
+			},
+		},
+	})
+}
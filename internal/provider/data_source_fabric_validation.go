@@ -0,0 +1,240 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCiscoFabricValidation() *schema.Resource {
+	return &schema.Resource{
+		Description: "Offline cross validation of the VLAN, VNI, EVPN instance and VRF references of the fabric. " +
+			"Fails the plan without any request to the devices. References are only checked against the kinds of blocks which are set.",
+		ReadContext: dataSourceCiscoFabricValidationRead,
+		Schema: map[string]*schema.Schema{
+			"vlan": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The `ciscoevpn_vlan` resources.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"vlan_id": {
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+						"vni": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+						"evpn_instance": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"nve": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The `ciscoevpn_nve` resources.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"vni": {
+							Type:     schema.TypeMap,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"vni_ipv4_multicast_group": {
+							Type:     schema.TypeMap,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"vni_ingress_replication": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeInt},
+						},
+					},
+				},
+			},
+			"evpn_instance": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The `ciscoevpn_evpn_instance` resources.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"instance_id": {
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+					},
+				},
+			},
+			"vrf": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The `ciscoevpn_vrf` resources.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+					},
+				},
+			},
+			"svi": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The `ciscoevpn_svi` resources.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"svi_id": {
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+						"vrf": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceCiscoFabricValidationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco FABRIC VALIDATION DATA SOURCE READ")
+	var diags diag.Diagnostics
+
+	for _, msg := range fabricValidate(d) {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Fabric validation failed",
+			Detail:   msg,
+		})
+	}
+	if diags.HasError() {
+		return diags
+	}
+	d.SetId("fabric_validation")
+	return diags
+}
+
+// fabricValidate returns the broken references between the resources of the fabric
+func fabricValidate(d *schema.ResourceData) []string {
+	var errs []string
+	vlans := d.Get("vlan").([]interface{})
+	nves := d.Get("nve").([]interface{})
+	instances := d.Get("evpn_instance").([]interface{})
+	vrfs := d.Get("vrf").([]interface{})
+	svis := d.Get("svi").([]interface{})
+
+	vlanIDs := make(map[int]bool)
+	vlanVnis := make(map[int]int)
+	for _, raw := range vlans {
+		vlan := raw.(map[string]interface{})
+		id := vlan["vlan_id"].(int)
+		if vlanIDs[id] {
+			errs = append(errs, fmt.Sprintf("VLAN %v is defined more than once", id))
+		}
+		vlanIDs[id] = true
+		if vni := vlan["vni"].(int); vni != 0 {
+			if other, ok := vlanVnis[vni]; ok {
+				errs = append(errs, fmt.Sprintf("VNI %v is mapped to both VLAN %v and VLAN %v", vni, other, id))
+			}
+			vlanVnis[vni] = id
+		}
+	}
+
+	instanceIDs := make(map[int]bool)
+	for _, raw := range instances {
+		instanceIDs[raw.(map[string]interface{})["instance_id"].(int)] = true
+	}
+	vrfNames := make(map[string]bool)
+	for _, raw := range vrfs {
+		vrfNames[raw.(map[string]interface{})["name"].(string)] = true
+	}
+
+	// VNIs of the NVE interfaces, with the NVE setting which uses it
+	nveVnis := make(map[int]string)
+	for _, raw := range nves {
+		nve := raw.(map[string]interface{})
+		vniMap := nve["vni"].(map[string]interface{})
+		for _, vrf := range sortedKeys(vniMap) {
+			vni := vniMap[vrf]
+			for _, id := range vlanRange(vni.(string)) {
+				nveVnis[id.(int)] = fmt.Sprintf("vni (VRF %v)", vrf)
+			}
+			if len(vrfs) > 0 && !vrfNames[vrf] {
+				errs = append(errs, fmt.Sprintf("ciscoevpn_nve vni references VRF %q which isn't a ciscoevpn_vrf", vrf))
+			}
+		}
+		groups := nve["vni_ipv4_multicast_group"].(map[string]interface{})
+		for _, group := range sortedKeys(groups) {
+			vni := groups[group]
+			for _, id := range vlanRange(vni.(string)) {
+				if _, ok := nveVnis[id.(int)]; ok {
+					errs = append(errs, fmt.Sprintf("VNI %v in ciscoevpn_nve vni_ipv4_multicast_group %v is already used in %v", id, group, nveVnis[id.(int)]))
+				}
+				nveVnis[id.(int)] = fmt.Sprintf("vni_ipv4_multicast_group (%v)", group)
+			}
+		}
+		for _, id := range nve["vni_ingress_replication"].([]interface{}) {
+			if _, ok := nveVnis[id.(int)]; ok {
+				errs = append(errs, fmt.Sprintf("VNI %v in ciscoevpn_nve vni_ingress_replication is already used in %v", id, nveVnis[id.(int)]))
+			}
+			nveVnis[id.(int)] = "vni_ingress_replication"
+		}
+	}
+
+	for _, raw := range vlans {
+		vlan := raw.(map[string]interface{})
+		id := vlan["vlan_id"].(int)
+		vni := vlan["vni"].(int)
+		if _, ok := nveVnis[vni]; len(nves) > 0 && vni != 0 && !ok {
+			errs = append(errs, fmt.Sprintf("VLAN %v VNI %v is not in ciscoevpn_nve vni, vni_ipv4_multicast_group or vni_ingress_replication", id, vni))
+		}
+		if instance := vlan["evpn_instance"].(int); len(instances) > 0 && instance != 0 && !instanceIDs[instance] {
+			errs = append(errs, fmt.Sprintf("VLAN %v references EVPN instance %v which isn't a ciscoevpn_evpn_instance", id, instance))
+		}
+	}
+	if len(vlans) > 0 {
+		ids := make([]int, 0, len(nveVnis))
+		for vni := range nveVnis {
+			ids = append(ids, vni)
+		}
+		sort.Ints(ids)
+		for _, vni := range ids {
+			if _, ok := vlanVnis[vni]; !ok {
+				errs = append(errs, fmt.Sprintf("VNI %v in ciscoevpn_nve %v isn't mapped to a ciscoevpn_vlan", vni, nveVnis[vni]))
+			}
+		}
+	}
+
+	for _, raw := range svis {
+		svi := raw.(map[string]interface{})
+		id := svi["svi_id"].(int)
+		if len(vlans) > 0 && !vlanIDs[id] {
+			errs = append(errs, fmt.Sprintf("SVI %v has no ciscoevpn_vlan %v", id, id))
+		}
+		if vrf := svi["vrf"].(string); len(vrfs) > 0 && vrf != "" && !vrfNames[vrf] {
+			errs = append(errs, fmt.Sprintf("SVI %v references VRF %q which isn't a ciscoevpn_vrf", id, vrf))
+		}
+	}
+	return errs
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestFabricValidate(t *testing.T) {
+	vlan := func(id, vni, instance int) interface{} {
+		return map[string]interface{}{"vlan_id": id, "vni": vni, "evpn_instance": instance}
+	}
+	instances := []interface{}{
+		map[string]interface{}{"instance_id": 101},
+		map[string]interface{}{"instance_id": 102},
+	}
+	vrfs := []interface{}{
+		map[string]interface{}{"name": "green"},
+	}
+	nve := func(vni map[string]interface{}, groups map[string]interface{}, ingress ...interface{}) []interface{} {
+		return []interface{}{map[string]interface{}{
+			"vni":                      vni,
+			"vni_ipv4_multicast_group": groups,
+			"vni_ingress_replication":  ingress,
+		}}
+	}
+
+	tests := []struct {
+		name string
+		raw  map[string]interface{}
+		errs []string
+	}{
+		{
+			name: "valid",
+			raw: map[string]interface{}{
+				"vlan":          []interface{}{vlan(101, 10101, 101), vlan(102, 10102, 102), vlan(901, 50901, 0)},
+				"nve":           nve(map[string]interface{}{"green": "50901"}, map[string]interface{}{"225.0.0.101": "10101"}, 10102),
+				"evpn_instance": instances,
+				"vrf":           vrfs,
+				"svi": []interface{}{
+					map[string]interface{}{"svi_id": 101, "vrf": "green"},
+				},
+			},
+		},
+		{
+			name: "duplicate VLAN",
+			raw: map[string]interface{}{
+				"vlan": []interface{}{vlan(101, 0, 0), vlan(101, 0, 0)},
+			},
+			errs: []string{"VLAN 101 is defined more than once"},
+		},
+		{
+			name: "VNI mapped to two VLANs",
+			raw: map[string]interface{}{
+				"vlan": []interface{}{vlan(101, 10101, 0), vlan(102, 10101, 0)},
+			},
+			errs: []string{"VNI 10101 is mapped to both VLAN 101 and VLAN 102"},
+		},
+		{
+			name: "VNI missing from the NVE",
+			raw: map[string]interface{}{
+				"vlan": []interface{}{vlan(101, 10101, 0), vlan(102, 10102, 0)},
+				"nve":  nve(nil, nil, 10101),
+			},
+			errs: []string{"VLAN 102 VNI 10102 is not in ciscoevpn_nve vni, vni_ipv4_multicast_group or vni_ingress_replication"},
+		},
+		{
+			name: "NVE VNI without VLAN",
+			raw: map[string]interface{}{
+				"vlan": []interface{}{vlan(101, 10101, 0)},
+				"nve":  nve(nil, map[string]interface{}{"225.0.0.101": "10101-10102"}),
+			},
+			errs: []string{"VNI 10102 in ciscoevpn_nve vni_ipv4_multicast_group (225.0.0.101) isn't mapped to a ciscoevpn_vlan"},
+		},
+		{
+			name: "unknown EVPN instance",
+			raw: map[string]interface{}{
+				"vlan":          []interface{}{vlan(101, 0, 101), vlan(103, 0, 103)},
+				"evpn_instance": instances,
+			},
+			errs: []string{"VLAN 103 references EVPN instance 103 which isn't a ciscoevpn_evpn_instance"},
+		},
+		{
+			name: "unknown VRF",
+			raw: map[string]interface{}{
+				"nve": nve(map[string]interface{}{"blue": "50902"}, nil),
+				"vrf": vrfs,
+				"svi": []interface{}{
+					map[string]interface{}{"svi_id": 101, "vrf": "red"},
+				},
+			},
+			errs: []string{
+				`ciscoevpn_nve vni references VRF "blue" which isn't a ciscoevpn_vrf`,
+				`SVI 101 references VRF "red" which isn't a ciscoevpn_vrf`,
+			},
+		},
+		{
+			name: "SVI without VLAN",
+			raw: map[string]interface{}{
+				"vlan": []interface{}{vlan(101, 0, 0)},
+				"svi": []interface{}{
+					map[string]interface{}{"svi_id": 101},
+					map[string]interface{}{"svi_id": 102},
+				},
+			},
+			errs: []string{"SVI 102 has no ciscoevpn_vlan 102"},
+		},
+		{
+			name: "VNI in multicast group and ingress replication",
+			raw: map[string]interface{}{
+				"nve": nve(nil, map[string]interface{}{"225.0.0.101": "10101"}, 10101),
+			},
+			errs: []string{"VNI 10101 in ciscoevpn_nve vni_ingress_replication is already used in vni_ipv4_multicast_group (225.0.0.101)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, dataSourceCiscoFabricValidation().Schema, tt.raw)
+			if errs := fabricValidate(d); !reflect.DeepEqual(errs, tt.errs) {
+				t.Errorf("expected %q, got %q", tt.errs, errs)
+			}
+		})
+	}
+}
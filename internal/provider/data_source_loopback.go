@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/loopback"
+)
+
+func dataSourceCiscoNativeLoopback() *schema.Resource {
+	return &schema.Resource{
+		Description: "Cisco Loopback Interface of a host or the devices of a role",
+		ReadContext: dataSourceCiscoNativeLoopbackRead,
+		Schema: dataSourceSchema(map[string]*schema.Schema{
+			"interface_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ipv4_address": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ipv4_mask": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"pim_sm": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		}, map[string]*schema.Schema{
+			"loopback_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+		}),
+	}
+}
+
+func dataSourceCiscoNativeLoopbackRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco Loopback DATA SOURCE READ")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	id := d.Get("loopback_id").(int)
+	bodies, hosts, err := c.readTarget(ctx, d, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Loopback=%v", id))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	devices := []interface{}{}
+	for _, host := range hosts {
+		data := &loopback.CiscoIOSXENativeLoopbackInterface{}
+		if err = unmarshalBody(bodies[host], data); err != nil || len(data.CiscoIOSXENativeLoopback) == 0 {
+			return diag.Errorf("Loopback%v not found on: %v", id, host)
+		}
+		lp := data.CiscoIOSXENativeLoopback[0]
+		devices = append(devices, map[string]interface{}{
+			"host":           host,
+			"interface_name": fmt.Sprintf("Loopback%v", lp.Name),
+			"description":    lp.Description,
+			"ipv4_address":   lp.IP.Address.Primary.Address,
+			"ipv4_mask":      lp.IP.Address.Primary.Mask,
+			"pim_sm":         lp.IP.Pim.CiscoIOSXEMulticastPimModeChoiceCfg.SparseMode != nil,
+		})
+	}
+
+	if err = d.Set("devices", devices); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(fmt.Sprintf("loopback_%v_%v", dataSourceTarget(d), id))
+	return diags
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnDataSourceLoopback(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	// only leaf0 has the loopback, the read of leaf1 returns a 404
+	if err := f.Leafs[0].Set("Cisco-IOS-XE-native:native/interface/Loopback=100", `{"Cisco-IOS-XE-native:Loopback":[{
+		"name":100,"description":"underlay",
+		"ip":{"address":{"primary":{"address":"10.0.0.11","mask":"255.255.255.255"}},
+			"pim":{"Cisco-IOS-XE-multicast:pim-mode-choice-cfg":{"sparse-mode":{}}}}}]}`); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: f.Config() + fmt.Sprintf(`
+data "ciscoevpn_loopback" "test" {
+  host        = %q
+  loopback_id = 100
+}
+`, f.Leafs[0].Host()),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ciscoevpn_loopback.test", "devices.#", "1"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_loopback.test", "devices.0.host", f.Leafs[0].Host()),
+					resource.TestCheckResourceAttr("data.ciscoevpn_loopback.test", "devices.0.interface_name", "Loopback100"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_loopback.test", "devices.0.description", "underlay"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_loopback.test", "devices.0.ipv4_address", "10.0.0.11"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_loopback.test", "devices.0.ipv4_mask", "255.255.255.255"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_loopback.test", "devices.0.pim_sm", "true"),
+				),
+			},
+			{
+				Config: f.Config() + `
+data "ciscoevpn_loopback" "test" {
+  role        = "leafs"
+  loopback_id = 100
+}
+`,
+				ExpectError: regexp.MustCompile(regexp.QuoteMeta("Loopback100 not found on: " + f.Leafs[1].Host())),
+			},
+		},
+	})
+}
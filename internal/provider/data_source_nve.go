@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/nve"
+)
+
+func dataSourceCiscoNativeNve() *schema.Resource {
+	return &schema.Resource{
+		Description: "Cisco NVE Interface of a host or the devices of a role",
+		ReadContext: dataSourceCiscoNativeNveRead,
+		Schema: dataSourceSchema(map[string]*schema.Schema{
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"source_interface": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"host_reachability_bgp": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"l3_vnis": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"vni": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vrf": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"l2_vnis": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"vni": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ipv4_multicast_group": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ingress_replication": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+		}, map[string]*schema.Schema{}),
+	}
+}
+
+func dataSourceCiscoNativeNveRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco NVE DATA SOURCE READ")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	bodies, hosts, err := c.readTarget(ctx, d, "/data/Cisco-IOS-XE-native:native/interface/nve=1")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	devices := []interface{}{}
+	for _, host := range hosts {
+		data := &nve.CiscoIOSXENativeNves{}
+		if err = unmarshalBody(bodies[host], data); err != nil || len(data.CiscoIOSXENativeNve) == 0 {
+			return diag.Errorf("NVE not found on: %v", host)
+		}
+		nveData := data.CiscoIOSXENativeNve[0]
+		l3 := []interface{}{}
+		for _, vni := range nveData.MemberInOneLine.Member.Vni {
+			l3 = append(l3, map[string]interface{}{
+				"vni": vni.VniRange,
+				"vrf": vni.Vrf,
+			})
+		}
+		l2 := []interface{}{}
+		for _, vni := range nveData.Member.Vni {
+			entry := map[string]interface{}{
+				"vni":                  vni.VniRange,
+				"ipv4_multicast_group": "",
+				"ingress_replication":  vni.IrCpConfig != nil,
+			}
+			if vni.McastGroup != nil {
+				entry["ipv4_multicast_group"] = vni.McastGroup.MulticastGroupMin
+			}
+			l2 = append(l2, entry)
+		}
+		devices = append(devices, map[string]interface{}{
+			"host":                  host,
+			"description":           nveData.Description,
+			"source_interface":      fmt.Sprintf("Loopback%v", nveData.SourceInterface.Loopback),
+			"host_reachability_bgp": nveData.HostReachability.Protocol.Bgp != nil,
+			"l3_vnis":               l3,
+			"l2_vnis":               l2,
+		})
+	}
+
+	if err = d.Set("devices", devices); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(fmt.Sprintf("nve_%v", dataSourceTarget(d)))
+	return diags
+}
@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/nve"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/iosxe"
+)
+
+func dataSourceCiscoNveStatus() *schema.Resource {
+	return &schema.Resource{
+		Description: "Cisco NVE operational state (VNIs and peers) of a host or the devices of a role",
+		ReadContext: dataSourceCiscoNveStatusRead,
+		Schema: dataSourceSchema(map[string]*schema.Schema{
+			"vnis_up": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "All VNIs are up, false without VNIs.",
+			},
+			"peers_up": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "All NVE peers are up, false without peers.",
+			},
+			"vnis": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"interface": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vni": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"mode": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vlan": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"vrf": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"mcast_group": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"peers": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"interface": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vni": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"peer_ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"learn_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"uptime": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		}, map[string]*schema.Schema{}),
+	}
+}
+
+func dataSourceCiscoNveStatusRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco NVE STATUS DATA SOURCE READ")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	bodies, hosts, err := c.readTarget(ctx, d, "/data/Cisco-IOS-XE-nve-oper:nve-oper-data")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	devices := []interface{}{}
+	for _, host := range hosts {
+		data := &nve.CiscoIOSXENveOperData{}
+		if err = unmarshalBody(bodies[host], data); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		vnisUp := true
+		vnis := []interface{}{}
+		for _, v := range data.NveOperData.NveVni {
+			vnisUp = vnisUp && operUp(v.VniState)
+			vnis = append(vnis, map[string]interface{}{
+				"interface":   v.NveIfName,
+				"vni":         v.Vni,
+				"state":       v.VniState,
+				"mode":        v.Mode,
+				"vlan":        v.Vlan,
+				"vrf":         v.Vrf,
+				"mcast_group": v.McastAddr,
+			})
+		}
+		peersUp := true
+		peers := []interface{}{}
+		for _, p := range data.NveOperData.NvePeers {
+			peersUp = peersUp && operUp(p.PeerState)
+			peers = append(peers, map[string]interface{}{
+				"interface":  p.NveIfName,
+				"vni":        p.Vni,
+				"peer_ip":    p.PeerIP,
+				"state":      p.PeerState,
+				"learn_type": p.LearnType,
+				"uptime":     p.Uptime,
+			})
+		}
+		devices = append(devices, map[string]interface{}{
+			"host":     host,
+			"vnis_up":  vnisUp && len(vnis) > 0,
+			"peers_up": peersUp && len(peers) > 0,
+			"vnis":     vnis,
+			"peers":    peers,
+		})
+	}
+
+	if err = d.Set("devices", devices); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(fmt.Sprintf("nve_status_%v", dataSourceTarget(d)))
+	return diags
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnDataSourceNveStatus(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	path := "Cisco-IOS-XE-nve-oper:nve-oper-data"
+	// only leaf0 has operational data, the read of leaf1 returns a 404
+	if err := f.Leafs[0].Set(path, testAccNveOperData("vni-state-up")); err != nil {
+		t.Fatal(err)
+	}
+	leaf0 := testAccDataSourceDevice(f.Leafs, f.Leafs[0])
+	leaf1 := testAccDataSourceDevice(f.Leafs, f.Leafs[1])
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: f.Config() + `
+data "ciscoevpn_nve_status" "test" {
+  role = "leafs"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", "devices.#", "2"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", leaf0+".host", f.Leafs[0].Host()),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", leaf0+".vnis_up", "true"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", leaf0+".peers_up", "true"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", leaf0+".vnis.#", "2"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", leaf0+".vnis.0.interface", "nve1"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", leaf0+".vnis.0.vni", "10101"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", leaf0+".vnis.0.state", "vni-state-up"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", leaf0+".vnis.0.mode", "control-plane"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", leaf0+".vnis.0.vlan", "101"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", leaf0+".vnis.0.mcast_group", "225.0.0.101"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", leaf0+".vnis.1.vrf", "green"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", leaf0+".peers.#", "1"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", leaf0+".peers.0.peer_ip", "10.0.0.12"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", leaf0+".peers.0.state", "peer-state-up"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", leaf0+".peers.0.learn_type", "learn-type-cp"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", leaf0+".peers.0.uptime", "1d2h"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", leaf1+".host", f.Leafs[1].Host()),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", leaf1+".vnis_up", "false"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", leaf1+".peers_up", "false"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", leaf1+".vnis.#", "0"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", leaf1+".peers.#", "0"),
+				),
+			},
+			{
+				PreConfig: func() {
+					if err := f.Leafs[0].Set(path, testAccNveOperData("vni-state-down")); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: f.Config() + fmt.Sprintf(`
+data "ciscoevpn_nve_status" "test" {
+  host = %q
+}
+`, f.Leafs[0].Host()),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", "devices.#", "1"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", "devices.0.vnis_up", "false"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", "devices.0.vnis.0.state", "vni-state-down"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve_status.test", "devices.0.peers_up", "true"),
+				),
+			},
+		},
+	})
+}
+
+// testAccNveOperData returns the operational data of a leaf with the state of the first VNI
+func testAccNveOperData(state string) string {
+	return fmt.Sprintf(`{"Cisco-IOS-XE-nve-oper:nve-oper-data":{
+		"nve-vni":[
+			{"nve-if-name":"nve1","vni":10101,"mcast-addr":"225.0.0.101","vni-state":%q,"mode":"control-plane","vlan":101,"cfg-src":"cli"},
+			{"nve-if-name":"nve1","vni":50901,"vni-state":"vni-state-up","mode":"control-plane","vrf":"green"}],
+		"nve-peers":[{"nve-if-name":"nve1","vni":50901,"peer-ip":"10.0.0.12","peer-state":"peer-state-up","learn-type":"learn-type-cp","uptime":"1d2h"}]}}`, state)
+}
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnDataSourceNve(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	// only leaf0 has the NVE, the read of leaf1 returns a 404
+	if err := f.Leafs[0].Set("Cisco-IOS-XE-native:native/interface/nve=1", `{"Cisco-IOS-XE-native:nve":[{
+		"name":1,"description":"overlay","source-interface":{"Loopback":200},
+		"host-reachability":{"protocol":{"bgp":[null]}},
+		"member-in-one-line":{"member":{"vni":[{"vni-range":"50901","vrf":"green"}]}},
+		"member":{"vni":[
+			{"vni-range":"10101","mcast-group":{"multicast-group-min":"225.0.0.101"}},
+			{"vni-range":"10102","ir-cp-config":{"ingress-replication":[null]}}]}}]}`); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: f.Config() + fmt.Sprintf(`
+data "ciscoevpn_nve" "test" {
+  host = %q
+}
+`, f.Leafs[0].Host()),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve.test", "devices.#", "1"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve.test", "devices.0.host", f.Leafs[0].Host()),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve.test", "devices.0.description", "overlay"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve.test", "devices.0.source_interface", "Loopback200"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve.test", "devices.0.host_reachability_bgp", "true"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve.test", "devices.0.l3_vnis.#", "1"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve.test", "devices.0.l3_vnis.0.vni", "50901"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve.test", "devices.0.l3_vnis.0.vrf", "green"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve.test", "devices.0.l2_vnis.#", "2"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve.test", "devices.0.l2_vnis.0.vni", "10101"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve.test", "devices.0.l2_vnis.0.ipv4_multicast_group", "225.0.0.101"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve.test", "devices.0.l2_vnis.0.ingress_replication", "false"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve.test", "devices.0.l2_vnis.1.vni", "10102"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve.test", "devices.0.l2_vnis.1.ipv4_multicast_group", ""),
+					resource.TestCheckResourceAttr("data.ciscoevpn_nve.test", "devices.0.l2_vnis.1.ingress_replication", "true"),
+				),
+			},
+			{
+				Config: f.Config() + `
+data "ciscoevpn_nve" "test" {
+  role = "leafs"
+}
+`,
+				ExpectError: regexp.MustCompile(regexp.QuoteMeta("NVE not found on: " + f.Leafs[1].Host())),
+			},
+		},
+	})
+}
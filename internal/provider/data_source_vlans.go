@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/vlan"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/iosxe"
+)
+
+func dataSourceCiscoNativeVlans() *schema.Resource {
+	return &schema.Resource{
+		Description: "Cisco VLANs of a host or the devices of a role",
+		ReadContext: dataSourceCiscoNativeVlansRead,
+		Schema: dataSourceSchema(map[string]*schema.Schema{
+			"vlans": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"vlan_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vni": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"evpn_instance": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		}, map[string]*schema.Schema{}),
+	}
+}
+
+func dataSourceCiscoNativeVlansRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco VLANS DATA SOURCE READ")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	bodies, hosts, err := c.readTarget(ctx, d, "/data/Cisco-IOS-XE-native:native/vlan")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	devices := []interface{}{}
+	for _, host := range hosts {
+		data := &vlan.CiscoIOSXENativeVlans{}
+		if err = unmarshalBody(bodies[host], data); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		members := make(map[int]vlan.CiscoIOSXEVlanConfigurationEntryMember)
+		for _, v := range data.CiscoIOSXENativeVlan.CiscoIOSXEVlanConfigurationEntry {
+			if id, err := strconv.Atoi(v.VlanID); err == nil {
+				members[id] = v.Member
+			}
+		}
+		vlans := []interface{}{}
+		for _, v := range data.CiscoIOSXENativeVlan.CiscoIOSXEVlanVlanList {
+			entry := map[string]interface{}{
+				"vlan_id":       v.ID,
+				"name":          v.Name,
+				"vni":           members[v.ID].Vni,
+				"evpn_instance": 0,
+			}
+			if member := members[v.ID]; member.EvpnInstance != nil {
+				entry["vni"] = member.EvpnInstance.Vni
+				entry["evpn_instance"] = member.EvpnInstance.EvpnInstance
+			}
+			vlans = append(vlans, entry)
+		}
+		devices = append(devices, map[string]interface{}{
+			"host":  host,
+			"vlans": vlans,
+		})
+	}
+
+	if err = d.Set("devices", devices); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(fmt.Sprintf("vlans_%v", dataSourceTarget(d)))
+	return diags
+}
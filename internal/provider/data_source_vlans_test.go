@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnDataSourceVlans(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	// only leaf0 has VLANs, the read of leaf1 returns a 404
+	if err := f.Leafs[0].Set("Cisco-IOS-XE-native:native/vlan", `{"Cisco-IOS-XE-native:vlan":{
+		"Cisco-IOS-XE-vlan:vlan-list":[{"id":101,"name":"green"},{"id":102,"name":"blue"}],
+		"Cisco-IOS-XE-vlan:configuration-entry":[{"vlan-id":"101","member":{"evpn-instance":{"evpn-instance":101,"vni":10101}}}]}}`); err != nil {
+		t.Fatal(err)
+	}
+	leaf0 := testAccDataSourceDevice(f.Leafs, f.Leafs[0])
+	leaf1 := testAccDataSourceDevice(f.Leafs, f.Leafs[1])
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: f.Config() + `
+data "ciscoevpn_vlans" "test" {
+  role = "leafs"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ciscoevpn_vlans.test", "devices.#", "2"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vlans.test", leaf0+".host", f.Leafs[0].Host()),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vlans.test", leaf0+".vlans.#", "2"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vlans.test", leaf0+".vlans.0.vlan_id", "101"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vlans.test", leaf0+".vlans.0.name", "green"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vlans.test", leaf0+".vlans.0.vni", "10101"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vlans.test", leaf0+".vlans.0.evpn_instance", "101"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vlans.test", leaf0+".vlans.1.vlan_id", "102"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vlans.test", leaf0+".vlans.1.evpn_instance", "0"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vlans.test", leaf1+".host", f.Leafs[1].Host()),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vlans.test", leaf1+".vlans.#", "0"),
+				),
+			},
+			{
+				Config: f.Config() + fmt.Sprintf(`
+data "ciscoevpn_vlans" "test" {
+  host = %q
+}
+`, f.Leafs[0].Host()),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ciscoevpn_vlans.test", "devices.#", "1"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vlans.test", "devices.0.host", f.Leafs[0].Host()),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vlans.test", "devices.0.vlans.0.name", "green"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/vrf"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/iosxe"
+)
+
+func dataSourceCiscoNativeVrfs() *schema.Resource {
+	routeTargets := func() *schema.Schema {
+		return &schema.Schema{
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		}
+	}
+	return &schema.Resource{
+		Description: "Cisco VRFs of a host or the devices of a role",
+		ReadContext: dataSourceCiscoNativeVrfsRead,
+		Schema: dataSourceSchema(map[string]*schema.Schema{
+			"vrfs": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"rd": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ipv4_import":      routeTargets(),
+						"ipv4_export":      routeTargets(),
+						"ipv4_import_evpn": routeTargets(),
+						"ipv4_export_evpn": routeTargets(),
+						"ipv6_import":      routeTargets(),
+						"ipv6_export":      routeTargets(),
+						"ipv6_import_evpn": routeTargets(),
+						"ipv6_export_evpn": routeTargets(),
+					},
+				},
+			},
+		}, map[string]*schema.Schema{}),
+	}
+}
+
+func dataSourceCiscoNativeVrfsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco VRFS DATA SOURCE READ")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	bodies, hosts, err := c.readTarget(ctx, d, "/data/Cisco-IOS-XE-native:native/vrf/definition")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	devices := []interface{}{}
+	for _, host := range hosts {
+		data := &vrf.CiscoIOSXENativeVrf{}
+		if err = unmarshalBody(bodies[host], data); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		vrfs := []interface{}{}
+		for _, v := range data.CiscoIOSXENativeDefinition {
+			ipv4 := v.AddressFamily.Ipv4.RouteTarget
+			ipv6 := v.AddressFamily.Ipv6.RouteTarget
+			vrfs = append(vrfs, map[string]interface{}{
+				"name":             v.Name,
+				"rd":               v.Rd,
+				"ipv4_import":      dataSourceVrfRouteTargets(ipv4.ImportRouteTarget.WithoutStitching, nil),
+				"ipv4_export":      dataSourceVrfRouteTargets(ipv4.ExportRouteTarget.WithoutStitching, nil),
+				"ipv4_import_evpn": dataSourceVrfRouteTargets(nil, ipv4.ImportRouteTarget.WithStitching),
+				"ipv4_export_evpn": dataSourceVrfRouteTargets(nil, ipv4.ExportRouteTarget.WithStitching),
+				"ipv6_import":      dataSourceVrfRouteTargets(ipv6.ImportRouteTarget.WithoutStitching, nil),
+				"ipv6_export":      dataSourceVrfRouteTargets(ipv6.ExportRouteTarget.WithoutStitching, nil),
+				"ipv6_import_evpn": dataSourceVrfRouteTargets(nil, ipv6.ImportRouteTarget.WithStitching),
+				"ipv6_export_evpn": dataSourceVrfRouteTargets(nil, ipv6.ExportRouteTarget.WithStitching),
+			})
+		}
+		devices = append(devices, map[string]interface{}{
+			"host": host,
+			"vrfs": vrfs,
+		})
+	}
+
+	if err = d.Set("devices", devices); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(fmt.Sprintf("vrfs_%v", dataSourceTarget(d)))
+	return diags
+}
+
+func dataSourceVrfRouteTargets(without []vrf.CiscoIOSXENativeDefinitionWithoutStitching, with []vrf.CiscoIOSXENativeDefinitionWithStitching) []interface{} {
+	rts := []interface{}{}
+	for _, rt := range without {
+		rts = append(rts, rt.AsnIP)
+	}
+	for _, rt := range with {
+		rts = append(rts, rt.AsnIP)
+	}
+	return rts
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnDataSourceVrfs(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	// only leaf0 has VRFs, the read of leaf1 returns a 404
+	if err := f.Leafs[0].Set("Cisco-IOS-XE-native:native/vrf/definition=green", `{"Cisco-IOS-XE-native:definition":[{
+		"name":"green","rd":"1:1","address-family":{
+			"ipv4":{"route-target":{
+				"export-route-target":{"without-stitching":[{"asn-ip":"1:1"}],"with-stitching":[{"asn-ip":"1:1","stitching":[null]}]},
+				"import-route-target":{"without-stitching":[{"asn-ip":"1:1"},{"asn-ip":"2:2"}]}}},
+			"ipv6":{}}}]}`); err != nil {
+		t.Fatal(err)
+	}
+	leaf0 := testAccDataSourceDevice(f.Leafs, f.Leafs[0])
+	leaf1 := testAccDataSourceDevice(f.Leafs, f.Leafs[1])
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: f.Config() + `
+data "ciscoevpn_vrfs" "test" {
+  role = "leafs"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ciscoevpn_vrfs.test", "devices.#", "2"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vrfs.test", leaf0+".host", f.Leafs[0].Host()),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vrfs.test", leaf0+".vrfs.#", "1"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vrfs.test", leaf0+".vrfs.0.name", "green"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vrfs.test", leaf0+".vrfs.0.rd", "1:1"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vrfs.test", leaf0+".vrfs.0.ipv4_import.#", "2"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vrfs.test", leaf0+".vrfs.0.ipv4_import.1", "2:2"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vrfs.test", leaf0+".vrfs.0.ipv4_export.0", "1:1"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vrfs.test", leaf0+".vrfs.0.ipv4_export_evpn.0", "1:1"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vrfs.test", leaf0+".vrfs.0.ipv4_import_evpn.#", "0"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vrfs.test", leaf0+".vrfs.0.ipv6_import.#", "0"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vrfs.test", leaf1+".host", f.Leafs[1].Host()),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vrfs.test", leaf1+".vrfs.#", "0"),
+				),
+			},
+			{
+				Config: f.Config() + fmt.Sprintf(`
+data "ciscoevpn_vrfs" "test" {
+  host = %q
+}
+`, f.Leafs[1].Host()),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ciscoevpn_vrfs.test", "devices.#", "1"),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vrfs.test", "devices.0.host", f.Leafs[1].Host()),
+					resource.TestCheckResourceAttr("data.ciscoevpn_vrfs.test", "devices.0.vrfs.#", "0"),
+				),
+			},
+		},
+	})
+}
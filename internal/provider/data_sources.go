@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/iosxe"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
+)
+
+// dataSourceSchema adds the host or role to read from, and the computed devices with elem
+func dataSourceSchema(elem map[string]*schema.Schema, s map[string]*schema.Schema) map[string]*schema.Schema {
+	s["host"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ExactlyOneOf: []string{"host", "role"},
+		ValidateFunc: validation.StringIsNotWhiteSpace,
+		Description:  "Host of the device to read from.",
+	}
+	s["role"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ExactlyOneOf: []string{"host", "role"},
+		ValidateFunc: validation.StringInSlice([]string{"spines", "leafs", "borders"}, false),
+		Description:  "Role of the devices to read from: `spines`, `leafs` or `borders`.",
+	}
+	elem["host"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Host of the device.",
+	}
+	s["devices"] = &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "Result per device, sorted by host.",
+		Elem:        &schema.Resource{Schema: elem},
+	}
+	return s
+}
+
+// dataSourceTarget returns the host or role of the data source, used in the ID
+func dataSourceTarget(d *schema.ResourceData) string {
+	if v, ok := d.GetOk("host"); ok {
+		return v.(string)
+	}
+	return d.Get("role").(string)
+}
+
+// readTarget GETs the path from the host or the devices of the role. Hosts without the path get an empty body.
+func (c *providerClient) readTarget(ctx context.Context, d *schema.ResourceData, path string) (map[string]string, []string, error) {
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "GET",
+		Path:    path,
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	bodies := make(map[string]string)
+	var err error
+	if v, ok := d.GetOk("host"); ok {
+		svc.Device = v.(string)
+		bodies[svc.Device], err = iosxe.SingleSession(svc)
+		if errors.Is(err, iosxe.ErrNotFound) {
+			err = nil
+		}
+	} else {
+		svc.Role = d.Get("role").(string)
+		if len(iosxe.HostRoles(svc.Devices, svc.Role)) > 0 {
+			bodies, err = iosxe.MultiSession(svc)
+		}
+		var notFound []string
+		notFound, err = iosxe.SplitNotFound(err)
+		for _, host := range notFound {
+			bodies[host] = ""
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hosts := make([]string, 0, len(bodies))
+	for host := range bodies {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return bodies, hosts, nil
+}
+
+// operUp returns true for the up states of the operational models, e.g. "vni-state-up" or "fsm-established"
+func operUp(state string) bool {
+	state = strings.ToLower(state)
+	return strings.HasSuffix(state, "up") || strings.HasSuffix(state, "established")
+}
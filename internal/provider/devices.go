@@ -0,0 +1,508 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/iosxe"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
+)
+
+const (
+	deviceApplied = "applied"
+	deviceFailed  = "failed"
+	deviceDrift   = "drift"
+	// deviceRendered is a payload rendered in dry run mode, it isn't applied on the device
+	deviceRendered = "rendered"
+)
+
+// deviceState is the apply status of a role based resource on a single host
+type deviceState struct {
+	Role        string
+	Status      string
+	PayloadHash string
+	Timestamp   string
+	// Payload is only kept for the rendered_payloads in dry run mode
+	Payload string
+	// CLI is the IOS-XE CLI of the payload sent in this apply
+	CLI string
+}
+
+type stateGetter interface {
+	Get(key string) interface{}
+}
+
+func devicesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "Per device state of the roles, sorted by host.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"host": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Host of the device.",
+				},
+				"role": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Role of the device.",
+				},
+				"status": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Apply status of the device: `applied`, `failed`, `drift` or `rendered` (dry run).",
+				},
+				"payload_hash": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "SHA256 of the last payload applied on the device.",
+				},
+				"timestamp": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Time (RFC3339) of the last apply on the device.",
+				},
+			},
+		},
+	}
+}
+
+func cliPreviewSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "IOS-XE CLI of the payloads per host, equivalent to the YANG payloads sent by the last apply.",
+	}
+}
+
+func renderedPayloadsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "Payloads per host rendered in dry run mode, nothing is sent to the devices.",
+	}
+}
+
+// devicesCustomizeDiff plans an update when the hosts of the roles have changed
+// or when a device hasn't got the latest payload applied
+func devicesCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	c, _ := meta.(*providerClient)
+	devices := getDevices(d)
+	hosts := c.roleHosts(d.Get("roles").([]interface{}))
+
+	for host, role := range hosts {
+		if s, ok := devices[host]; !ok || s.Role != role || s.Status != deviceApplied {
+			log.Println("[DEBUG] Device needs to be reconciled: ", host)
+			return d.SetNewComputed("devices")
+		}
+	}
+	for host := range devices {
+		if _, ok := hosts[host]; !ok {
+			log.Println("[DEBUG] Device removed from roles: ", host)
+			return d.SetNewComputed("devices")
+		}
+	}
+	return nil
+}
+
+// roleHosts returns the hosts of the roles from the provider configuration
+func (c *providerClient) roleHosts(roles []interface{}) map[string]string {
+	hosts := make(map[string]string)
+	for _, role := range roles {
+		for _, host := range iosxe.HostRoles(c.Devices.List(), role.(string)) {
+			hosts[host.(string)] = role.(string)
+		}
+	}
+	return hosts
+}
+
+func getDevices(d stateGetter) map[string]*deviceState {
+	devices := make(map[string]*deviceState)
+	v, ok := d.Get("devices").([]interface{})
+	if !ok {
+		return devices
+	}
+	for _, raw := range v {
+		device := raw.(map[string]interface{})
+		devices[device["host"].(string)] = &deviceState{
+			Role:        device["role"].(string),
+			Status:      device["status"].(string),
+			PayloadHash: device["payload_hash"].(string),
+			Timestamp:   device["timestamp"].(string),
+		}
+	}
+	return devices
+}
+
+func setDevices(d *schema.ResourceData, devices map[string]*deviceState) error {
+	hosts := make([]string, 0, len(devices))
+	for host := range devices {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	data := make([]interface{}, 0, len(hosts))
+	for _, host := range hosts {
+		data = append(data, map[string]interface{}{
+			"host":         host,
+			"role":         devices[host].Role,
+			"status":       devices[host].Status,
+			"payload_hash": devices[host].PayloadHash,
+			"timestamp":    devices[host].Timestamp,
+		})
+	}
+	if err := d.Set("devices", data); err != nil {
+		return err
+	}
+
+	// payloads rendered by an earlier dry run are kept until the host is applied
+	rendered := make(map[string]interface{})
+	for host, payload := range d.Get("rendered_payloads").(map[string]interface{}) {
+		if state, ok := devices[host]; ok && state.Status == deviceRendered {
+			rendered[host] = payload
+		}
+	}
+	for host, state := range devices {
+		if state.Payload != "" {
+			rendered[host] = state.Payload
+		}
+	}
+	if err := d.Set("rendered_payloads", rendered); err != nil {
+		return err
+	}
+
+	// the CLI of hosts without a change in this apply is kept
+	preview := make(map[string]interface{})
+	for host, cli := range d.Get("cli_preview").(map[string]interface{}) {
+		if _, ok := devices[host]; ok {
+			preview[host] = cli
+		}
+	}
+	for host, state := range devices {
+		if state.CLI != "" {
+			preview[host] = state.CLI
+		}
+	}
+	return d.Set("cli_preview", preview)
+}
+
+func payloadHash(svc *service.Client) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(svc.Payload)))
+}
+
+// applyRole sends the payload to the hosts of the role which haven't got it applied yet
+func (c *providerClient) applyRole(svc *service.Client, devices map[string]*deviceState) error {
+	hash := payloadHash(svc)
+	hosts := []interface{}{}
+	for _, host := range iosxe.HostRoles(svc.Devices, svc.Role) {
+		if isApplied(devices, host.(string), svc.Role, hash) {
+			log.Println("[DEBUG] Payload already applied on: ", host)
+			continue
+		}
+		hosts = append(hosts, host)
+	}
+	if len(hosts) == 0 {
+		return nil
+	}
+	return c.sendHosts(svc, hosts, devices, hash)
+}
+
+func (c *providerClient) sendHosts(svc *service.Client, hosts []interface{}, devices map[string]*deviceState, hash string) error {
+	svc.Hosts = hosts
+	defer func() { svc.Hosts = nil }()
+
+	data, err := iosxe.MultiSession(svc)
+	for _, host := range hosts {
+		_, ok := data[host.(string)]
+		c.markSent(devices, host.(string), svc, hash, ok)
+	}
+	return err
+}
+
+// markSent records the result of the request of svc to the host, in dry run mode the payload was only rendered
+func (c *providerClient) markSent(devices map[string]*deviceState, host string, svc *service.Client, hash string, ok bool) {
+	if ok && c.Config.DryRun {
+		markRendered(devices, host, svc.Role, svc.Payload)
+	} else {
+		markDevice(devices, host, svc.Role, hash, ok)
+	}
+	if ok {
+		devices[host].CLI = strings.Join(svc.CLI, "\n")
+	}
+}
+
+// payloadStep is one object of a resource which configures several objects on a device
+type payloadStep struct {
+	Path    string
+	Payload string
+	CLI     []string
+}
+
+// newPayloadStep marshals the YANG model of the path
+func newPayloadStep(path string, data interface{ CLI() []string }) payloadStep {
+	b, _ := json.MarshalIndent(data, "", "\t")
+	return payloadStep{Path: path, Payload: string(b), CLI: data.CLI()}
+}
+
+// stepsPayload returns the steps as a single JSON document, its hash tracks the apply of all steps
+func stepsPayload(steps []payloadStep) (string, []string) {
+	var lines []string
+	data := make([]map[string]interface{}, 0, len(steps))
+	for _, step := range steps {
+		data = append(data, map[string]interface{}{
+			"path":    step.Path,
+			"payload": json.RawMessage(step.Payload),
+		})
+		lines = append(lines, step.CLI...)
+	}
+	b, _ := json.MarshalIndent(data, "", "\t")
+	return string(b), lines
+}
+
+// applyRoleSteps PATCHes the steps in order on the hosts of the role which haven't got them applied yet
+func (c *providerClient) applyRoleSteps(svc *service.Client, steps []payloadStep, devices map[string]*deviceState) error {
+	hostSteps := make(map[string][]payloadStep)
+	for _, host := range iosxe.HostRoles(svc.Devices, svc.Role) {
+		hostSteps[host.(string)] = steps
+	}
+	return c.applyHostSteps(svc, hostSteps, devices)
+}
+
+// applyHostSteps PATCHes the steps of each host in order, e.g. payloads with an address of the device.
+// Hosts with the same steps are configured together, hosts which have the steps applied are skipped.
+func (c *providerClient) applyHostSteps(svc *service.Client, hostSteps map[string][]payloadStep, devices map[string]*deviceState) error {
+	groups := make(map[string][]interface{})
+	payloads := []string{}
+	for host, steps := range hostSteps {
+		payload, _ := stepsPayload(steps)
+		if isApplied(devices, host, svc.Role, payloadHash(&service.Client{Payload: payload})) {
+			log.Println("[DEBUG] Payload already applied on: ", host)
+			continue
+		}
+		if _, ok := groups[payload]; !ok {
+			payloads = append(payloads, payload)
+		}
+		groups[payload] = append(groups[payload], host)
+	}
+	sort.Strings(payloads)
+
+	errs := make(iosxe.HostErrors)
+	for _, payload := range payloads {
+		hosts := groups[payload]
+		sort.Slice(hosts, func(i, j int) bool { return hosts[i].(string) < hosts[j].(string) })
+		if err := mergeHostErrors(errs, c.sendSteps(svc, hostSteps[hosts[0].(string)], hosts, devices)); err != nil {
+			return err
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// sendSteps PATCHes the steps in order on the hosts, a host which fails a step is skipped in the following steps
+func (c *providerClient) sendSteps(svc *service.Client, steps []payloadStep, hosts []interface{}, devices map[string]*deviceState) error {
+	svc.Method = "PATCH"
+	svc.Payload, svc.CLI = stepsPayload(steps)
+	hash := payloadHash(svc)
+	all := *svc
+
+	errs := make(iosxe.HostErrors)
+	failed := make(map[string]bool)
+	for _, step := range steps {
+		svc.Hosts = []interface{}{}
+		for _, host := range hosts {
+			if !failed[host.(string)] {
+				svc.Hosts = append(svc.Hosts, host)
+			}
+		}
+		if len(svc.Hosts) == 0 {
+			break
+		}
+		svc.Path, svc.Payload, svc.CLI = step.Path, step.Payload, step.CLI
+		data, err := iosxe.MultiSession(svc)
+		for _, host := range svc.Hosts {
+			if _, ok := data[host.(string)]; !ok {
+				failed[host.(string)] = true
+			}
+		}
+		if err = mergeHostErrors(errs, err); err != nil {
+			svc.Hosts = nil
+			return err
+		}
+	}
+	svc.Hosts = nil
+	for _, host := range hosts {
+		c.markSent(devices, host.(string), &all, hash, !failed[host.(string)])
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// mergeHostErrors adds the host errors of err to errs, other errors are returned
+func mergeHostErrors(errs iosxe.HostErrors, err error) error {
+	var hostErrs iosxe.HostErrors
+	if errors.As(err, &hostErrs) {
+		for host, e := range hostErrs {
+			errs[host] = e
+		}
+		return nil
+	}
+	return err
+}
+
+// deleteRoleSteps deletes the paths in order on the hosts of the role, paths which are already removed are skipped
+func (c *providerClient) deleteRoleSteps(svc *service.Client, paths []string, devices map[string]*deviceState) error {
+	svc.Method = "DELETE"
+	for _, path := range paths {
+		svc.Path = path
+		if _, err := iosxe.SplitNotFound(c.deleteRole(svc, devices)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isApplied returns true when the host has the payload of the role applied
+func isApplied(devices map[string]*deviceState, host, role, hash string) bool {
+	s, ok := devices[host]
+	return ok && s.Status == deviceApplied && s.Role == role && s.PayloadHash == hash
+}
+
+// markDevice records the result of an apply on the host
+func markDevice(devices map[string]*deviceState, host, role, hash string, applied bool) {
+	state, ok := devices[host]
+	if !ok {
+		state = &deviceState{}
+		devices[host] = state
+	}
+	state.Role = role
+	state.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	if applied {
+		state.Status = deviceApplied
+		state.PayloadHash = hash
+	} else {
+		state.Status = deviceFailed
+	}
+}
+
+// markRendered records the payload rendered for the host in dry run mode
+func markRendered(devices map[string]*deviceState, host, role, payload string) {
+	state, ok := devices[host]
+	if !ok {
+		state = &deviceState{}
+		devices[host] = state
+	}
+	state.Role = role
+	state.Status = deviceRendered
+	state.Payload = payload
+	state.Timestamp = time.Now().UTC().Format(time.RFC3339)
+}
+
+// setHostPreview sets the CLI of a single host resource and the payload in dry run mode, it's cleared by an apply
+func (c *providerClient) setHostPreview(d *schema.ResourceData, svc *service.Client) error {
+	if err := setHostCli(d, svc.CLI); err != nil {
+		return err
+	}
+	if !c.Config.DryRun {
+		return d.Set("rendered_payloads", map[string]interface{}{})
+	}
+	return d.Set("rendered_payloads", map[string]interface{}{svc.Device: svc.Payload})
+}
+
+// setHostCli sets the CLI of a single host resource
+func setHostCli(d *schema.ResourceData, lines []string) error {
+	return d.Set("cli_preview", map[string]interface{}{d.Get("host").(string): strings.Join(lines, "\n")})
+}
+
+// deleteRole sends the request to the hosts of the role in state,
+// resources without device state fall back to the hosts of the role
+func (c *providerClient) deleteRole(svc *service.Client, devices map[string]*deviceState) error {
+	if len(devices) == 0 {
+		_, err := iosxe.MultiSession(svc)
+		return err
+	}
+	hosts := []interface{}{}
+	for host, state := range devices {
+		if state.Role == svc.Role {
+			hosts = append(hosts, host)
+		}
+	}
+	if len(hosts) == 0 {
+		return nil
+	}
+	svc.Hosts = hosts
+	defer func() { svc.Hosts = nil }()
+	_, err := iosxe.MultiSession(svc)
+	return err
+}
+
+// removedHosts returns the hosts in state which aren't part of the roles anymore, grouped by role
+func (c *providerClient) removedHosts(d *schema.ResourceData, devices map[string]*deviceState) map[string][]interface{} {
+	removed := make(map[string][]interface{})
+	hosts := c.roleHosts(d.Get("roles").([]interface{}))
+	for host, state := range devices {
+		if role, ok := hosts[host]; !ok || role != state.Role {
+			removed[state.Role] = append(removed[state.Role], host)
+		}
+	}
+	return removed
+}
+
+// removeHosts deletes the paths on the hosts which aren't part of the roles anymore
+func (c *providerClient) removeHosts(ctx context.Context, d *schema.ResourceData, devices map[string]*deviceState, paths ...string) error {
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "DELETE",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+	for role, hosts := range c.removedHosts(d, devices) {
+		svc.Role = role
+		svc.Hosts = hosts
+		for _, path := range paths {
+			log.Printf("[DEBUG] Removing %v from: %v\n", path, hosts)
+			svc.Path = path
+			// the hosts stay in state until the paths are removed from all of them
+			_, err := iosxe.MultiSession(svc)
+			if _, err = iosxe.SplitNotFound(err); err != nil {
+				return err
+			}
+		}
+		for _, host := range hosts {
+			delete(devices, host.(string))
+		}
+	}
+	return nil
+}
+
+// setDevicesDriftState sets the state read from the devices and marks the devices with drift
+func setDevicesDriftState(d *schema.ResourceData, states map[string]map[string]interface{}) error {
+	devices := getDevices(d)
+	for host, state := range states {
+		if s, ok := devices[host]; ok && stateDrift(d, state) {
+			s.Status = deviceDrift
+		}
+	}
+	if err := setDriftState(d, states); err != nil {
+		return err
+	}
+	return setDevices(d, devices)
+}
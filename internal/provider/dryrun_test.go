@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// TestAccDryRun renders the payloads without configuring the simulator
+func TestAccDryRun(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	report := filepath.Join(t.TempDir(), "plan_report.json")
+	path := "Cisco-IOS-XE-native:native/vrf/definition=green"
+
+	f.Settings = fmt.Sprintf("  dry_run     = true\n  plan_report = %q", report)
+	config := testAccCiscoEvpnVrfConfig(f, "1:1", false)
+	removed := f.Config()
+	f.Settings = ""
+	applied := testAccCiscoEvpnVrfConfig(f, "1:1", false)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_vrf.test", "rendered_payloads.%", "2"),
+					resource.TestCheckResourceAttr("ciscoevpn_vrf.test", "devices.0.status", "rendered"),
+					testAccCheckDevicesRemoved(f.Devices(), path),
+					testAccCheckCassette(report, `"method": "PATCH"`, `"role": "leafs"`, f.Leafs[0].Host(), f.Leafs[1].Host()),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: applied,
+				Check:  testAccCheckDevices(f.Leafs, path, `"1:1"`),
+			},
+			{
+				Config:      config,
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`dry_run: delete not applied`),
+			},
+			{
+				// the VRF is kept in state and on the devices, removing it from the config still plans the delete
+				Config:             removed,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: f.Config(),
+				Check:  testAccCheckDevicesRemoved(f.Devices(), path),
+			},
+		},
+	})
+}
@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// importID splits the import ID in the parts of the format, e.g. "<roles>:<vlan_id>"
+func importID(id string, format string) ([]string, error) {
+	return importParts(id, format, strings.Split(id, ":"))
+}
+
+// importHostID splits the import ID in the parts of the format, e.g. "<host>:<loopback_id>".
+// The host is the first part and may contain a port.
+func importHostID(id string, format string) ([]string, error) {
+	n := len(strings.Split(format, ":"))
+	parts := strings.Split(id, ":")
+	if len(parts) > n {
+		parts = append([]string{strings.Join(parts[:len(parts)-n+1], ":")}, parts[len(parts)-n+1:]...)
+	}
+	return importParts(id, format, parts)
+}
+
+func importParts(id string, format string, parts []string) ([]string, error) {
+	if len(parts) != len(strings.Split(format, ":")) {
+		return nil, fmt.Errorf("unexpected import ID %q, expected %v", id, format)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("unexpected import ID %q, expected %v", id, format)
+		}
+	}
+	return parts, nil
+}
+
+func importInt(value string, name string) (int, error) {
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%v must be a number, got %q", name, value)
+	}
+	return i, nil
+}
+
+// importRoles sets the roles of the import ID (e.g. "leafs,borders") and marks the devices as applied
+func (c *providerClient) importRoles(d *schema.ResourceData, roles string) error {
+	list := []interface{}{}
+	for _, role := range strings.Split(roles, ",") {
+		list = append(list, role)
+	}
+	if err := d.Set("roles", list); err != nil {
+		return err
+	}
+
+	devices := make(map[string]*deviceState)
+	for host, role := range c.roleHosts(list) {
+		devices[host] = &deviceState{
+			Role:      role,
+			Status:    deviceApplied,
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+		}
+	}
+	if len(devices) == 0 {
+		return fmt.Errorf("no devices found with roles: %v", roles)
+	}
+	return setDevices(d, devices)
+}
+
+// importRead reads the resource from the devices after the keys have been set by the importer
+func importRead(ctx context.Context, d *schema.ResourceData, meta interface{}, read schema.ReadContextFunc) ([]*schema.ResourceData, error) {
+	if diags := read(ctx, d, meta); diags.HasError() {
+		for _, v := range diags {
+			if v.Severity == diag.Error {
+				return nil, fmt.Errorf("%v: %v", v.Summary, v.Detail)
+			}
+		}
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("resource not found on the devices")
+	}
+	return []*schema.ResourceData{d}, nil
+}
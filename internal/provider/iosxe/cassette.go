@@ -0,0 +1,284 @@
+package iosxe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
+)
+
+// interaction is a request to a device and its response, the status is the HTTP status of RESTCONF
+// (NETCONF has none, only the status of an error is kept)
+type interaction struct {
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	Request  string `json:"request,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Response string `json:"response,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// cassette holds the interactions with a host, stored as <cassette_dir>/<host>.json
+type cassette struct {
+	Host         string         `json:"host"`
+	Interactions []*interaction `json:"interactions"`
+
+	mu     sync.Mutex
+	file   string
+	cursor int
+}
+
+var (
+	cassettesMu sync.Mutex
+	cassettes   = make(map[string]*cassette)
+)
+
+// openCassette returns the cassette of the host, shared by all transports of the provider process
+func openCassette(mode, dir, host string) (*cassette, error) {
+	cassettesMu.Lock()
+	defer cassettesMu.Unlock()
+	file := filepath.Join(dir, strings.NewReplacer(":", "_", "/", "_").Replace(host)+".json")
+	if c, ok := cassettes[mode+file]; ok {
+		return c, nil
+	}
+	c := &cassette{Host: host, file: file}
+	b, err := os.ReadFile(file)
+	switch {
+	case err == nil:
+		if err = json.Unmarshal(b, c); err != nil {
+			return nil, fmt.Errorf("cassette %v: %v", file, err)
+		}
+	case errors.Is(err, os.ErrNotExist) && mode == "record":
+	default:
+		return nil, fmt.Errorf("cassette of %v: %v", host, err)
+	}
+	cassettes[mode+file] = c
+	return c, nil
+}
+
+// record appends the interaction, the cassette is written after every request so a failed apply is kept
+func (c *cassette) record(i *interaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Interactions = append(c.Interactions, i)
+	if err := os.MkdirAll(filepath.Dir(c.file), os.ModePerm); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(c, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.file, b, 0600)
+}
+
+// replay returns the next recorded response of the request, the requests of the plan and the apply are
+// recorded in the same cassette so later interactions are searched first
+func (c *cassette) replay(method, path, request string) (*interaction, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := len(c.Interactions)
+	for k := 0; k < n; k++ {
+		j := (c.cursor + k) % n
+		i := c.Interactions[j]
+		if i.Method != method || i.Path != path {
+			continue
+		}
+		if i.Request != request {
+			log.Printf("[WARN] Replay of %v %v on %v with a different payload than recorded\n", method, path, c.Host)
+		}
+		c.cursor = j + 1
+		return i, nil
+	}
+	return nil, fmt.Errorf("no recorded response of %v %v on %v in %v", method, path, c.Host, c.file)
+}
+
+// redactor removes the credentials of the provider from the recorded requests and responses.
+// The username isn't a secret, replacing it everywhere would corrupt paths and values such as admin-status.
+type redactor struct {
+	secrets []string
+}
+
+var authorizationRe = regexp.MustCompile(`(?i)(authorization"?\s*[:=]\s*"?)(basic|bearer)\s+[A-Za-z0-9+/=._-]+`)
+
+func newRedactor(cfg *service.Config) *redactor {
+	r := &redactor{}
+	for _, v := range []string{cfg.Password, cfg.ProxyCreds} {
+		if v != "" {
+			r.secrets = append(r.secrets, v)
+		}
+	}
+	return r
+}
+
+func (r *redactor) redact(s string) string {
+	s = authorizationRe.ReplaceAllString(s, "${1}${2} REDACTED")
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, "REDACTED")
+	}
+	return s
+}
+
+// recordTransport records the requests of a transport in the cassette of the host
+type recordTransport struct {
+	next     service.Transport
+	cassette *cassette
+	redactor *redactor
+}
+
+func newRecordTransport(host string, cfg *service.Config, next service.Transport) (*recordTransport, error) {
+	c, err := openCassette("record", cfg.CassetteDir, host)
+	if err != nil {
+		next.Close()
+		return nil, err
+	}
+	return &recordTransport{
+		next:     next,
+		cassette: c,
+		redactor: newRedactor(cfg),
+	}, nil
+}
+
+// statusTransport is a transport which knows the HTTP status of its last response
+type statusTransport interface {
+	LastStatus() int
+}
+
+func (t *recordTransport) record(method, path, request, response string, err error) {
+	i := &interaction{
+		Method:   method,
+		Path:     t.redactor.redact(path),
+		Request:  t.redactor.redact(request),
+		Response: t.redactor.redact(response),
+	}
+	if st, ok := t.next.(statusTransport); ok {
+		i.Status = st.LastStatus()
+	}
+	if err != nil {
+		if i.Status == 0 {
+			i.Status = statusCode(err)
+		}
+		if i.Status == 0 && errors.Is(err, ErrNotFound) {
+			i.Status = http.StatusNotFound
+		}
+		i.Error = t.redactor.redact(err.Error())
+	}
+	if err := t.cassette.record(i); err != nil {
+		log.Println("[WARN] Can't record cassette ", err)
+	}
+}
+
+func (t *recordTransport) Get(ctx context.Context, path string) (string, error) {
+	body, err := t.next.Get(ctx, path)
+	t.record(http.MethodGet, path, "", body, err)
+	return body, err
+}
+
+func (t *recordTransport) Merge(ctx context.Context, path string, payload string) error {
+	err := t.next.Merge(ctx, path, payload)
+	t.record(http.MethodPatch, path, payload, "", err)
+	return err
+}
+
+func (t *recordTransport) Replace(ctx context.Context, path string, payload string) error {
+	err := t.next.Replace(ctx, path, payload)
+	t.record(http.MethodPut, path, payload, "", err)
+	return err
+}
+
+func (t *recordTransport) Post(ctx context.Context, path string, payload string) (string, error) {
+	body, err := t.next.Post(ctx, path, payload)
+	t.record(http.MethodPost, path, payload, body, err)
+	return body, err
+}
+
+func (t *recordTransport) Delete(ctx context.Context, path string) error {
+	err := t.next.Delete(ctx, path)
+	t.record(http.MethodDelete, path, "", "", err)
+	return err
+}
+
+func (t *recordTransport) Close() error {
+	return t.next.Close()
+}
+
+// replayError is a recorded error, it keeps the status so the retries and not found handling are replayed
+type replayError struct {
+	Status  int
+	Message string
+}
+
+func (e *replayError) Error() string {
+	return e.Message
+}
+
+func (e *replayError) StatusCode() int {
+	return e.Status
+}
+
+func (e *replayError) Is(target error) bool {
+	return target == ErrNotFound && e.Status == http.StatusNotFound
+}
+
+// replayTransport answers the requests from the cassette of the host without connecting to it
+type replayTransport struct {
+	cassette *cassette
+	redactor *redactor
+}
+
+func newReplayTransport(host string, cfg *service.Config) (*replayTransport, error) {
+	c, err := openCassette("replay", cfg.CassetteDir, host)
+	if err != nil {
+		return nil, err
+	}
+	return &replayTransport{
+		cassette: c,
+		redactor: newRedactor(cfg),
+	}, nil
+}
+
+func (t *replayTransport) replay(method, path, request string) (string, error) {
+	i, err := t.cassette.replay(method, t.redactor.redact(path), t.redactor.redact(request))
+	if err != nil {
+		return "", err
+	}
+	if i.Error != "" {
+		return "", &replayError{Status: i.Status, Message: i.Error}
+	}
+	return i.Response, nil
+}
+
+func (t *replayTransport) Get(ctx context.Context, path string) (string, error) {
+	return t.replay(http.MethodGet, path, "")
+}
+
+func (t *replayTransport) Merge(ctx context.Context, path string, payload string) error {
+	_, err := t.replay(http.MethodPatch, path, payload)
+	return err
+}
+
+func (t *replayTransport) Replace(ctx context.Context, path string, payload string) error {
+	_, err := t.replay(http.MethodPut, path, payload)
+	return err
+}
+
+func (t *replayTransport) Post(ctx context.Context, path string, payload string) (string, error) {
+	return t.replay(http.MethodPost, path, payload)
+}
+
+func (t *replayTransport) Delete(ctx context.Context, path string) error {
+	_, err := t.replay(http.MethodDelete, path, "")
+	return err
+}
+
+func (t *replayTransport) Close() error {
+	return nil
+}
@@ -0,0 +1,49 @@
+package iosxe
+
+import (
+	"testing"
+
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
+)
+
+func TestRedact(t *testing.T) {
+	r := newRedactor(&service.Config{
+		Username:   "admin",
+		Password:   "s3cret",
+		ProxyCreds: "proxy:pr0xy",
+	})
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			// the username is kept, it's part of paths and values
+			name: "username",
+			in:   `/data/Cisco-IOS-XE-native:native/interface/Loopback=100/Cisco-IOS-XE-cisco-ia:admin-status`,
+			want: `/data/Cisco-IOS-XE-native:native/interface/Loopback=100/Cisco-IOS-XE-cisco-ia:admin-status`,
+		},
+		{
+			name: "password",
+			in:   `{"username":[{"name":"admin","password":"s3cret"}]}`,
+			want: `{"username":[{"name":"admin","password":"REDACTED"}]}`,
+		},
+		{
+			name: "proxy_creds",
+			in:   `proxyconnect tcp: proxy:pr0xy@10.0.0.1:3128`,
+			want: `proxyconnect tcp: REDACTED@10.0.0.1:3128`,
+		},
+		{
+			name: "authorization",
+			in:   `"Authorization": "Basic YWRtaW46czNjcmV0"`,
+			want: `"Authorization": "Basic REDACTED"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.redact(tt.in); got != tt.want {
+				t.Errorf("redact(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
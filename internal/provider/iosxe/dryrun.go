@@ -0,0 +1,81 @@
+package iosxe
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Operation is a request to a device which isn't sent in dry run mode
+type Operation struct {
+	Host      string `json:"host"`
+	Role      string `json:"role,omitempty"`
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	Payload   string `json:"payload,omitempty"`
+	Timestamp string `json:"timestamp"`
+}
+
+// planReport collects the operations of the provider process in the plan report file
+type planReport struct {
+	Operations []Operation `json:"operations"`
+}
+
+var (
+	planReportMu sync.Mutex
+	planReports  = make(map[string]*planReport)
+)
+
+// render adds the request to the plan report instead of sending it, the payload is returned as body
+func (s *sessionClient) render(method string) (string, error) {
+	log.Printf("[DEBUG] IOS-XE dry run %v on: %v %v\n", method, s.Host, s.Service.Path)
+	file := s.Service.Config.PlanReport
+	op := Operation{
+		Host:      s.Host,
+		Role:      s.Service.Role,
+		Method:    strings.Replace(method, "UPDATE", "PUT", 1),
+		Path:      s.Service.Path,
+		Payload:   s.Service.Payload,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	if err := reportOperation(file, op); err != nil {
+		return "", err
+	}
+	return s.Service.Payload, nil
+}
+
+// reportOperation appends the operation to the report, the file is rewritten so it is complete after a failed apply.
+// Operations of earlier runs in the file are kept, remove the file before a new dry run.
+func reportOperation(file string, op Operation) error {
+	if file == "" {
+		return nil
+	}
+	planReportMu.Lock()
+	defer planReportMu.Unlock()
+	r, ok := planReports[file]
+	if !ok {
+		r = &planReport{}
+		if b, err := os.ReadFile(file); err == nil {
+			if err = json.Unmarshal(b, r); err != nil {
+				log.Printf("[WARN] Plan report %v isn't valid, starting a new one: %v\n", file, err)
+				r.Operations = nil
+			}
+		}
+		planReports[file] = r
+	}
+	r.Operations = append(r.Operations, op)
+	if dir := filepath.Dir(file); dir != "." {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return err
+		}
+	}
+	b, err := json.MarshalIndent(r, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, b, 0644)
+}
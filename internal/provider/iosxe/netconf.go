@@ -0,0 +1,425 @@
+package iosxe
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	netconfNamespace       = "urn:ietf:params:xml:ns:netconf:base:1.0"
+	netconfBase10          = "urn:ietf:params:netconf:base:1.0"
+	netconfBase11          = "urn:ietf:params:netconf:base:1.1"
+	netconfCandidate       = "urn:ietf:params:netconf:capability:candidate:1.0"
+	netconfConfirmedCommit = "urn:ietf:params:netconf:capability:confirmed-commit:1.1"
+	netconfEndOfMessage    = "]]>]]>"
+)
+
+// netconfTransport sends the requests as NETCONF RPCs over SSH.
+// The RESTCONF paths and YANG JSON payloads are translated to XML, see netconf_xml.go.
+type netconfTransport struct {
+	Host           string
+	Datastore      string
+	ConfirmTimeout int
+	Timeout        time.Duration
+
+	client       *ssh.Client
+	session      *ssh.Session
+	stdin        io.WriteCloser
+	stdout       *bufio.Reader
+	chunked      bool
+	capabilities map[string]bool
+	messageID    int
+}
+
+type netconfHello struct {
+	XMLName      xml.Name `xml:"urn:ietf:params:xml:ns:netconf:base:1.0 hello"`
+	Capabilities []string `xml:"capabilities>capability"`
+}
+
+type netconfReply struct {
+	XMLName xml.Name       `xml:"rpc-reply"`
+	Errors  []netconfError `xml:"rpc-error"`
+	Data    xmlNode        `xml:"data"`
+	Body    []xmlNode      `xml:",any"`
+}
+
+// netconfError is a rpc-error of a NETCONF reply
+type netconfError struct {
+	Type     string `xml:"error-type"`
+	Tag      string `xml:"error-tag"`
+	Severity string `xml:"error-severity"`
+	Path     string `xml:"error-path"`
+	Message  string `xml:"error-message"`
+}
+
+func (e *netconfError) Error() string {
+	msg := fmt.Sprintf("netconf %v: %v", e.Tag, strings.TrimSpace(e.Message))
+	if path := strings.TrimSpace(e.Path); path != "" {
+		msg += fmt.Sprintf(" (%v)", path)
+	}
+	return msg
+}
+
+// StatusCode maps the error-tag to the HTTP status RESTCONF would have returned, used by the retry policy
+func (e *netconfError) StatusCode() int {
+	switch e.Tag {
+	case "in-use", "lock-denied":
+		return 409
+	case "resource-denied":
+		return 503
+	case "data-missing":
+		return 404
+	}
+	return 0
+}
+
+func (e *netconfError) Is(target error) bool {
+	return target == ErrNotFound && e.Tag == "data-missing"
+}
+
+func newNetconfTransport(host string, cfg *service.Config) (*netconfTransport, error) {
+	t := &netconfTransport{
+		Host:         host,
+		Datastore:    "running",
+		Timeout:      30 * time.Second,
+		capabilities: make(map[string]bool),
+	}
+	if cfg.NetconfDatastore != "" {
+		t.Datastore = cfg.NetconfDatastore
+	}
+	t.ConfirmTimeout = cfg.NetconfConfirmTimeout
+	if cfg.Timeout > 0 {
+		t.Timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+	port := 830
+	if cfg.NetconfPort > 0 {
+		port = cfg.NetconfPort
+	}
+
+	hostKey, err := netconfHostKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+	password := cfg.Password
+	config := &ssh.ClientConfig{
+		User: cfg.Username,
+		Auth: []ssh.AuthMethod{
+			ssh.Password(password),
+			ssh.KeyboardInteractive(func(user, instruction string, questions []string, echos []bool) ([]string, error) {
+				answers := make([]string, len(questions))
+				for i := range answers {
+					answers[i] = password
+				}
+				return answers, nil
+			}),
+		},
+		HostKeyCallback: hostKey,
+		Timeout:         t.Timeout,
+	}
+
+	// The host of the roles can include the RESTCONF port
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+	log.Printf("[DEBUG] NETCONF connecting to: %v:%v\n", hostname, port)
+	if t.client, err = ssh.Dial("tcp", net.JoinHostPort(hostname, strconv.Itoa(port)), config); err != nil {
+		return nil, fmt.Errorf("netconf ssh to %v: %w", host, err)
+	}
+	if err = t.open(); err != nil {
+		t.Close()
+		return nil, fmt.Errorf("netconf session to %v: %w", host, err)
+	}
+	if t.Datastore == "candidate" && !t.capabilities[netconfCandidate] {
+		t.Close()
+		return nil, fmt.Errorf("netconf candidate datastore not supported on %v", host)
+	}
+	return t, nil
+}
+
+// netconfHostKey verifies the host key with the known hosts file, unless insecure is set
+func netconfHostKey(cfg *service.Config) (ssh.HostKeyCallback, error) {
+	if cfg.Insecure {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+	file := cfg.KnownHostsFile
+	if file == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		file = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	return knownhosts.New(file)
+}
+
+// open starts the netconf subsystem and exchanges the hello messages
+func (t *netconfTransport) open() error {
+	var err error
+	if t.session, err = t.client.NewSession(); err != nil {
+		return err
+	}
+	if t.stdin, err = t.session.StdinPipe(); err != nil {
+		return err
+	}
+	stdout, err := t.session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	t.stdout = bufio.NewReader(stdout)
+	if err = t.session.RequestSubsystem("netconf"); err != nil {
+		return err
+	}
+
+	hello, err := xml.Marshal(&netconfHello{Capabilities: []string{netconfBase10, netconfBase11}})
+	if err != nil {
+		return err
+	}
+	if err = t.send(hello); err != nil {
+		return err
+	}
+	msg, err := t.receive()
+	if err != nil {
+		return err
+	}
+	server := &netconfHello{}
+	if err = xml.Unmarshal(msg, server); err != nil {
+		return fmt.Errorf("invalid hello: %v", err)
+	}
+	for _, c := range server.Capabilities {
+		t.capabilities[strings.TrimSpace(c)] = true
+	}
+	// Chunked framing once both peers announced base:1.1, RFC 6242
+	t.chunked = t.capabilities[netconfBase11]
+	return nil
+}
+
+func (t *netconfTransport) send(msg []byte) error {
+	var err error
+	if t.chunked {
+		_, err = fmt.Fprintf(t.stdin, "\n#%d\n%s\n##\n", len(msg), msg)
+	} else {
+		_, err = fmt.Fprintf(t.stdin, "%s%s", msg, netconfEndOfMessage)
+	}
+	return err
+}
+
+func (t *netconfTransport) receive() ([]byte, error) {
+	if t.chunked {
+		return t.receiveChunked()
+	}
+	var msg []byte
+	for {
+		b, err := t.stdout.ReadBytes('>')
+		msg = append(msg, b...)
+		if bytes.HasSuffix(msg, []byte(netconfEndOfMessage)) {
+			return bytes.TrimSuffix(msg, []byte(netconfEndOfMessage)), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *netconfTransport) receiveChunked() ([]byte, error) {
+	var msg []byte
+	for {
+		header, err := t.stdout.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(header) == "" {
+			continue
+		}
+		header = strings.TrimSpace(header)
+		if header == "##" {
+			return msg, nil
+		}
+		size, err := strconv.Atoi(strings.TrimPrefix(header, "#"))
+		if !strings.HasPrefix(header, "#") || err != nil || size <= 0 {
+			return nil, fmt.Errorf("invalid chunk header: %q", header)
+		}
+		chunk := make([]byte, size)
+		if _, err = io.ReadFull(t.stdout, chunk); err != nil {
+			return nil, err
+		}
+		msg = append(msg, chunk...)
+	}
+}
+
+// rpc sends the operation and returns the reply, the session is closed when the context is done
+func (t *netconfTransport) rpc(ctx context.Context, operation string) (*netconfReply, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.Timeout)
+	defer cancel()
+
+	t.messageID++
+	msg := fmt.Sprintf(`<rpc message-id="%d" xmlns="%v">%v</rpc>`, t.messageID, netconfNamespace, operation)
+	log.Printf("[DEBUG] NETCONF RPC on %v: %v\n", t.Host, msg)
+
+	type result struct {
+		msg []byte
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		if err := t.send([]byte(msg)); err != nil {
+			done <- result{err: err}
+			return
+		}
+		msg, err := t.receive()
+		done <- result{msg: msg, err: err}
+	}()
+
+	var r result
+	select {
+	case <-ctx.Done():
+		t.Close()
+		return nil, fmt.Errorf("netconf rpc on %v: %w", t.Host, ctx.Err())
+	case r = <-done:
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("netconf rpc on %v: %w", t.Host, r.err)
+	}
+
+	reply := &netconfReply{}
+	if err := xml.Unmarshal(r.msg, reply); err != nil {
+		return nil, fmt.Errorf("invalid netconf reply from %v: %v", t.Host, err)
+	}
+	for i := range reply.Errors {
+		if reply.Errors[i].Severity != "warning" {
+			return reply, &reply.Errors[i]
+		}
+		log.Printf("[DEBUG] NETCONF warning on %v: %v\n", t.Host, reply.Errors[i].Error())
+	}
+	return reply, nil
+}
+
+func (t *netconfTransport) Get(ctx context.Context, path string) (string, error) {
+	p, err := parseYangPath(path)
+	if err != nil {
+		return "", err
+	}
+	filter := fmt.Sprintf(`<filter type="subtree">%v</filter>`, p.filter())
+	operation := fmt.Sprintf("<get-config><source><running/></source>%v</get-config>", filter)
+	if p.oper() {
+		operation = fmt.Sprintf("<get>%v</get>", filter)
+	}
+	reply, err := t.rpc(ctx, operation)
+	if err != nil {
+		return "", err
+	}
+	body, ok := p.json(reply.Data.Children)
+	if !ok {
+		return "", fmt.Errorf("%w: %v on %v", ErrNotFound, path, t.Host)
+	}
+	return body, nil
+}
+
+func (t *netconfTransport) Merge(ctx context.Context, path string, payload string) error {
+	return t.edit(ctx, path, payload, "")
+}
+
+func (t *netconfTransport) Replace(ctx context.Context, path string, payload string) error {
+	return t.edit(ctx, path, payload, "replace")
+}
+
+func (t *netconfTransport) Post(ctx context.Context, path string, payload string) (string, error) {
+	p, err := parseYangPath(path)
+	if err != nil {
+		return "", err
+	}
+	if !p.Operation {
+		return "", t.edit(ctx, path, payload, "create")
+	}
+	operation, err := p.rpcInput(payload)
+	if err != nil {
+		return "", err
+	}
+	reply, err := t.rpc(ctx, operation)
+	if err != nil {
+		return "", err
+	}
+	return p.rpcOutput(reply.Body), nil
+}
+
+func (t *netconfTransport) Delete(ctx context.Context, path string) error {
+	return t.edit(ctx, path, "", "delete")
+}
+
+// edit sends the edit-config to the datastore, the candidate is locked and committed.
+// With a confirm timeout the commit is confirmed, the device rolls back when the confirming commit never arrives.
+func (t *netconfTransport) edit(ctx context.Context, path string, payload string, operation string) error {
+	p, err := parseYangPath(path)
+	if err != nil {
+		return err
+	}
+	config, err := p.config(payload, operation)
+	if err != nil {
+		return err
+	}
+	edit := fmt.Sprintf(`<edit-config><target><%v/></target><config xmlns:nc="%v">%v</config></edit-config>`, t.Datastore, netconfNamespace, config)
+	if t.Datastore != "candidate" {
+		_, err = t.rpc(ctx, edit)
+		return err
+	}
+
+	if _, err = t.rpc(ctx, "<lock><target><candidate/></target></lock>"); err != nil {
+		return err
+	}
+	defer func() {
+		if _, err := t.rpc(context.Background(), "<unlock><target><candidate/></target></unlock>"); err != nil {
+			log.Println("[DEBUG] NETCONF unable to unlock candidate: ", err)
+		}
+	}()
+	if err = t.commit(ctx, edit); err != nil {
+		if _, derr := t.rpc(context.Background(), "<discard-changes/>"); derr != nil {
+			log.Println("[DEBUG] NETCONF unable to discard changes: ", derr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (t *netconfTransport) commit(ctx context.Context, edit string) error {
+	if _, err := t.rpc(ctx, edit); err != nil {
+		return err
+	}
+	if t.ConfirmTimeout <= 0 {
+		_, err := t.rpc(ctx, "<commit/>")
+		return err
+	}
+	if !t.capabilities[netconfConfirmedCommit] {
+		return errors.New("netconf confirmed-commit not supported on " + t.Host)
+	}
+	if _, err := t.rpc(ctx, fmt.Sprintf("<commit><confirmed/><confirm-timeout>%d</confirm-timeout></commit>", t.ConfirmTimeout)); err != nil {
+		return err
+	}
+	_, err := t.rpc(ctx, "<commit/>")
+	return err
+}
+
+func (t *netconfTransport) Close() error {
+	if t.session != nil {
+		t.session.Close()
+	}
+	if t.client != nil {
+		return t.client.Close()
+	}
+	return nil
+}
@@ -0,0 +1,531 @@
+package iosxe
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const yangNative = "Cisco-IOS-XE-native"
+
+// yangNamespaces are the XML namespaces of the modules which don't follow http://cisco.com/ns/yang/<module>
+var yangNamespaces = map[string]string{
+	"cisco-ia": "http://cisco.com/yang/cisco-ia",
+}
+
+// yangAugments are the modules augmenting the native model, the RESTCONF paths of the resources don't prefix them
+var yangAugments = map[string]string{
+	"bgp":                   "Cisco-IOS-XE-bgp",
+	"evpn":                  "Cisco-IOS-XE-l2vpn",
+	"evpn-instance":         "Cisco-IOS-XE-l2vpn",
+	"evpn-ethernet-segment": "Cisco-IOS-XE-l2vpn",
+	"relay":                 "Cisco-IOS-XE-dhcp",
+	"configuration-entry":   "Cisco-IOS-XE-vlan",
+	"vlan-list":             "Cisco-IOS-XE-vlan",
+}
+
+// yangLists are the lists used by the models with their keys, XML doesn't tell a list with one entry from a container
+var yangLists = map[string][]string{
+	"GigabitEthernet":      {"name"},
+	"TenGigabitEthernet":   {"name"},
+	"TwentyFiveGigE":       {"name"},
+	"FortyGigabitEthernet": {"name"},
+	"HundredGigE":          {"name"},
+	"FourHundredGigE":      {"name"},
+	"Loopback":             {"name"},
+	"Vlan":                 {"name"},
+	"nve":                  {"name"},
+	"definition":           {"name"},
+	"vrf":                  {"name"},
+	"bgp":                  {"id"},
+	"neighbor":             {"id"},
+	"l2vpn":                {"af-name"},
+	"ipv4":                 {"af-name"},
+	"ipv6":                 {"af-name"},
+	"instance":             {"evpn-instance-num"},
+	"evpn-instance":        {"evpn-instance"},
+	"configuration-entry":  {"vlan-id"},
+	"vlan-list":            {"id"},
+	"helper-address":       {"address"},
+	"vni":                  {"vni-range"},
+	"nve-vni":              {"nve-instance", "vni"},
+	"nve-peers":            {"nve-instance", "peer-ip", "vni"},
+	"with-stitching":       {"vlan-id"},
+	"without-stitching":    {"vlan-id"},
+	"process-id":           {"id"},
+	"area":                 {"area-id"},
+	"isis":                 {"area-tag"},
+	"net":                  {"tag"},
+	"peer":                 {"addr"},
+	"ethernet-segment":     {"es-value"},
+	"Port-channel":         {"name"},
+}
+
+// ListKeys returns the keys of a YANG list of the models, nil when the name isn't a known list
+func ListKeys(name string) []string {
+	return yangLists[name]
+}
+
+var yangInteger = regexp.MustCompile(`^-?(0|[1-9][0-9]{0,17})$`)
+
+func yangNamespace(module string) string {
+	if ns, ok := yangNamespaces[module]; ok {
+		return ns
+	}
+	return "http://cisco.com/ns/yang/" + module
+}
+
+func yangModule(namespace string) string {
+	for module, ns := range yangNamespaces {
+		if ns == namespace {
+			return module
+		}
+	}
+	return strings.TrimPrefix(namespace, "http://cisco.com/ns/yang/")
+}
+
+// yangName splits the module prefix of a JSON member or path segment
+func yangName(name string, parent string) (string, string) {
+	if i := strings.Index(name, ":"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return parent, name
+}
+
+type yangNode struct {
+	Module string
+	Name   string
+	Keys   []string
+}
+
+// yangPath is a RESTCONF path, e.g. /data/Cisco-IOS-XE-native:native/interface/Loopback=100
+type yangPath struct {
+	Nodes     []yangNode
+	Fields    []string
+	Operation bool
+}
+
+func parseYangPath(path string) (*yangPath, error) {
+	p := &yangPath{}
+	if i := strings.Index(path, "?"); i >= 0 {
+		// the fields are separated by ";", which url.ParseQuery rejects
+		for _, param := range strings.Split(path[i+1:], "&") {
+			if !strings.HasPrefix(param, "fields=") {
+				continue
+			}
+			fields, err := url.QueryUnescape(strings.TrimPrefix(param, "fields="))
+			if err != nil {
+				return nil, err
+			}
+			for _, field := range strings.Split(fields, ";") {
+				if field != "" {
+					p.Fields = append(p.Fields, field)
+				}
+			}
+		}
+		path = path[:i]
+	}
+	switch {
+	case strings.HasPrefix(path, "/data/"):
+		path = strings.TrimPrefix(path, "/data/")
+	case strings.HasPrefix(path, "/operations/"):
+		path = strings.TrimPrefix(path, "/operations/")
+		p.Operation = true
+	default:
+		return nil, fmt.Errorf("unsupported path: %v", path)
+	}
+
+	module := ""
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" {
+			continue
+		}
+		node := yangNode{}
+		if i := strings.Index(segment, "="); i >= 0 {
+			for _, key := range strings.Split(segment[i+1:], ",") {
+				k, err := url.PathUnescape(key)
+				if err != nil {
+					return nil, err
+				}
+				node.Keys = append(node.Keys, k)
+			}
+			segment = segment[:i]
+		}
+		node.Module, node.Name = yangName(segment, module)
+		if augment, ok := yangAugments[segment]; ok && module == yangNative {
+			node.Module = augment
+		}
+		if node.Module == "" {
+			return nil, fmt.Errorf("missing module in path: %v", path)
+		}
+		module = node.Module
+		p.Nodes = append(p.Nodes, node)
+	}
+	if len(p.Nodes) == 0 {
+		return nil, fmt.Errorf("empty path: %v", path)
+	}
+	return p, nil
+}
+
+func (p *yangPath) last() yangNode {
+	return p.Nodes[len(p.Nodes)-1]
+}
+
+// oper returns true for operational data, which isn't part of the config datastores
+func (p *yangPath) oper() bool {
+	return strings.HasSuffix(p.Nodes[0].Module, "-oper")
+}
+
+func keyNames(node yangNode) []string {
+	names := append([]string{}, yangLists[node.Name]...)
+	for len(names) < len(node.Keys) {
+		names = append(names, "name")
+	}
+	return names
+}
+
+func openTag(buf *bytes.Buffer, name, module, parent, attr string) {
+	buf.WriteString("<" + name)
+	if module != parent {
+		fmt.Fprintf(buf, ` xmlns="%v"`, yangNamespace(module))
+	}
+	buf.WriteString(attr + ">")
+}
+
+func writeText(buf *bytes.Buffer, name, text string) {
+	buf.WriteString("<" + name + ">")
+	xml.EscapeText(buf, []byte(text))
+	buf.WriteString("</" + name + ">")
+}
+
+// nodes writes the elements of the path nodes and returns the closing tags, attr is set on the last node
+func (p *yangPath) nodes(buf *bytes.Buffer, nodes []yangNode, attr string) string {
+	closing := ""
+	parent := ""
+	for i, node := range nodes {
+		a := ""
+		if i == len(nodes)-1 {
+			a = attr
+		}
+		openTag(buf, node.Name, node.Module, parent, a)
+		for j, key := range keyNames(node)[:len(node.Keys)] {
+			writeText(buf, key, node.Keys[j])
+		}
+		closing = "</" + node.Name + ">" + closing
+		parent = node.Module
+	}
+	return closing
+}
+
+// filter returns the subtree filter of the path
+func (p *yangPath) filter() string {
+	buf := &bytes.Buffer{}
+	closing := p.nodes(buf, p.Nodes, "")
+	for _, field := range p.Fields {
+		buf.WriteString("<" + field + "/>")
+	}
+	buf.WriteString(closing)
+	return buf.String()
+}
+
+// config returns the config of the edit-config, the payload is the YANG JSON of the last node of the path.
+// Without payload the last node of the path is the target of the operation.
+func (p *yangPath) config(payload string, operation string) (string, error) {
+	attr := ""
+	if operation != "" {
+		attr = fmt.Sprintf(` nc:operation="%v"`, operation)
+	}
+	buf := &bytes.Buffer{}
+	if payload == "" {
+		closing := p.nodes(buf, p.Nodes, attr)
+		buf.WriteString(closing)
+		return buf.String(), nil
+	}
+
+	root, err := payloadRoot(payload)
+	if err != nil {
+		return "", err
+	}
+	nodes := p.Nodes
+	if _, name := yangName(root, ""); name == p.last().Name {
+		nodes = nodes[:len(nodes)-1]
+	}
+	closing := p.nodes(buf, nodes, "")
+	module := ""
+	if len(nodes) > 0 {
+		module = nodes[len(nodes)-1].Module
+	}
+
+	dec := json.NewDecoder(strings.NewReader(payload))
+	dec.UseNumber()
+	if _, err = dec.Token(); err != nil {
+		return "", err
+	}
+	for dec.More() {
+		if err = jsonMember(buf, dec, module, attr); err != nil {
+			return "", err
+		}
+	}
+	buf.WriteString(closing)
+	return buf.String(), nil
+}
+
+// rpcInput returns the RPC of an /operations path with the input of the payload
+func (p *yangPath) rpcInput(payload string) (string, error) {
+	node := p.last()
+	buf := &bytes.Buffer{}
+	if strings.TrimSpace(payload) == "" {
+		openTag(buf, node.Name, node.Module, "", "")
+		buf.WriteString("</" + node.Name + ">")
+		return buf.String(), nil
+	}
+	dec := json.NewDecoder(strings.NewReader(payload))
+	dec.UseNumber()
+	if _, err := dec.Token(); err != nil {
+		return "", err
+	}
+	if _, err := dec.Token(); err != nil {
+		return "", err
+	}
+	if err := jsonValue(buf, dec, node.Name, node.Module, "", ""); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// rpcOutput returns the YANG JSON output of the RPC reply
+func (p *yangPath) rpcOutput(body []xmlNode) string {
+	module := p.last().Module
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, `{"%v:output":`, module)
+	writeJSONObject(buf, body, yangNamespace(module))
+	buf.WriteString("}")
+	return buf.String()
+}
+
+// json returns the YANG JSON of the last node of the path in the data of a reply
+func (p *yangPath) json(data []xmlNode) (string, bool) {
+	matches := data
+	for i, node := range p.Nodes {
+		if i > 0 {
+			children := []xmlNode{}
+			for _, m := range matches {
+				children = append(children, m.Children...)
+			}
+			matches = children
+		}
+		matches = node.match(matches)
+		if len(matches) == 0 {
+			return "", false
+		}
+	}
+
+	node := p.last()
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, `{"%v:%v":`, yangModule(matches[0].XMLName.Space), node.Name)
+	if listEntry(matches[0]) || len(node.Keys) > 0 || len(matches) > 1 {
+		buf.WriteString("[")
+		for i, m := range matches {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			writeJSONValue(buf, m)
+		}
+		buf.WriteString("]")
+	} else {
+		writeJSONValue(buf, matches[0])
+	}
+	buf.WriteString("}")
+	return buf.String(), true
+}
+
+// match returns the elements of the node, list entries have to match the keys
+func (node yangNode) match(elements []xmlNode) []xmlNode {
+	matches := []xmlNode{}
+	names := keyNames(node)
+	for _, e := range elements {
+		if e.XMLName.Local != node.Name {
+			continue
+		}
+		ok := true
+		for i, key := range node.Keys {
+			if e.child(names[i]) != key {
+				ok = false
+			}
+		}
+		if ok {
+			matches = append(matches, e)
+		}
+	}
+	return matches
+}
+
+// payloadRoot returns the first member of the payload
+func payloadRoot(payload string) (string, error) {
+	dec := json.NewDecoder(strings.NewReader(payload))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return "", fmt.Errorf("invalid payload: %v", err)
+	}
+	t, err := dec.Token()
+	if err != nil {
+		return "", err
+	}
+	root, ok := t.(string)
+	if !ok {
+		return "", fmt.Errorf("invalid payload")
+	}
+	return root, nil
+}
+
+// jsonMember writes the next member of the object as element
+func jsonMember(buf *bytes.Buffer, dec *json.Decoder, parent string, attr string) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	module, name := yangName(t.(string), parent)
+	if module == "" {
+		return fmt.Errorf("missing module of %v", name)
+	}
+	return jsonValue(buf, dec, name, module, parent, attr)
+}
+
+// jsonValue writes the next value as element, the entries of an array are repeated elements
+func jsonValue(buf *bytes.Buffer, dec *json.Decoder, name, module, parent, attr string) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch v := t.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			openTag(buf, name, module, parent, attr)
+			for dec.More() {
+				if err = jsonMember(buf, dec, module, ""); err != nil {
+					return err
+				}
+			}
+			if _, err = dec.Token(); err != nil {
+				return err
+			}
+			buf.WriteString("</" + name + ">")
+		case '[':
+			for dec.More() {
+				if err = jsonValue(buf, dec, name, module, parent, attr); err != nil {
+					return err
+				}
+			}
+			if _, err = dec.Token(); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("invalid payload at %v", name)
+		}
+	case nil:
+		// [null] is an empty leaf
+		openTag(buf, name, module, parent, attr)
+		buf.WriteString("</" + name + ">")
+	default:
+		openTag(buf, name, module, parent, attr)
+		xml.EscapeText(buf, []byte(fmt.Sprint(v)))
+		buf.WriteString("</" + name + ">")
+	}
+	return nil
+}
+
+// xmlNode is an element of a NETCONF reply
+type xmlNode struct {
+	XMLName  xml.Name
+	Content  string    `xml:",chardata"`
+	Children []xmlNode `xml:",any"`
+}
+
+func (n xmlNode) child(name string) string {
+	for _, c := range n.Children {
+		if c.XMLName.Local == name {
+			return strings.TrimSpace(c.Content)
+		}
+	}
+	return ""
+}
+
+// listEntry returns true when the element is an entry of a known list, containers with the same name lack the keys
+func listEntry(n xmlNode) bool {
+	keys, ok := yangLists[n.XMLName.Local]
+	if !ok || len(n.Children) == 0 {
+		return false
+	}
+	for _, key := range keys {
+		found := false
+		for _, c := range n.Children {
+			found = found || c.XMLName.Local == key
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func writeJSONValue(buf *bytes.Buffer, n xmlNode) {
+	if len(n.Children) > 0 {
+		writeJSONObject(buf, n.Children, n.XMLName.Space)
+		return
+	}
+	text := strings.TrimSpace(n.Content)
+	switch {
+	case text == "":
+		buf.WriteString("[null]")
+	case text == "true" || text == "false" || yangInteger.MatchString(text):
+		buf.WriteString(text)
+	default:
+		b, _ := json.Marshal(text)
+		buf.Write(b)
+	}
+}
+
+// writeJSONObject writes the elements as object, repeated elements and lists become arrays
+func writeJSONObject(buf *bytes.Buffer, elements []xmlNode, namespace string) {
+	names := []xml.Name{}
+	groups := make(map[xml.Name][]xmlNode)
+	for _, e := range elements {
+		if _, ok := groups[e.XMLName]; !ok {
+			names = append(names, e.XMLName)
+		}
+		groups[e.XMLName] = append(groups[e.XMLName], e)
+	}
+
+	buf.WriteString("{")
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		key := name.Local
+		if name.Space != "" && name.Space != namespace {
+			key = yangModule(name.Space) + ":" + key
+		}
+		b, _ := json.Marshal(key)
+		buf.Write(b)
+		buf.WriteString(":")
+
+		group := groups[name]
+		if len(group) > 1 || listEntry(group[0]) {
+			buf.WriteString("[")
+			for j, e := range group {
+				if j > 0 {
+					buf.WriteString(",")
+				}
+				writeJSONValue(buf, e)
+			}
+			buf.WriteString("]")
+		} else {
+			writeJSONValue(buf, group[0])
+		}
+	}
+	buf.WriteString("}")
+}
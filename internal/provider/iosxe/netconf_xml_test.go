@@ -0,0 +1,155 @@
+package iosxe
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden NETCONF XML in testdata")
+
+var netconfXMLTests = []struct {
+	name      string
+	path      string
+	payload   string
+	operation string
+}{
+	{
+		// lists of the Cisco-IOS-XE-bgp augment under the native model
+		name: "bgp_neighbors",
+		path: "/data/Cisco-IOS-XE-native:native/router/bgp=65534",
+		payload: `{"Cisco-IOS-XE-bgp:bgp":[{
+			"id":65534,
+			"bgp":{"default":{"ipv4-unicast":false},"router-id":{"interface":{"Loopback":100}}},
+			"neighbor":[
+				{"id":"100.119.11.1","remote-as":65534,"update-source":{"interface":{"Loopback":100}}},
+				{"id":"100.119.11.2","remote-as":65534,"update-source":{"interface":{"Loopback":100}}}],
+			"address-family":{"no-vrf":{"l2vpn":[{"af-name":"evpn","l2vpn-evpn":{"neighbor":[
+				{"id":"100.119.11.1","activate":[null],"send-community":{"send-community-where":"both"}}]}}]}}}]}`,
+	},
+	{
+		// the vlan container of the native model with the lists of the Cisco-IOS-XE-vlan augment
+		name: "vlan",
+		path: "/data/Cisco-IOS-XE-native:native/vlan",
+		payload: `{"Cisco-IOS-XE-native:vlan":{
+			"Cisco-IOS-XE-vlan:configuration-entry":[{"vlan-id":"101","member":{"evpn-instance":{"evpn-instance":101,"vni":10101}}}],
+			"Cisco-IOS-XE-vlan:vlan-list":[{"id":101,"name":"green & blue"},{"id":102,"name":"red"}]}}`,
+		operation: "replace",
+	},
+	{
+		// the payload is a child of the last node of the path
+		name:    "nve_member",
+		path:    "/data/Cisco-IOS-XE-native:native/interface/nve=1/member",
+		payload: `{"Cisco-IOS-XE-native:member":{"vni":[{"vni-range":"10101","mcast-group":{"multicast-group-min":"225.0.0.101"}}]}}`,
+	},
+	{
+		name:      "loopback_delete",
+		path:      "/data/Cisco-IOS-XE-native:native/interface/Loopback=100",
+		operation: "delete",
+	},
+	{
+		name:      "vni_delete",
+		path:      "/data/Cisco-IOS-XE-native:native/interface/nve=1/member/vni=10101",
+		operation: "delete",
+	},
+}
+
+// TestNetconfConfig compares the edit-config translated from the RESTCONF paths and YANG JSON with the golden files,
+// run with -update to regenerate them
+func TestNetconfConfig(t *testing.T) {
+	for _, tt := range netconfXMLTests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := parseYangPath(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := p.config(tt.payload, tt.operation)
+			if err != nil {
+				t.Fatal(err)
+			}
+			testGolden(t, filepath.Join("testdata", "netconf", tt.name+".xml"), []byte(got+"\n"))
+		})
+	}
+}
+
+// TestNetconfRPC compares the RPCs translated from the /operations paths and YANG JSON input with the golden files
+func TestNetconfRPC(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		payload string
+	}{
+		{
+			// cisco-ia doesn't follow the namespaces of the IOS-XE modules
+			name:    "rollback",
+			path:    "/operations/cisco-ia:rollback",
+			payload: `{"cisco-ia:input":{"target-url":"flash:ciscoevpn-1","verbose":true}}`,
+		},
+		{
+			name:    "copy",
+			path:    "/operations/Cisco-IOS-XE-rpc:copy",
+			payload: `{"Cisco-IOS-XE-rpc:input":{"source-drop-node-name":"running-config","destination-drop-node-name":"flash:ciscoevpn-1"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := parseYangPath(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := p.rpcInput(tt.payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			testGolden(t, filepath.Join("testdata", "netconf", tt.name+".xml"), []byte(got+"\n"))
+		})
+	}
+}
+
+// TestNetconfFilter compares the subtree filters of the GETs with the golden files
+func TestNetconfFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+	}{
+		{
+			name: "bgp_filter",
+			path: "/data/Cisco-IOS-XE-native:native/router/bgp=65534",
+		},
+		{
+			name: "nve_oper_filter",
+			path: "/data/Cisco-IOS-XE-nve-oper:nve-oper-data?fields=nve-vni;nve-peers",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := parseYangPath(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			testGolden(t, filepath.Join("testdata", "netconf", tt.name+".xml"), []byte(p.filter()+"\n"))
+		})
+	}
+}
+
+// testGolden compares got with the golden file, it's written first with -update
+func testGolden(t *testing.T, golden string, got []byte) {
+	t.Helper()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("%v, run go test -run 'TestNetconf' -update to create it", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%v differs, run go test -run 'TestNetconf' -update if the change is intended\ngot:\n%s\nwant:\n%s", golden, got, want)
+	}
+}
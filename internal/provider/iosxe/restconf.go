@@ -0,0 +1,135 @@
+package iosxe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/CiscoDevNet/iosxe-go-client/client"
+	"github.com/CiscoDevNet/iosxe-go-client/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
+)
+
+// restconfTransport sends the requests with the RESTCONF client of iosxe-go-client
+type restconfTransport struct {
+	Client *client.V2
+	Host   string
+	// status is the HTTP status of the last response, the requests of a transport are sent one at a time
+	status int
+}
+
+func newRestconfTransport(host string, cfg *service.Config) (*restconfTransport, error) {
+	c, err := cfg.HostClient(host, func() (interface{}, error) {
+		c, diags := NewClient(fmt.Sprintf("https://%v", host), cfg)
+		if diags.HasError() {
+			return nil, errors.New(diags[0].Detail)
+		}
+		return c, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &restconfTransport{
+		Client: c.(*client.V2),
+		Host:   host,
+	}, nil
+}
+
+// newClientMu serializes client.NewV2, it configures the TLS and proxy of http.DefaultTransport
+var newClientMu sync.Mutex
+
+// NewClient creates a client with a transport of its own, the clients of the hosts are created once and reused
+func NewClient(host string, cfg *service.Config) (*client.V2, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	newClientMu.Lock()
+	defer newClientMu.Unlock()
+	// NewV2 changes the TLS and proxy of http.DefaultTransport, it's given a clone so the other clients aren't changed
+	shared := http.DefaultTransport
+	http.DefaultTransport = shared.(*http.Transport).Clone()
+	defer func() { http.DefaultTransport = shared }()
+	iosxeV2Client, err := client.NewV2(
+		host,
+		cfg.Username,
+		cfg.Password,
+		cfg.Timeout,
+		cfg.Insecure,
+		cfg.CAFile,
+		cfg.ProxyURL,
+		cfg.ProxyCreds,
+	)
+
+	if err != nil {
+		log.Printf("[DEBUG] ERROR: %v\n", err)
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to create Cisco IOS-XE client",
+			Detail:   fmt.Sprintf("Unable to create Cisco IOSXE client. Error - %v", err),
+		})
+		return nil, diags
+	}
+	return iosxeV2Client, diags
+
+}
+
+func (t *restconfTransport) Get(ctx context.Context, path string) (string, error) {
+	resp, container, err := t.Client.Get(path, nil)
+	t.setStatus(resp)
+	if err != nil && strings.HasPrefix(err.Error(), "not-found") {
+		return "", fmt.Errorf("%w: %v on %v", ErrNotFound, path, t.Host)
+	}
+	if err != nil || container == nil {
+		return "", err
+	}
+	return container.String(), nil
+}
+
+func (t *restconfTransport) Merge(ctx context.Context, path string, payload string) error {
+	resp, _, err := t.Client.PatchRaw(path, payload)
+	t.setStatus(resp)
+	return err
+}
+
+func (t *restconfTransport) Replace(ctx context.Context, path string, payload string) error {
+	resp, err := t.Client.Update(path, &models.GenericModel{JSONPayload: payload})
+	t.setStatus(resp)
+	return err
+}
+
+func (t *restconfTransport) Post(ctx context.Context, path string, payload string) (string, error) {
+	resp, container, err := t.Client.Create(path, &models.GenericModel{JSONPayload: payload})
+	t.setStatus(resp)
+	if err != nil || container == nil {
+		return "", err
+	}
+	return container.String(), nil
+}
+
+func (t *restconfTransport) Delete(ctx context.Context, path string) error {
+	resp, err := t.Client.Delete(path)
+	t.setStatus(resp)
+	if err != nil && strings.HasPrefix(err.Error(), "not-found") {
+		return fmt.Errorf("%w: %v on %v", ErrNotFound, path, t.Host)
+	}
+	return err
+}
+
+func (t *restconfTransport) Close() error {
+	return nil
+}
+
+func (t *restconfTransport) setStatus(resp *http.Response) {
+	t.status = 0
+	if resp != nil {
+		t.status = resp.StatusCode
+	}
+}
+
+// LastStatus returns the HTTP status of the last response, 0 when the request got no response
+func (t *restconfTransport) LastStatus() int {
+	return t.status
+}
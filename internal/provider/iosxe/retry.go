@@ -0,0 +1,146 @@
+package iosxe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
+)
+
+var (
+	defaultRetryStatusCodes = []int{409, 503}
+	defaultRetryErrors      = []string{"connection reset", "TLS handshake timeout"}
+)
+
+// statusCodeRe finds the HTTP status in the errors of iosxe-go-client, GET doesn't return the response on errors
+var statusCodeRe = regexp.MustCompile(`(?:status code: |server-error: )(\d{3})`)
+
+// retryPolicy decides which requests are retried and how long to wait in between
+type retryPolicy struct {
+	Retries     int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+	MaxElapsed  time.Duration
+	StatusCodes map[int]bool
+	Errors      []string
+}
+
+func newRetryPolicy(cfg *service.Config) *retryPolicy {
+	p := &retryPolicy{
+		Retries:     cfg.Retries,
+		MinBackoff:  1 * time.Second,
+		MaxBackoff:  30 * time.Second,
+		MaxElapsed:  300 * time.Second,
+		StatusCodes: make(map[int]bool),
+		Errors:      defaultRetryErrors,
+	}
+	if cfg.RetryMinBackoff > 0 {
+		p.MinBackoff = time.Duration(cfg.RetryMinBackoff) * time.Second
+	}
+	if cfg.RetryMaxBackoff > 0 {
+		p.MaxBackoff = time.Duration(cfg.RetryMaxBackoff) * time.Second
+	}
+	if cfg.RetryMaxElapsed > 0 {
+		p.MaxElapsed = time.Duration(cfg.RetryMaxElapsed) * time.Second
+	}
+
+	codes := defaultRetryStatusCodes
+	if len(cfg.RetryStatusCodes) > 0 {
+		codes = cfg.RetryStatusCodes
+	}
+	for _, code := range codes {
+		p.StatusCodes[code] = true
+	}
+	if len(cfg.RetryErrors) > 0 {
+		p.Errors = cfg.RetryErrors
+	}
+	return p
+}
+
+// statusCoder is implemented by transport errors which map to a HTTP status
+type statusCoder interface {
+	StatusCode() int
+}
+
+func statusCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var sc statusCoder
+	if errors.As(err, &sc) {
+		return sc.StatusCode()
+	}
+	if m := statusCodeRe.FindStringSubmatch(err.Error()); m != nil {
+		code, _ := strconv.Atoi(m[1])
+		return code
+	}
+	return 0
+}
+
+func (p *retryPolicy) retryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if code := statusCode(err); code != 0 {
+		return p.StatusCodes[code]
+	}
+	for _, e := range p.Errors {
+		if strings.Contains(err.Error(), e) {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff doubles the wait for every attempt, with jitter in the upper half of the wait
+func (p *retryPolicy) backoff(attempt int) time.Duration {
+	wait := p.MaxBackoff
+	if attempt < 32 {
+		if w := p.MinBackoff << uint(attempt); w > 0 && w < p.MaxBackoff {
+			wait = w
+		}
+	}
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+// do runs the request until it succeeds, the error isn't retryable or the policy is exhausted.
+// The waits are cancelled with the context.
+func (p *retryPolicy) do(ctx context.Context, host string, request func() error) error {
+	start := time.Now()
+	for i := 0; ; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := request()
+		if !p.retryable(err) {
+			return err
+		}
+		if statusCode(err) == 409 {
+			log.Println("[DEBUG] IOS-XE configuration database is unavailable on: ", host)
+		}
+		if i >= p.Retries {
+			log.Printf("[DEBUG] IOS-XE Retries exhausted (%v) on: %v\n", p.Retries, host)
+			return err
+		}
+		wait := p.backoff(i)
+		if time.Since(start)+wait > p.MaxElapsed {
+			log.Printf("[DEBUG] IOS-XE Retry max elapsed time (%v) reached on: %v\n", p.MaxElapsed, host)
+			return err
+		}
+		log.Printf("[DEBUG] IOS-XE Retry: (%v/%v) on: %v waiting: %v error: %v\n", i+1, p.Retries, host, wait, err)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (retry cancelled: %v)", err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
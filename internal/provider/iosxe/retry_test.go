@@ -0,0 +1,99 @@
+package iosxe_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/iosxe"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/simulator"
+)
+
+func TestRetry(t *testing.T) {
+	path := "/data/Cisco-IOS-XE-native:native/interface/Loopback=100"
+	payload := `{"Cisco-IOS-XE-native:Loopback":[{"name":100,"description":"retry"}]}`
+
+	cases := []struct {
+		name     string
+		fail     func(d *simulator.Device)
+		retries  int
+		requests int
+		err      string
+	}{
+		{
+			name:     "409 then success",
+			fail:     func(d *simulator.Device) { d.LockDatabase(2) },
+			retries:  3,
+			requests: 3,
+		},
+		{
+			name:     "503 then success",
+			fail:     func(d *simulator.Device) { d.FailRequests("PATCH", "Loopback", 1, 503) },
+			retries:  3,
+			requests: 2,
+		},
+		{
+			name:     "409 until the retries are exhausted",
+			fail:     func(d *simulator.Device) { d.LockDatabase(10) },
+			retries:  2,
+			requests: 3,
+			err:      "409",
+		},
+		{
+			name:     "400 isn't retried",
+			fail:     func(d *simulator.Device) { d.FailRequests("PATCH", "Loopback", 1, 400) },
+			retries:  3,
+			requests: 1,
+			err:      "400",
+		},
+		{
+			name:     "404 isn't retried",
+			fail:     func(d *simulator.Device) { d.FailRequests("PATCH", "Loopback", 1, 404) },
+			retries:  3,
+			requests: 1,
+			err:      "not-found",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := simulator.NewDevice("admin", "simulator")
+			defer d.Close()
+			tc.fail(d)
+
+			cfg := &service.Config{
+				Username:        d.Username,
+				Password:        d.Password,
+				Timeout:         5,
+				Insecure:        true,
+				Retries:         tc.retries,
+				RetryMinBackoff: 1,
+				RetryMaxBackoff: 1,
+			}
+			_, err := iosxe.SingleSession(&service.Client{
+				Context: context.Background(),
+				Method:  "PATCH",
+				Path:    path,
+				Payload: payload,
+				Config:  cfg,
+				Device:  d.Host(),
+			})
+
+			if n := len(d.Requests()); n != tc.requests {
+				t.Errorf("got %v requests, want %v: %v", n, tc.requests, d.Requests())
+			}
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if body, ok := d.Get("Cisco-IOS-XE-native:native/interface/Loopback=100"); !ok || !strings.Contains(body, `"retry"`) {
+					t.Errorf("loopback not merged: %v", body)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("got error %v, want %v", err, tc.err)
+			}
+		})
+	}
+}
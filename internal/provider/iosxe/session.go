@@ -1,21 +1,60 @@
 package iosxe
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
-	"net/http"
-	"time"
+	"sort"
+	"sync"
 
-	"github.com/CiscoDevNet/iosxe-go-client/client"
-	"github.com/CiscoDevNet/iosxe-go-client/container"
-	"github.com/CiscoDevNet/iosxe-go-client/models"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
 )
 
+// ErrNotFound is returned when the path doesn't exist on the device
+var ErrNotFound = errors.New("not-found")
+
+// HostErrors collects the errors of a MultiSession per host
+type HostErrors map[string]error
+
+func (e HostErrors) Error() string {
+	hosts := make([]string, 0, len(e))
+	for host := range e {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	msg := fmt.Sprintf("%v of the hosts failed:", len(hosts))
+	for _, host := range hosts {
+		msg += fmt.Sprintf("\n  %v: %v", host, e[host])
+	}
+	return msg
+}
+
+// SplitNotFound splits the error of a MultiSession in to the hosts which returned ErrNotFound
+// and the errors of the other hosts, the error is nil when all the failed hosts returned ErrNotFound
+func SplitNotFound(err error) ([]string, error) {
+	var errs HostErrors
+	if !errors.As(err, &errs) {
+		return nil, err
+	}
+	notFound := []string{}
+	failed := make(HostErrors)
+	for host, e := range errs {
+		if errors.Is(e, ErrNotFound) {
+			notFound = append(notFound, host)
+			continue
+		}
+		failed[host] = e
+	}
+	sort.Strings(notFound)
+	if len(failed) == 0 {
+		return notFound, nil
+	}
+	return notFound, failed
+}
+
 type sessionClient struct {
-	Client  *client.V2
 	Host    string
 	Service *service.Client
 }
@@ -36,25 +75,46 @@ func SingleSession(svc *service.Client) (string, error) {
 }
 
 func MultiSession(svc *service.Client) (map[string]string, error) {
-	var err error
-	var body string
+	var mu sync.Mutex
+	var wg sync.WaitGroup
 	var data = make(map[string]string)
-	hosts := HostRoles(svc.Devices, svc.Role)
+	var errs = make(HostErrors)
+	hosts := svc.Hosts
+	if hosts == nil {
+		hosts = HostRoles(svc.Devices, svc.Role)
+	}
 	if len(hosts) == 0 {
-		log.Panicln("[PANIC] No hosts found, using role: ", svc.Role)
+		return nil, fmt.Errorf("no hosts found with role: %v", svc.Role)
+	}
+	parallelism := 1
+	if svc.Config.Parallelism > 0 {
+		parallelism = svc.Config.Parallelism
 	}
+	sem := make(chan struct{}, parallelism)
 	for _, host := range hosts {
-		s := &sessionClient{
-			Host:    fmt.Sprintf("%v", host),
-			Service: svc,
-		}
-		body, err = s.methods(svc.Method)
-		if err != nil {
-			log.Println("[DEBUG] ERROR MultiSession: ", err)
-			return data, err
-		}
-		data[s.Host] = body
-
+		wg.Add(1)
+		go func(host string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			s := &sessionClient{
+				Host:    host,
+				Service: svc,
+			}
+			body, err := s.methods(svc.Method)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Println("[DEBUG] ERROR MultiSession: ", err)
+				errs[host] = err
+				return
+			}
+			data[host] = body
+		}(fmt.Sprintf("%v", host))
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return data, errs
 	}
 	return data, nil
 }
@@ -76,149 +136,78 @@ func HostRoles(devices []interface{}, role string) []interface{} {
 	return hosts
 }
 
-func NewClient(host string, d schema.ResourceData) (*client.V2, diag.Diagnostics) {
-	var diags diag.Diagnostics
-	iosxeV2Client, err := client.NewV2(
-		host,
-		d.Get("username").(string),
-		d.Get("password").(string),
-		d.Get("timeout").(int),
-		d.Get("insecure").(bool),
-		d.Get("proxy_url").(string),
-		d.Get("proxy_creds").(string),
-		d.Get("ca_file").(string),
-	)
-
-	if err != nil {
-		log.Printf("[DEBUG] ERROR: %v\n", err)
-		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Error,
-			Summary:  "Unable to create Cisco IOS-XE client",
-			Detail:   fmt.Sprintf("Unable to create Cisco IOSXE client. Error - %v", err),
-		})
-		return nil, diags
-	}
-	return iosxeV2Client, diags
-
-}
-
 func (s *sessionClient) methods(method string) (string, error) {
 	var err error
 	var body string
-	var resp *http.Response
-	var container *container.Container
-	var httpRetry int
-	var httpSleep time.Duration
 
-	httpRetry = 20
-	httpSleep = 10 * time.Second
+	retry := newRetryPolicy(s.Service.Config)
+	ctx := s.Service.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
 
-	iosxeGM := &models.GenericModel{
-		JSONPayload: s.Service.Payload,
+	if method != "GET" && s.Service.Config.DryRun {
+		return s.render(method)
 	}
 
-	c, _ := NewClient(
-		fmt.Sprintf("https://%v", s.Host),
-		s.Service.Provider,
-	)
+	t, err := NewTransport(s.Host, s.Service.Config)
+	if err != nil {
+		return body, err
+	}
+	defer t.Close()
 
 	switch method {
 	case "GET":
-		log.Println("[DEBUG] IOS-XE GET on: ", s.Host)
-		for i := 0; ; i++ {
-			resp, container, err = c.Get(s.Service.Path, nil)
-			if resp != nil {
-				if resp.StatusCode == 409 {
-					log.Println(s.httpErrorMsg(resp.StatusCode))
-				} else {
-					break
-				}
-			}
-			if i >= (httpRetry - 1) {
-				break
-			}
-			log.Printf("[DEBUG] IOS-XE Retry: (%v/%v) waiting: %v\n", i, httpRetry, httpSleep)
-			time.Sleep(httpSleep)
+		log.Println("[DEBUG] IOS-XE GET on: ", s.Host)
+		err = retry.do(ctx, s.Host, func() error {
+			body, err = t.Get(ctx, s.Service.Path)
+			return err
+		})
+		if errors.Is(err, ErrNotFound) {
+			log.Printf("[DEBUG] IOS-XE GET not found on: %v %v\n", s.Host, s.Service.Path)
+			return body, err
 		}
 		if err != nil {
-			log.Println("[DEBUG] ERROR GET: ", err, resp.Status)
+			log.Println("[DEBUG] ERROR GET: ", err)
 			return body, err
 		}
-		body = container.String()
 	case "PATCH":
 		log.Println("[DEBUG] IOS-XE PATCH on: ", s.Host)
-		for i := 0; ; i++ {
-			resp, _, err = c.PatchRaw(s.Service.Path, s.Service.Payload)
-			if resp != nil {
-				if resp.StatusCode == 409 {
-					log.Println(s.httpErrorMsg(resp.StatusCode))
-				} else {
-					break
-				}
-			}
-			if i >= (httpRetry - 1) {
-				break
-			}
-			log.Printf("[DEBUG] IOS-XE Retry: (%v/%v) waiting: %v\n", i, httpRetry, httpSleep)
-			time.Sleep(httpSleep)
-		}
+		err = retry.do(ctx, s.Host, func() error {
+			return t.Merge(ctx, s.Service.Path, s.Service.Payload)
+		})
 		if err != nil {
-			log.Println("[DEBUG] ERROR PATCH: ", err, resp.Status)
+			log.Println("[DEBUG] ERROR PATCH: ", err)
 			return body, err
 		}
 	case "UPDATE":
 		log.Println("[DEBUG] IOS-XE UPDATE on: ", s.Host)
-		for i := 0; ; i++ {
-			resp, err = c.Update(s.Service.Path, iosxeGM)
-			if resp != nil {
-				if resp.StatusCode == 409 {
-					log.Println(s.httpErrorMsg(resp.StatusCode))
-				} else {
-					break
-				}
-			}
-			if i >= (httpRetry - 1) {
-				break
-			}
-			log.Printf("IOS-XE Retry: (%v/%v) waiting: %v\n", i, httpRetry, httpSleep)
-			time.Sleep(httpSleep)
+		err = retry.do(ctx, s.Host, func() error {
+			return t.Replace(ctx, s.Service.Path, s.Service.Payload)
+		})
+		if err != nil {
+			log.Println("[DEBUG] ERROR UPDATE: ", err)
+			return body, err
 		}
+	case "POST":
+		log.Printf("[DEBUG] IOS-XE POST on: %v %v\n", s.Host, s.Service.Path)
+		err = retry.do(ctx, s.Host, func() error {
+			body, err = t.Post(ctx, s.Service.Path, s.Service.Payload)
+			return err
+		})
 		if err != nil {
-			log.Println("[DEBUG] ERROR UPDATE: ", err, resp.Status)
+			log.Println("[DEBUG] ERROR POST: ", err)
 			return body, err
 		}
 	case "DELETE":
 		log.Printf("[DEBUG] IOS-XE DELETE on: %v %v\n", s.Host, s.Service.Path)
-		for i := 0; ; i++ {
-			resp, err = c.Delete(s.Service.Path)
-			// TODO
-			// catch this?
-			// panic: runtime error: invalid memory address or nil pointer dereference
-			if resp != nil {
-				if resp.StatusCode == 409 {
-					log.Println(s.httpErrorMsg(resp.StatusCode))
-				} else {
-					break
-				}
-			}
-			if i >= (httpRetry - 1) {
-				break
-			}
-			log.Printf("[DEBUG] IOS-XE Retry: (%v/%v) waiting: %v\n", i, httpRetry, httpSleep)
-			time.Sleep(httpSleep)
-		}
+		err = retry.do(ctx, s.Host, func() error {
+			return t.Delete(ctx, s.Service.Path)
+		})
 		if err != nil {
-			log.Println("[DEBUG] ERROR DELETE: ", err, resp.Status)
+			log.Println("[DEBUG] ERROR DELETE: ", err)
 			return body, err
 		}
 	}
 	return body, nil
 }
-
-func (*sessionClient) httpErrorMsg(status int) string {
-	var msg string
-	if status == 409 {
-		msg = "[DEBUG] IOS-XE configuration database is unavailable"
-	}
-	return msg
-}
@@ -0,0 +1,26 @@
+package iosxe
+
+import (
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
+)
+
+// NewTransport opens the transport of the provider configuration to the host
+func NewTransport(host string, cfg *service.Config) (service.Transport, error) {
+	mode := cfg.CassetteMode
+	if mode == "replay" {
+		return newReplayTransport(host, cfg)
+	}
+
+	var t service.Transport
+	var err error
+	switch cfg.Transport {
+	case "netconf":
+		t, err = newNetconfTransport(host, cfg)
+	default:
+		t, err = newRestconfTransport(host, cfg)
+	}
+	if err != nil || mode != "record" {
+		return t, err
+	}
+	return newRecordTransport(host, cfg, t)
+}
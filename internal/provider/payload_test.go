@@ -0,0 +1,493 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/bgp"
+)
+
+var update = flag.Bool("update", false, "update the golden payloads and CLI in testdata")
+
+// payloadTest builds a payload from the attributes of a resource
+type payloadTest struct {
+	name     string
+	resource *schema.Resource
+	raw      map[string]interface{}
+	build    func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics)
+}
+
+func noDiags(data interface{}) (interface{}, diag.Diagnostics) {
+	return data, nil
+}
+
+// testSteps is the payload of a resource configuring several objects, as recorded by applyRoleSteps
+type testSteps []payloadStep
+
+func (s testSteps) MarshalJSON() ([]byte, error) {
+	payload, _ := stepsPayload(s)
+	return []byte(payload), nil
+}
+
+func (s testSteps) CLI() []string {
+	_, lines := stepsPayload(s)
+	return lines
+}
+
+var payloadTests = []payloadTest{
+	{
+		name:     "loopback",
+		resource: resourceCiscoNativeLoopbackInterface(),
+		raw: map[string]interface{}{
+			"host":         "10.0.0.1",
+			"loopback_id":  100,
+			"ipv4_address": "100.119.11.1",
+			"ipv4_mask":    "255.255.255.255",
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return noDiags(c.resourceCiscoNativeLoopbackInterfaceData(d))
+		},
+	},
+	{
+		name:     "loopback_no_pim",
+		resource: resourceCiscoNativeLoopbackInterface(),
+		raw: map[string]interface{}{
+			"host":         "10.0.0.1",
+			"loopback_id":  200,
+			"description":  "VTEP",
+			"ipv4_address": "100.119.12.1",
+			"ipv4_mask":    "255.255.255.255",
+			"pim_sm":       false,
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return noDiags(c.resourceCiscoNativeLoopbackInterfaceData(d))
+		},
+	},
+	{
+		name:     "vrf",
+		resource: resourceCiscoNativeVrf(),
+		raw: map[string]interface{}{
+			"roles": []interface{}{"leafs"},
+			"name":  "green",
+			"rd":    "1:1",
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return noDiags(c.CiscoIOSXENativeVrfData(d))
+		},
+	},
+	{
+		name:     "vrf_ipv4",
+		resource: resourceCiscoNativeVrf(),
+		raw: map[string]interface{}{
+			"roles": []interface{}{"leafs"},
+			"name":  "blue",
+			"rd":    "2:2",
+			"ipv6":  false,
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return noDiags(c.CiscoIOSXENativeVrfData(d))
+		},
+	},
+	{
+		name:     "vlan",
+		resource: resourceCiscoNativeVlan(),
+		raw: map[string]interface{}{
+			"roles":         []interface{}{"leafs"},
+			"vlan_id":       101,
+			"evpn_instance": 101,
+			"vni":           10101,
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return noDiags(c.resourceCiscoNativeVlanData(d))
+		},
+	},
+	{
+		name:     "vlan_l3vni",
+		resource: resourceCiscoNativeVlan(),
+		raw: map[string]interface{}{
+			"roles":   []interface{}{"leafs"},
+			"vlan_id": 103,
+			"name":    "green",
+			"vni":     10103,
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return noDiags(c.resourceCiscoNativeVlanData(d))
+		},
+	},
+	{
+		name:     "evpn_instance",
+		resource: resourceCiscoNativeEvpnInstance(),
+		raw: map[string]interface{}{
+			"roles":                     []interface{}{"leafs"},
+			"instance_id":               101,
+			"vlan_based":                true,
+			"encapsulation":             "vxlan",
+			"replication_type":          "ingress",
+			"rd":                        "101:101",
+			"rt":                        "101:101",
+			"rt_type":                   "both",
+			"ip_learning":               true,
+			"default_gateway_advertise": true,
+			"re_originate":              "route-type5",
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return noDiags(c.CiscoIOSXENativeEvpnInstanceData(d))
+		},
+	},
+	{
+		name:     "evpn_instance_minimal",
+		resource: resourceCiscoNativeEvpnInstance(),
+		raw: map[string]interface{}{
+			"roles":         []interface{}{"leafs"},
+			"instance_id":   102,
+			"vlan_based":    true,
+			"encapsulation": "vxlan",
+			"ip_learning":   false,
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return noDiags(c.CiscoIOSXENativeEvpnInstanceData(d))
+		},
+	},
+	{
+		name:     "evpn",
+		resource: resourceCiscoNativeL2VpnEvpn(),
+		raw: map[string]interface{}{
+			"roles":              []interface{}{"leafs"},
+			"router_id":          "Loopback200",
+			"logging_peer_state": true,
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return c.resourceCiscoNativeL2VpnEvpnData(d, "role leafs")
+		},
+	},
+	{
+		name:     "nve",
+		resource: resourceCiscoNativeNve(),
+		raw: map[string]interface{}{
+			"roles":                    []interface{}{"leafs"},
+			"source_interface":         "Loopback200",
+			"vni":                      map[string]interface{}{"green": "10103"},
+			"vni_ipv4_multicast_group": map[string]interface{}{"225.0.0.101": "10101-10102"},
+			"vni_ingress_replication":  []interface{}{"10104"},
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return c.resourceCiscoNativeNveData(d, "role leafs")
+		},
+	},
+	{
+		name:     "svi",
+		resource: resourceCiscoNativeSvi(),
+		raw: map[string]interface{}{
+			"roles":        []interface{}{"leafs"},
+			"svi_id":       101,
+			"vrf":          "green",
+			"ipv4_address": "100.119.101.1",
+			"ipv4_mask":    "255.255.255.0",
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return noDiags(c.resourceCiscoNativeSviData(d))
+		},
+	},
+	{
+		name:     "svi_unnumbered",
+		resource: resourceCiscoNativeSvi(),
+		raw: map[string]interface{}{
+			"roles":      []interface{}{"leafs"},
+			"svi_id":     103,
+			"autostate":  true,
+			"vrf":        "green",
+			"unnumbered": "Loopback200",
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return noDiags(c.resourceCiscoNativeSviData(d))
+		},
+	},
+	{
+		name:     "subinterface",
+		resource: resourceCiscoNativeSubInterface(),
+		raw: map[string]interface{}{
+			"host":            "10.0.0.1",
+			"ethernet":        "1/1/1.253",
+			"interface_speed": 10,
+			"dot1q":           253,
+			"vrf":             "green",
+			"ipv4_address":    "100.119.253.10",
+			"ipv4_mask":       "255.255.255.252",
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			data, _ := c.resourceCiscoNativeSubInterfaceData(d)
+			return noDiags(data)
+		},
+	},
+	{
+		name:     "dhcp",
+		resource: resourceCiscoNativeDhcp(),
+		raw: map[string]interface{}{
+			"roles": []interface{}{"leafs"},
+			"vlans": []interface{}{101, 102},
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return noDiags(c.resourceCiscoNativeDhcpData(d))
+		},
+	},
+	{
+		name:     "dhcp_helper",
+		resource: resourceCiscoNativeDhcpHelper(),
+		raw: map[string]interface{}{
+			"roles":            []interface{}{"leafs"},
+			"svi_id":           103,
+			"ipv4_helper":      []interface{}{"100.127.255.2"},
+			"source_interface": "Loopback200",
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return noDiags(c.resourceCiscoNativeDhcpHelperCreateIpv4Data(d))
+		},
+	},
+	{
+		name:     "bgp_system",
+		resource: resourceCiscoNativeBgpSystem(),
+		raw: map[string]interface{}{
+			"roles":     []interface{}{"spines", "leafs"},
+			"bgp_id":    65534,
+			"router_id": "Loopback100",
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return c.resourceCiscoIOSXEBgpSystemData(d, "role spines")
+		},
+	},
+	{
+		name:     "bgp_neighbor_spine",
+		resource: resourceCiscoNativeBgpNeighbor(),
+		raw: map[string]interface{}{
+			"roles":                  []interface{}{"spines"},
+			"bgp_id":                 65534,
+			"neighbors":              []interface{}{"100.119.11.1", "100.119.11.11", "100.119.11.12"},
+			"remote_as":              65534,
+			"update_source":          "Loopback100",
+			"l2vpn_evpn":             true,
+			"route_reflector_client": true,
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return c.resourceCiscoIOSXEBgpNeighborData(d, "100.119.11.1", "spines", "host 10.0.0.1")
+		},
+	},
+	{
+		name:     "bgp_neighbor_leaf",
+		resource: resourceCiscoNativeBgpNeighbor(),
+		raw: map[string]interface{}{
+			"roles":                  []interface{}{"leafs"},
+			"bgp_id":                 65534,
+			"neighbors":              []interface{}{"100.119.11.1", "100.119.11.2"},
+			"remote_as":              65534,
+			"update_source":          "Loopback100",
+			"l2vpn_evpn":             true,
+			"route_reflector_client": true,
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return c.resourceCiscoIOSXEBgpNeighborData(d, "100.119.11.11", "leafs", "host 10.0.0.11")
+		},
+	},
+	{
+		name:     "bgp_vrf",
+		resource: resourceCiscoNativeBgpVrf(),
+		raw: map[string]interface{}{
+			"roles":  []interface{}{"leafs"},
+			"bgp_id": 65534,
+			"vrf":    "green",
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return noDiags(c.CiscoIOSXENativeVrfBgp(d))
+		},
+	},
+	{
+		name:     "bgp_neighbor_vrf_unicast",
+		resource: resourceCiscoNativeBgpNeighborVrfUnicast(),
+		raw: map[string]interface{}{
+			"host":           "10.0.0.11",
+			"bgp_id":         65534,
+			"remote_as":      65000,
+			"vrf":            "green",
+			"ipv4_neighbors": []interface{}{"100.119.253.9"},
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return noDiags(c.resourceCiscoNativeBgpNeighborVrfUnicastIpv4Data(d))
+		},
+	},
+	{
+		name:     "ospf",
+		resource: resourceCiscoNativeOspf(),
+		raw: map[string]interface{}{
+			"roles":              []interface{}{"leafs"},
+			"process_id":         1,
+			"router_id":          "Loopback0",
+			"loopbacks":          []interface{}{"Loopback0", "Loopback1"},
+			"interfaces":         []interface{}{"TenGigabitEthernet1/1/1", "TenGigabitEthernet1/1/2"},
+			"passive_interfaces": []interface{}{"Loopback0"},
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return noDiags(testSteps(c.resourceCiscoNativeOspfSteps(d, "100.119.0.11")))
+		},
+	},
+	{
+		name:     "isis",
+		resource: resourceCiscoNativeIsis(),
+		raw: map[string]interface{}{
+			"roles":             []interface{}{"leafs"},
+			"area_tag":          "1",
+			"loopback":          "Loopback0",
+			"interfaces":        []interface{}{"TenGigabitEthernet1/1/1", "TenGigabitEthernet1/1/2"},
+			"bfd":               true,
+			"passive_loopbacks": []interface{}{"Loopback0", "1"},
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return noDiags(testSteps(c.resourceCiscoNativeIsisSteps(d, "49.0001.1001.1900.0011.00")))
+		},
+	},
+	{
+		name:     "multicast_underlay",
+		resource: resourceCiscoNativeMulticastUnderlay(),
+		raw: map[string]interface{}{
+			"roles":               []interface{}{"spines", "leafs"},
+			"rp_roles":            []interface{}{"spines"},
+			"rp_address":          "100.119.254.1",
+			"anycast_rp_loopback": "Loopback254",
+			"msdp_loopback":       "Loopback0",
+			"interfaces":          []interface{}{"TenGigabitEthernet1/1/1"},
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return noDiags(testSteps(c.resourceCiscoNativeMulticastUnderlaySteps(d, "10.0.0.1", []string{"100.119.0.2"})))
+		},
+	},
+	{
+		name:     "fabric_link",
+		resource: resourceCiscoNativeFabricLink(),
+		raw: map[string]interface{}{
+			"pim_sm": true,
+			"endpoint": []interface{}{
+				map[string]interface{}{"host": "10.0.0.1", "ethernet": "1/0/1", "interface_speed": 100, "ipv4_address": "100.119.1.0"},
+				map[string]interface{}{"host": "10.0.0.11", "ethernet": "1/0/49", "interface_speed": 25, "ipv4_address": "100.119.1.1"},
+			},
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return noDiags(c.resourceCiscoNativeFabricLinkData(d, 0))
+		},
+	},
+	{
+		name:     "access_interface",
+		resource: resourceCiscoNativeAccessInterface(),
+		raw: map[string]interface{}{
+			"host":                "10.0.0.11",
+			"interface":           "GigabitEthernet1/0/10",
+			"mode":                "trunk",
+			"trunk_allowed_vlans": []interface{}{101, 102},
+			"native_vlan":         101,
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return noDiags(c.resourceCiscoNativeAccessInterfaceData(d))
+		},
+	},
+	{
+		name:     "ethernet_segment",
+		resource: resourceCiscoNativeEthernetSegment(),
+		raw: map[string]interface{}{
+			"identifier_type": 3,
+			"system_mac":      "0000.0000.0001",
+			"member": []interface{}{
+				map[string]interface{}{"host": "10.0.0.11", "port_channel": 10, "segment": 1},
+				map[string]interface{}{"host": "10.0.0.12", "port_channel": 10, "segment": 1},
+			},
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return noDiags(testSteps(c.resourceCiscoNativeEthernetSegmentData(d, 0)))
+		},
+	},
+	{
+		name:     "port_channel",
+		resource: resourceCiscoNativePortChannel(),
+		raw: map[string]interface{}{
+			"host":         "10.0.0.21",
+			"port_channel": 10,
+			"members":      []interface{}{"TenGigabitEthernet1/0/1", "TenGigabitEthernet1/0/2"},
+			"mode":         "l3",
+			"min_links":    2,
+			"ipv4_address": "100.119.10.1",
+			"ipv4_mask":    "255.255.255.254",
+		},
+		build: func(c *providerClient, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return noDiags(testSteps(c.resourceCiscoNativePortChannelData(d)))
+		},
+	},
+}
+
+// TestPayloads compares the payloads sent to the devices with the golden files, run with -update to regenerate them
+func TestPayloads(t *testing.T) {
+	c := &providerClient{}
+	for _, tt := range payloadTests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, tt.resource.Schema, tt.raw)
+			data, diags := tt.build(c, d)
+			if diags.HasError() {
+				t.Fatalf("build payload: %v", diags[0].Detail)
+			}
+			got, err := json.MarshalIndent(data, "", "\t")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+			testGolden(t, filepath.Join("testdata", "payloads", tt.name+".json"), got)
+		})
+	}
+}
+
+// TestCLI compares the IOS-XE CLI rendered from the payloads with the golden files, run with -update to regenerate them
+func TestCLI(t *testing.T) {
+	c := &providerClient{}
+	for _, tt := range payloadTests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, tt.resource.Schema, tt.raw)
+			data, diags := tt.build(c, d)
+			if diags.HasError() {
+				t.Fatalf("build payload: %v", diags[0].Detail)
+			}
+			var lines []string
+			switch v := data.(type) {
+			case *bgp.CiscoIOSXEBgpWithVrfs:
+				lines = v.CLI(d.Get("bgp_id").(int))
+			case *bgp.CiscoIOSXEBgpVrfIpv4Unicast:
+				lines = v.CLI(d.Get("bgp_id").(int), d.Get("vrf").(string))
+			case interface{ CLI() []string }:
+				lines = v.CLI()
+			default:
+				t.Fatalf("no CLI of %T", data)
+			}
+			testGolden(t, filepath.Join("testdata", "cli", tt.name+".cli"), []byte(strings.Join(lines, "\n")+"\n"))
+		})
+	}
+}
+
+// testGolden compares got with the golden file, it's written first with -update
+func testGolden(t *testing.T, golden string, got []byte) {
+	t.Helper()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("%v, run go test -run 'TestPayloads|TestCLI' -update to create it", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%v differs, run go test -run 'TestPayloads|TestCLI' -update if the change is intended\ngot:\n%s\nwant:\n%s", golden, got, want)
+	}
+}
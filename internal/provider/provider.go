@@ -7,11 +7,16 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
 )
 
 type providerClient struct {
-	Provider schema.ResourceData
-	Devices  *schema.Set
+	Config  *service.Config
+	Devices *schema.Set
+	// transactions serializes the transactional applies per host
+	transactions hostLocks
+	// vlans holds the VLANs planned by the ciscoevpn_vlan resources
+	vlans plannedVlans
 }
 
 func init() {
@@ -43,7 +48,7 @@ func Provider() *schema.Provider {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     true,
-				Description: "Allow insecure TLS. Default: true, means the API call is insecure.",
+				Description: "Allow insecure TLS and skip the SSH host key verification of NETCONF. Default: true, means the API call is insecure.",
 			},
 			"timeout": {
 				Type:        schema.TypeInt,
@@ -69,10 +74,122 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("EVPN_PROXY_CREDS", nil),
 				Description: "Proxy credential in format `username:password`. This can also be set by environment variable `EVPN_PROXY_CREDS`.",
 			},
+			"retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      20,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Number of retries of a failed HTTP request. Default value: 20.",
+			},
+			"retry_min_backoff": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Seconds to wait before the first retry, doubled on every retry (with jitter). Default value: 1.",
+			},
+			"retry_max_backoff": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      30,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum seconds to wait between retries. Default value: 30.",
+			},
+			"retry_max_elapsed": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      300,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum seconds spent retrying a HTTP request. Default value: 300.",
+			},
+			"retry_status_codes": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "HTTP status codes which are retried. Default value: `[409, 503]`.",
+			},
+			"retry_errors": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Transport errors which are retried, matched as substring of the error. Default value: `[\"connection reset\", \"TLS handshake timeout\"]`.",
+			},
+			"parallelism": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      10,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Number of devices configured in parallel. Default value: 10.",
+			},
+			"transactional": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Take a checkpoint of the running config on the devices before a create, update or delete of a role resource, and roll back all devices if it fails. A rollback restores the whole running config, so the role resources on the same device are applied one at a time. Resources without roles, e.g. `ciscoevpn_loopback`, aren't serialized with them.",
+			},
+			"transport": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "restconf",
+				ValidateFunc: validation.StringInSlice([]string{"restconf", "netconf"}, false),
+				Description:  "Protocol used to configure the devices: `restconf` or `netconf` (over SSH). Default value: `restconf`.",
+			},
+			"netconf_port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      830,
+				ValidateFunc: validation.IsPortNumber,
+				Description:  "SSH port of NETCONF, a port in the hosts of the roles is ignored. Default value: 830.",
+			},
+			"netconf_datastore": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "running",
+				ValidateFunc: validation.StringInSlice([]string{"running", "candidate"}, false),
+				Description:  "NETCONF datastore the config is edited in: `running` or `candidate`. The candidate is locked during the edit and committed. Default value: `running`.",
+			},
+			"netconf_confirm_timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Seconds of the confirmed commit of the candidate datastore, the device rolls back when the commit isn't confirmed in time (e.g. the change cut off the session). Default value: 0, means a plain commit.",
+			},
+			"known_hosts_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("EVPN_KNOWN_HOSTS_FILE", nil),
+				Description: "Known hosts file verifying the SSH host keys of NETCONF when `insecure` is false. Default value: `~/.ssh/known_hosts`. This can also be set by environment variable `EVPN_KNOWN_HOSTS_FILE`.",
+			},
+			"cassette_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("EVPN_CASSETTE_MODE", nil),
+				ValidateFunc: validation.StringInSlice([]string{"record", "replay"}, false),
+				Description:  "`record` every request and response per host in to `cassette_dir`, with the password, the proxy credentials and the Authorization header redacted. `replay` answers the requests from the recorded cassettes without connecting to the devices. Remove the cassettes before a new recording, requests are appended. This can also be set by environment variable `EVPN_CASSETTE_MODE`.",
+			},
+			"cassette_dir": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("EVPN_CASSETTE_DIR", "cassettes"),
+				Description: "Folder of the cassettes, one `<host>.json` per device. Default value: `cassettes`. This can also be set by environment variable `EVPN_CASSETTE_DIR`.",
+			},
+			"dry_run": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Render the payloads of create, update and delete without sending them to the devices. The payloads are set in `rendered_payloads` of the resources and the operations are written to `plan_report`. A delete fails with `dry_run: delete not applied` after it's rendered, the resource is kept in state. Reads still query the devices. Default value: false.",
+			},
+			"plan_report": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("EVPN_PLAN_REPORT", "plan_report.json"),
+				Description: "File of the operations of `dry_run`, with host, role, method, path and payload. Operations are appended, remove the file before a new dry run. Default value: `plan_report.json`. This can also be set by environment variable `EVPN_PLAN_REPORT`.",
+			},
 			"debug": {
 				Type:        schema.TypeBool,
 				Optional:    true,
-				Description: "Debug JSON Payloads in to debug folder",
+				Description: "Debug JSON Payloads and their IOS-XE CLI in to debug folder",
 			},
 			"roles": {
 				Type:     schema.TypeSet,
@@ -141,15 +258,56 @@ func Provider() *schema.Provider {
 			"ciscoevpn_nve":                      resourceCiscoNativeNve(),
 			"ciscoevpn_svi":                      resourceCiscoNativeSvi(),
 			"ciscoevpn_subinterface":             resourceCiscoNativeSubInterface(),
-			"ciscoevpn_dhcp":             resourceCiscoNativeDhcp(),
-			"ciscoevpn_dhcp_helper":             resourceCiscoNativeDhcpHelper(),
+			"ciscoevpn_dhcp":                     resourceCiscoNativeDhcp(),
+			"ciscoevpn_dhcp_helper":              resourceCiscoNativeDhcpHelper(),
+			"ciscoevpn_l2vni":                    resourceCiscoNativeL2vni(),
+			"ciscoevpn_l3vni":                    resourceCiscoNativeL3vni(),
+			"ciscoevpn_ospf":                     resourceCiscoNativeOspf(),
+			"ciscoevpn_isis":                     resourceCiscoNativeIsis(),
+			"ciscoevpn_multicast_underlay":       resourceCiscoNativeMulticastUnderlay(),
+			"ciscoevpn_fabric_link":              resourceCiscoNativeFabricLink(),
+			"ciscoevpn_access_interface":         resourceCiscoNativeAccessInterface(),
+			"ciscoevpn_ethernet_segment":         resourceCiscoNativeEthernetSegment(),
+			"ciscoevpn_port_channel":             resourceCiscoNativePortChannel(),
 		},
-		DataSourcesMap: map[string]*schema.Resource{},
+		DataSourcesMap: map[string]*schema.Resource{
+			"ciscoevpn_loopback":             dataSourceCiscoNativeLoopback(),
+			"ciscoevpn_vrfs":                 dataSourceCiscoNativeVrfs(),
+			"ciscoevpn_vlans":                dataSourceCiscoNativeVlans(),
+			"ciscoevpn_nve":                  dataSourceCiscoNativeNve(),
+			"ciscoevpn_bgp_neighbors":        dataSourceCiscoNativeBgpNeighbors(),
+			"ciscoevpn_nve_status":           dataSourceCiscoNveStatus(),
+			"ciscoevpn_bgp_neighbor_status":  dataSourceCiscoBgpNeighborStatus(),
+			"ciscoevpn_evpn_instance_status": dataSourceCiscoEvpnInstanceStatus(),
+			"ciscoevpn_fabric_validation":    dataSourceCiscoFabricValidation(),
+		},
+	}
+	for _, r := range p.ResourcesMap {
+		r.DeleteContext = dryRunDelete(r.DeleteContext)
 	}
 	p.ConfigureContextFunc = providerConfigure(p)
 	return p
 }
 
+// dryRunDelete keeps the resource in state when the delete was only rendered by dry_run,
+// the error stops terraform from dropping the state of the resource still configured on the devices
+func dryRunDelete(del schema.DeleteContextFunc) schema.DeleteContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		c, _ := meta.(*providerClient)
+		id := d.Id()
+		diags := del(ctx, d, meta)
+		if diags.HasError() || !c.Config.DryRun {
+			return diags
+		}
+		d.SetId(id)
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "dry_run: delete not applied",
+			Detail:   fmt.Sprintf("The delete of %v was only rendered, the resource is kept in state", id),
+		})
+	}
+}
+
 func providerConfigure(p *schema.Provider) func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	return func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		var diags diag.Diagnostics
@@ -157,12 +315,48 @@ func providerConfigure(p *schema.Provider) func(ctx context.Context, d *schema.R
 			return nil, diags
 		}
 		return &providerClient{
-			Provider: *d,
-			Devices:  d.Get("roles").(*schema.Set),
+			Config:  providerConfig(d),
+			Devices: d.Get("roles").(*schema.Set),
 		}, diags
 	}
 }
 
+// providerConfig resolves the settings of the provider once, the ResourceData isn't safe for the concurrent sessions
+func providerConfig(d *schema.ResourceData) *service.Config {
+	cfg := &service.Config{
+		Username:              d.Get("username").(string),
+		Password:              d.Get("password").(string),
+		Insecure:              d.Get("insecure").(bool),
+		Timeout:               d.Get("timeout").(int),
+		CAFile:                d.Get("ca_file").(string),
+		ProxyURL:              d.Get("proxy_url").(string),
+		ProxyCreds:            d.Get("proxy_creds").(string),
+		Retries:               d.Get("retries").(int),
+		RetryMinBackoff:       d.Get("retry_min_backoff").(int),
+		RetryMaxBackoff:       d.Get("retry_max_backoff").(int),
+		RetryMaxElapsed:       d.Get("retry_max_elapsed").(int),
+		Parallelism:           d.Get("parallelism").(int),
+		Transactional:         d.Get("transactional").(bool),
+		Transport:             d.Get("transport").(string),
+		NetconfPort:           d.Get("netconf_port").(int),
+		NetconfDatastore:      d.Get("netconf_datastore").(string),
+		NetconfConfirmTimeout: d.Get("netconf_confirm_timeout").(int),
+		KnownHostsFile:        d.Get("known_hosts_file").(string),
+		CassetteMode:          d.Get("cassette_mode").(string),
+		CassetteDir:           d.Get("cassette_dir").(string),
+		DryRun:                d.Get("dry_run").(bool),
+		PlanReport:            d.Get("plan_report").(string),
+		Debug:                 d.Get("debug").(bool),
+	}
+	for _, v := range d.Get("retry_status_codes").([]interface{}) {
+		cfg.RetryStatusCodes = append(cfg.RetryStatusCodes, v.(int))
+	}
+	for _, v := range d.Get("retry_errors").([]interface{}) {
+		cfg.RetryErrors = append(cfg.RetryErrors, v.(string))
+	}
+	return cfg
+}
+
 func providerValidateInput(d *schema.ResourceData) diag.Diagnostics {
 	var diags diag.Diagnostics
 	if v, ok := d.GetOk("username"); !ok && v == "" {
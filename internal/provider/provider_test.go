@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/simulator"
+)
+
+const (
+	testAccUsername = "admin"
+	testAccPassword = "simulator"
+)
+
+var testAccProviderFactories = map[string]func() (*schema.Provider, error){
+	"ciscoevpn": func() (*schema.Provider, error) {
+		return Provider(), nil
+	},
+}
+
+func TestProvider(t *testing.T) {
+	if err := Provider().InternalValidate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+// testAccFabric is a fabric of simulated devices
+type testAccFabric struct {
+	Spines []*simulator.Device
+	Leafs  []*simulator.Device
+	// Settings are added to the provider block
+	Settings string
+}
+
+func newTestAccFabric(t *testing.T, spines, leafs int) *testAccFabric {
+	f := &testAccFabric{}
+	for i := 0; i < spines; i++ {
+		f.Spines = append(f.Spines, simulator.NewDevice(testAccUsername, testAccPassword))
+	}
+	for i := 0; i < leafs; i++ {
+		f.Leafs = append(f.Leafs, simulator.NewDevice(testAccUsername, testAccPassword))
+	}
+	t.Cleanup(func() {
+		for _, d := range f.Devices() {
+			d.Close()
+		}
+	})
+	return f
+}
+
+func (f *testAccFabric) Devices() []*simulator.Device {
+	return append(append([]*simulator.Device{}, f.Spines...), f.Leafs...)
+}
+
+func testAccHosts(devices []*simulator.Device) string {
+	hosts := []string{}
+	for _, d := range devices {
+		hosts = append(hosts, fmt.Sprintf("%q", d.Host()))
+	}
+	return strings.Join(hosts, ", ")
+}
+
+// Config returns the provider block of the fabric
+func (f *testAccFabric) Config() string {
+	return fmt.Sprintf(`
+provider "ciscoevpn" {
+  username          = %q
+  password          = %q
+  insecure          = true
+  timeout           = 5
+  retry_min_backoff = 1
+  retry_max_backoff = 1
+%v
+  roles {
+    spines {
+      iosxe = [%v]
+    }
+    leafs {
+      iosxe = [%v]
+    }
+  }
+}
+`, testAccUsername, testAccPassword, f.Settings, testAccHosts(f.Spines), testAccHosts(f.Leafs))
+}
+
+// testAccCheckDevices checks that the path contains all the values on the devices
+func testAccCheckDevices(devices []*simulator.Device, path string, values ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, d := range devices {
+			body, ok := d.Get(path)
+			if !ok {
+				return fmt.Errorf("%v not found on %v", path, d.Host())
+			}
+			for _, v := range values {
+				if !strings.Contains(body, v) {
+					return fmt.Errorf("%v on %v doesn't contain %v: %v", path, d.Host(), v, body)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// testAccCheckDevicesRemoved checks that the path is removed from the devices
+func testAccCheckDevicesRemoved(devices []*simulator.Device, path string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, d := range devices {
+			if body, ok := d.Get(path); ok {
+				return fmt.Errorf("%v still on %v: %v", path, d.Host(), body)
+			}
+		}
+		return nil
+	}
+}
+
+// testAccDataSourceDevice returns the key of the device in the devices of a data source, which are sorted by host, e.g. devices.1
+func testAccDataSourceDevice(devices []*simulator.Device, d *simulator.Device) string {
+	hosts := []string{}
+	for _, v := range devices {
+		hosts = append(hosts, v.Host())
+	}
+	sort.Strings(hosts)
+	return fmt.Sprintf("devices.%v", sort.SearchStrings(hosts, d.Host()))
+}
+
+// LoopbacksConfig returns a loopback on every device of the fabric, e.g. ciscoevpn_loopback.leaf0_100
+func (f *testAccFabric) LoopbacksConfig(id int) string {
+	config := ""
+	for role, devices := range map[string][]*simulator.Device{"spine": f.Spines, "leaf": f.Leafs} {
+		for i, d := range devices {
+			n := i + 1
+			if role == "leaf" {
+				n += 10
+			}
+			config += fmt.Sprintf(`
+resource "ciscoevpn_loopback" "%v%v_%v" {
+  host         = %q
+  loopback_id  = %v
+  ipv4_address = "100.119.%v.%v"
+  ipv4_mask    = "255.255.255.255"
+}
+`, role, i, id, d.Host(), id, id%256, n)
+		}
+	}
+	return config
+}
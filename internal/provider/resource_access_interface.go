@@ -0,0 +1,388 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/access"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/vlan"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/iosxe"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
+)
+
+func resourceCiscoNativeAccessInterface() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Cisco access or trunk port, the VLANs must exist on the host or be planned by the ciscoevpn_vlan resources the port references (or depends_on), the plan fails for a missing VLAN",
+		CreateContext: resourceCiscoNativeAccessInterfaceCreate,
+		ReadContext:   resourceCiscoNativeAccessInterfaceRead,
+		UpdateContext: resourceCiscoNativeAccessInterfaceUpdate,
+		DeleteContext: resourceCiscoNativeAccessInterfaceDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativeAccessInterfaceImport,
+		},
+		CustomizeDiff: resourceCiscoNativeAccessInterfaceCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"host": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"interface": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateInterface(),
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Default:  "Managed by Terraform (ciscoevpn)",
+				Optional: true,
+			},
+			"mode": {
+				Type:         schema.TypeString,
+				Default:      "access",
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"access", "trunk"}, false),
+			},
+			"access_vlan": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 4094),
+			},
+			"trunk_allowed_vlans": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeInt,
+					ValidateFunc: validation.IntBetween(1, 4094),
+				},
+			},
+			"native_vlan": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 4094),
+			},
+			"portfast": {
+				Type:     schema.TypeBool,
+				Default:  true,
+				Optional: true,
+			},
+			"bpdu_guard": {
+				Type:     schema.TypeBool,
+				Default:  true,
+				Optional: true,
+			},
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
+		},
+	}
+}
+
+// resourceCiscoNativeAccessInterfaceCustomizeDiff requires the VLAN attributes of the mode
+func resourceCiscoNativeAccessInterfaceCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("mode") || !d.NewValueKnown("access_vlan") || !d.NewValueKnown("trunk_allowed_vlans") || !d.NewValueKnown("native_vlan") {
+		return nil
+	}
+	trunk := len(d.Get("trunk_allowed_vlans").([]interface{})) > 0 || d.Get("native_vlan").(int) != 0
+	switch d.Get("mode").(string) {
+	case "trunk":
+		if d.Get("access_vlan").(int) != 0 {
+			return fmt.Errorf("access_vlan is only supported with mode access")
+		}
+		if len(d.Get("trunk_allowed_vlans").([]interface{})) == 0 {
+			return fmt.Errorf("trunk_allowed_vlans is required with mode trunk")
+		}
+	default:
+		if trunk {
+			return fmt.Errorf("trunk_allowed_vlans and native_vlan are only supported with mode trunk")
+		}
+		if d.Get("access_vlan").(int) == 0 {
+			return fmt.Errorf("access_vlan is required with mode access")
+		}
+	}
+	if d.Id() != "" && !d.HasChanges("access_vlan", "trunk_allowed_vlans", "native_vlan") {
+		return nil
+	}
+	c, _ := meta.(*providerClient)
+	return c.resourceCiscoNativeAccessInterfaceVlans(ctx, d)
+}
+
+// resourceCiscoNativeAccessInterfaceVlans checks that the VLANs of the port exist on the host,
+// or are planned by a ciscoevpn_vlan resource the port references
+func (c *providerClient) resourceCiscoNativeAccessInterfaceVlans(ctx context.Context, d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("host") {
+		return nil
+	}
+	host := d.Get("host").(string)
+	body, err := c.readHost(ctx, d, "/data/Cisco-IOS-XE-native:native/vlan")
+	data := &vlan.CiscoIOSXENativeVlans{}
+	if err == nil {
+		err = unmarshalBody(body, data)
+	}
+	if err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+		return err
+	}
+	found := make(map[int]bool)
+	for _, v := range data.CiscoIOSXENativeVlan.CiscoIOSXEVlanVlanList {
+		found[v.ID] = true
+	}
+
+	ids := d.Get("trunk_allowed_vlans").([]interface{})
+	ids = append(ids, d.Get("access_vlan"), d.Get("native_vlan"))
+	for _, id := range ids {
+		if id.(int) != 0 && !found[id.(int)] && !c.vlans.has(host, id.(int)) {
+			return fmt.Errorf("VLAN %v doesn't exist on %v, create it with ciscoevpn_vlan and reference it from the port", id, host)
+		}
+	}
+	return nil
+}
+
+func resourceCiscoNativeAccessInterfaceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco Access Interface CREATE")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	if err := c.resourceCiscoNativeAccessInterfaceApply(ctx, d); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%v_%v", d.Get("host").(string), d.Get("interface").(string)))
+	return diags
+}
+
+func resourceCiscoNativeAccessInterfaceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco Access Interface READ")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	body, err := c.readHost(ctx, d, interfacePath(d.Get("interface").(string)))
+	data := make(map[string][]access.CiscoIOSXENativeAccessInterface)
+	if err == nil {
+		err = unmarshalBody(body, &data)
+	}
+	if errors.Is(err, iosxe.ErrNotFound) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var state map[string]interface{}
+	for _, interfaces := range data {
+		if len(interfaces) > 0 {
+			state = c.resourceCiscoNativeAccessInterfaceState(d, &interfaces[0])
+		}
+	}
+	if state == nil {
+		log.Printf("[DEBUG] Access interface %v not found on: %v\n", d.Get("interface").(string), d.Get("host").(string))
+		d.SetId("")
+		return diags
+	}
+	if err = setDriftState(d, map[string]map[string]interface{}{d.Get("host").(string): state}); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = setHostCli(d, c.resourceCiscoNativeAccessInterfaceData(d).CLI()); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativeAccessInterfaceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco Access Interface UPDATE")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+
+	// PATCH merges, the switchport config of another mode and removed leaves are deleted before the new config is applied
+	paths := []string{}
+	switchport := interfacePath(d.Get("interface").(string)) + "/switchport"
+	switch {
+	case d.HasChange("mode"):
+		paths = append(paths, switchport)
+	case d.HasChanges("trunk_allowed_vlans", "native_vlan"):
+		paths = append(paths, switchport+"/Cisco-IOS-XE-switch:trunk")
+	}
+	if d.HasChanges("portfast", "bpdu_guard", "mode") {
+		paths = append(paths, interfacePath(d.Get("interface").(string))+"/spanning-tree")
+	}
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "DELETE",
+		Config:  c.Config,
+		Device:  d.Get("host").(string),
+	}
+	for _, path := range paths {
+		svc.Path = path
+		if _, err := iosxe.SingleSession(svc); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+	}
+
+	if err := c.resourceCiscoNativeAccessInterfaceApply(ctx, d); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%v_%v", d.Get("host").(string), d.Get("interface").(string)))
+	return diags
+}
+
+func resourceCiscoNativeAccessInterfaceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco Access Interface DELETE")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "DELETE",
+		Config:  c.Config,
+		Device:  d.Get("host").(string),
+	}
+	// the physical interface stays, only the config of the resource is removed
+	for _, leaf := range []string{"/spanning-tree", "/switchport", "/switchport-conf", "/description"} {
+		svc.Path = interfacePath(d.Get("interface").(string)) + leaf
+		if _, err := iosxe.SingleSession(svc); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId("")
+	return diags
+}
+
+func resourceCiscoNativeAccessInterfaceImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco Access Interface IMPORT")
+	parts, err := importHostID(d.Id(), "<host>:<interface>")
+	if err != nil {
+		return nil, err
+	}
+	if _, errs := validateInterface()(parts[1], "interface"); len(errs) > 0 {
+		return nil, errs[0]
+	}
+	d.Set("host", parts[0])
+	d.Set("interface", parts[1])
+	d.SetId(fmt.Sprintf("%v_%v", parts[0], parts[1]))
+	return importRead(ctx, d, meta, resourceCiscoNativeAccessInterfaceRead)
+}
+
+// resourceCiscoNativeAccessInterfaceApply PATCHes the port config to the host
+func (c *providerClient) resourceCiscoNativeAccessInterfaceApply(ctx context.Context, d *schema.ResourceData) error {
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    "/data/Cisco-IOS-XE-native:native/interface",
+		Config:  c.Config,
+		Device:  d.Get("host").(string),
+	}
+	data := c.resourceCiscoNativeAccessInterfaceData(d)
+	if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
+		svc.Payload = string(b)
+	}
+	svc.CLI = data.CLI()
+
+	if svc.Config.Debug {
+		debugPayload(fmt.Sprintf("access_interface_%v", strings.ReplaceAll(d.Get("interface").(string), "/", "_")), svc)
+	}
+	if _, err := iosxe.SingleSession(svc); err != nil {
+		return err
+	}
+	return c.setHostPreview(d, svc)
+}
+
+func (*providerClient) resourceCiscoNativeAccessInterfaceData(d *schema.ResourceData) *access.CiscoIOSXENativeAccessInterfaces {
+	list, id := interfaceName(d.Get("interface").(string))
+	port := access.CiscoIOSXENativeAccessInterface{
+		Name:           id,
+		Description:    d.Get("description").(string),
+		SwitchportConf: access.CiscoIOSXENativeSwitchportConf{Switchport: true},
+	}
+	trunk := d.Get("mode").(string) == "trunk"
+	if trunk {
+		port.Switchport.Mode.Trunk = &struct{}{}
+		port.Switchport.Trunk = &access.CiscoIOSXESwitchTrunk{}
+		if vlans := d.Get("trunk_allowed_vlans").([]interface{}); len(vlans) > 0 {
+			port.Switchport.Trunk.Allowed = &access.CiscoIOSXESwitchTrunkAllowed{
+				Vlan: access.CiscoIOSXESwitchTrunkAllowedVlan{Vlans: vlanList(vlans)},
+			}
+		}
+		if v, ok := d.GetOk("native_vlan"); ok {
+			port.Switchport.Trunk.Native = &access.CiscoIOSXESwitchTrunkNative{
+				Vlan: access.CiscoIOSXESwitchTrunkNativeVlan{VlanID: v.(int)},
+			}
+		}
+	} else {
+		port.Switchport.Mode.Access = &struct{}{}
+		port.Switchport.Access = &access.CiscoIOSXESwitchAccess{
+			Vlan: access.CiscoIOSXESwitchAccessVlan{Vlan: d.Get("access_vlan").(int)},
+		}
+	}
+
+	if d.Get("portfast").(bool) || d.Get("bpdu_guard").(bool) {
+		port.SpanningTree = &access.CiscoIOSXENativeSpanningTree{}
+	}
+	if d.Get("portfast").(bool) {
+		port.SpanningTree.Portfast = &access.CiscoIOSXESpanningTreePortfast{}
+		if trunk {
+			port.SpanningTree.Portfast.Trunk = null()
+		}
+	}
+	if d.Get("bpdu_guard").(bool) {
+		port.SpanningTree.Bpduguard = &access.CiscoIOSXESpanningTreeBpduguard{Enable: null()}
+	}
+
+	return &access.CiscoIOSXENativeAccessInterfaces{
+		Interface: map[string][]access.CiscoIOSXENativeAccessInterface{list: {port}},
+	}
+}
+
+// vlanList returns the VLANs as IOS-XE VLAN list, e.g. 10,20
+func vlanList(vlans []interface{}) string {
+	ids := []string{}
+	for _, v := range vlans {
+		ids = append(ids, fmt.Sprint(v))
+	}
+	return strings.Join(ids, ",")
+}
+
+func (*providerClient) resourceCiscoNativeAccessInterfaceState(d *schema.ResourceData, port *access.CiscoIOSXENativeAccessInterface) map[string]interface{} {
+	if !port.SwitchportConf.Switchport && port.Switchport.Mode.Access == nil && port.Switchport.Mode.Trunk == nil {
+		return nil
+	}
+	state := map[string]interface{}{
+		"description":         port.Description,
+		"mode":                "",
+		"access_vlan":         0,
+		"trunk_allowed_vlans": []interface{}{},
+		"native_vlan":         0,
+		"portfast":            false,
+		"bpdu_guard":          false,
+	}
+	sp := port.Switchport
+	switch {
+	case sp.Mode.Access != nil:
+		state["mode"] = "access"
+	case sp.Mode.Trunk != nil:
+		state["mode"] = "trunk"
+	}
+	if sp.Access != nil {
+		state["access_vlan"] = sp.Access.Vlan.Vlan
+	}
+	if sp.Trunk != nil && sp.Trunk.Allowed != nil {
+		state["trunk_allowed_vlans"] = orderLike(d.Get("trunk_allowed_vlans").([]interface{}), vlanRange(sp.Trunk.Allowed.Vlan.Vlans))
+	}
+	if sp.Trunk != nil && sp.Trunk.Native != nil {
+		state["native_vlan"] = sp.Trunk.Native.Vlan.VlanID
+	}
+	if st := port.SpanningTree; st != nil {
+		state["portfast"] = st.Portfast != nil
+		state["bpdu_guard"] = st.Bpduguard != nil
+	}
+	return state
+}
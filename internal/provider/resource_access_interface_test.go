@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnAccessInterface(t *testing.T) {
+	f := newTestAccFabric(t, 1, 1)
+	path := "Cisco-IOS-XE-native:native/interface/GigabitEthernet=1%2F0%2F10"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDevicesRemoved(f.Leafs, path+"/switchport"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnAccessInterfaceConfig(f, `access_vlan = ciscoevpn_vlan.vlan101.vlan_id`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDevices(f.Leafs, path, `"Cisco-IOS-XE-switch:access":{"vlan":{"vlan":101}}`, `"Cisco-IOS-XE-spanning-tree:portfast":{}`, `"Cisco-IOS-XE-spanning-tree:bpduguard"`),
+					resource.TestMatchResourceAttr("ciscoevpn_access_interface.test", "cli_preview."+f.Leafs[0].Host(), regexp.MustCompile(`switchport mode access`)),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnAccessInterfaceConfig(f, `
+  mode                = "trunk"
+  trunk_allowed_vlans = [ciscoevpn_vlan.vlan101.vlan_id, ciscoevpn_vlan.vlan102.vlan_id]
+  native_vlan         = ciscoevpn_vlan.vlan102.vlan_id
+  bpdu_guard          = false`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDevicesRemoved(f.Leafs, path+"/switchport/Cisco-IOS-XE-switch:access"),
+					testAccCheckDevicesRemoved(f.Leafs, path+"/spanning-tree/Cisco-IOS-XE-spanning-tree:bpduguard"),
+					testAccCheckDevices(f.Leafs, path, `"vlans":"101,102"`, `"vlan-id":102`, `"Cisco-IOS-XE-spanning-tree:portfast":{"trunk"`),
+				),
+			},
+			{
+				ResourceName:      "ciscoevpn_access_interface.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%v:GigabitEthernet1/0/10", f.Leafs[0].Host()),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccCiscoEvpnAccessInterfaceMissingVlan(t *testing.T) {
+	f := newTestAccFabric(t, 1, 1)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCiscoEvpnAccessInterfaceConfig(f, `access_vlan = 103`),
+				ExpectError: regexp.MustCompile(`VLAN 103 doesn't exist on ` + regexp.QuoteMeta(f.Leafs[0].Host())),
+			},
+			{
+				Config: testAccCiscoEvpnAccessInterfaceConfig(f, `
+  mode                = "trunk"
+  trunk_allowed_vlans = [ciscoevpn_vlan.vlan101.vlan_id, 104]`),
+				ExpectError: regexp.MustCompile(`VLAN 104 doesn't exist`),
+			},
+		},
+	})
+	// the plan fails, nothing is applied
+	for _, d := range f.Leafs {
+		for _, req := range d.Requests() {
+			if !strings.HasPrefix(req, "GET ") {
+				t.Errorf("%v was sent to %v with a missing VLAN", req, d.Host())
+			}
+		}
+	}
+}
+
+func testAccCiscoEvpnAccessInterfaceConfig(f *testAccFabric, port string) string {
+	return f.Config() + fmt.Sprintf(`
+resource "ciscoevpn_vlan" "vlan101" {
+  roles   = ["leafs"]
+  vlan_id = 101
+  vni     = 10101
+}
+
+resource "ciscoevpn_vlan" "vlan102" {
+  roles   = ["leafs"]
+  vlan_id = 102
+  vni     = 10102
+}
+
+resource "ciscoevpn_access_interface" "test" {
+  host      = %q
+  interface = "GigabitEthernet1/0/10"
+  %v
+}
+`, f.Leafs[0].Host(), port)
+}
@@ -3,9 +3,10 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"strconv"
+	"sort"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -19,10 +20,14 @@ import (
 func resourceCiscoNativeBgpNeighbor() *schema.Resource {
 	return &schema.Resource{
 		Description:   "Cisco BGP Neighbors",
-		CreateContext: resourceCiscoNativeBgpNeighborCreate,
+		CreateContext: transactional(resourceCiscoNativeBgpNeighborCreate),
 		ReadContext:   resourceCiscoNativeBgpNeighborRead,
-		UpdateContext: resourceCiscoNativeBgpNeighborUpdate,
-		DeleteContext: resourceCiscoNativeBgpNeighborDelete,
+		UpdateContext: transactional(resourceCiscoNativeBgpNeighborUpdate),
+		DeleteContext: transactional(resourceCiscoNativeBgpNeighborDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativeBgpNeighborImport,
+		},
+		CustomizeDiff: devicesCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"roles": {
 				Type:     schema.TypeList,
@@ -45,8 +50,10 @@ func resourceCiscoNativeBgpNeighbor() *schema.Resource {
 				Required: true,
 			},
 			"update_source": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateLoopback(),
+				DiffSuppressFunc: suppressLoopbackDiff,
 			},
 			"l2vpn_evpn": {
 				Type:     schema.TypeBool,
@@ -68,6 +75,9 @@ func resourceCiscoNativeBgpNeighbor() *schema.Resource {
 				Default:  false,
 				Optional: true,
 			},
+			"devices":           devicesSchema(),
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
 			"response": { // TODO remove?
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -83,26 +93,32 @@ func resourceCiscoNativeBgpNeighborCreate(ctx context.Context, d *schema.Resourc
 	var err error
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Provider: c.Provider,
+		Context: ctx,
+		Config:  c.Config,
 	}
 
 	id := loopbackId(d.Get("update_source").(string))
 	d.Set("update_source", id)
 
+	devices := getDevices(d)
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
-		devices := iosxe.HostRoles(c.Devices.List(), svc.Role) // TODO
-		for _, device := range devices {
+		for _, device := range iosxe.HostRoles(c.Devices.List(), svc.Role) {
 			svc.Device = device.(string)
 			svc.Method = "GET"
 			svc.Path = fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Loopback=%v", d.Get("update_source").(string))
 			loopback, err := c.loopbackIP(svc)
 			if err != nil {
+				setDevices(d, devices)
 				return diag.FromErr(err)
 			}
 
-			data := c.resourceCiscoIOSXEBgpNeighborData(d, loopback.CiscoIOSXENativeLoopback[0].IP.Address.Primary.Address, svc.Role)
+			data, dataDiags := c.resourceCiscoIOSXEBgpNeighborData(d, loopback.CiscoIOSXENativeLoopback[0].IP.Address.Primary.Address, svc.Role, fmt.Sprintf("host %v", svc.Device))
+			if dataDiags.HasError() {
+				setDevices(d, devices)
+				return dataDiags
+			}
 			if data == nil {
 				return diag.Errorf("No data in yang model")
 			}
@@ -110,18 +126,29 @@ func resourceCiscoNativeBgpNeighborCreate(ctx context.Context, d *schema.Resourc
 			if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 				svc.Payload = string(b)
 			}
-			if svc.Provider.Get("debug").(bool) {
-				debugJson(fmt.Sprintf("bgp_neighbors_%v_%v", svc.Role, loopback.CiscoIOSXENativeLoopback[0].IP.Address.Primary.Address), svc.Payload)
+			svc.CLI = data.CLI()
+			if svc.Config.Debug {
+				debugPayload(fmt.Sprintf("bgp_neighbors_%v_%v", svc.Role, loopback.CiscoIOSXENativeLoopback[0].IP.Address.Primary.Address), svc)
+			}
+			hash := payloadHash(svc)
+			if isApplied(devices, svc.Device, svc.Role, hash) {
+				log.Println("[DEBUG] Payload already applied on: ", svc.Device)
+				continue
 			}
 
 			svc.Method = "PATCH"
 			svc.Path = "/data/Cisco-IOS-XE-native:native/router/bgp"
 			_, err = iosxe.SingleSession(svc)
+			c.markSent(devices, svc.Device, svc, hash, err == nil)
 			if err != nil {
+				setDevices(d, devices)
 				return diag.FromErr(err)
 			}
 		}
 	}
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
 
 	err = d.Set("update_source", fmt.Sprintf("Loopback%v", id))
 	if err != nil {
@@ -132,8 +159,55 @@ func resourceCiscoNativeBgpNeighborCreate(ctx context.Context, d *schema.Resourc
 }
 
 func resourceCiscoNativeBgpNeighborRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	log.Println("[DEBUG] TODO")
+	log.Println("[DEBUG] Cisco BGP NEIGHBORS READ")
 	var diags diag.Diagnostics
+	c, _ := meta.(*providerClient)
+	svc := &service.Client{
+		Context: ctx,
+		Config:  c.Config,
+	}
+
+	id := loopbackId(d.Get("update_source").(string))
+	states := make(map[string]map[string]interface{})
+
+	roles := d.Get("roles").([]interface{})
+	for _, role := range roles {
+		svc.Role = role.(string)
+		devices := iosxe.HostRoles(c.Devices.List(), svc.Role)
+		for _, device := range devices {
+			svc.Device = device.(string)
+			svc.Method = "GET"
+			svc.Path = fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Loopback=%v", id)
+			loopback, err := c.loopbackIP(svc)
+			if err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+				return diag.FromErr(err)
+			}
+			localIP := ""
+			if err == nil && len(loopback.CiscoIOSXENativeLoopback) > 0 {
+				localIP = loopback.CiscoIOSXENativeLoopback[0].IP.Address.Primary.Address
+			}
+
+			svc.Path = fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v", d.Get("bgp_id").(int))
+			body, err := iosxe.SingleSession(svc)
+			data := &bgp.CiscoIOSXEBgpNeighbors{}
+			if err == nil {
+				err = unmarshalBody(body, data)
+			}
+			if errors.Is(err, iosxe.ErrNotFound) || (err == nil && len(data.CiscoIOSXEBgpBgp) == 0) {
+				log.Printf("[DEBUG] BGP %v not found on: %v\n", d.Get("bgp_id").(int), svc.Device)
+				d.SetId("")
+				return diags
+			}
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			states[svc.Device] = c.resourceCiscoIOSXEBgpNeighborState(d, data, localIP, svc.Role)
+		}
+	}
+
+	if err := setDevicesDriftState(d, states); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
@@ -146,33 +220,46 @@ func resourceCiscoNativeBgpNeighborUpdate(ctx context.Context, d *schema.Resourc
 		d.Set("bgp_id", oldState)
 		return diag.Errorf("Not supported to change BGP AS number")
 	}
-	if d.HasChange("roles") {
-		oldState, _ := d.GetChange("roles")
-		d.Set("roles", oldState)
-		return diag.Errorf("Not supported to change Roles")
-	}
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Provider: c.Provider,
+		Context: ctx,
+		Config:  c.Config,
 	}
 
 	id := loopbackId(d.Get("update_source").(string))
 	d.Set("update_source", id)
 
+	devices := getDevices(d)
+	for role, hosts := range c.removedHosts(d, devices) {
+		svc.Role = role
+		for _, host := range hosts {
+			svc.Device = host.(string)
+			if err = c.resourceCiscoNativeBgpNeighborRemove(d, svc); err != nil {
+				setDevices(d, devices)
+				return diag.FromErr(err)
+			}
+			delete(devices, svc.Device)
+		}
+	}
+
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
-		devices := iosxe.HostRoles(c.Devices.List(), svc.Role)
-		for _, device := range devices {
+		for _, device := range iosxe.HostRoles(c.Devices.List(), svc.Role) {
 			svc.Device = device.(string)
 			svc.Method = "GET"
 			svc.Path = fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Loopback=%v", d.Get("update_source").(string))
 			loopback, err := c.loopbackIP(svc)
 			if err != nil {
+				setDevices(d, devices)
 				return diag.FromErr(err)
 			}
 
-			data := c.resourceCiscoIOSXEBgpNeighborData(d, loopback.CiscoIOSXENativeLoopback[0].IP.Address.Primary.Address, svc.Role)
+			data, dataDiags := c.resourceCiscoIOSXEBgpNeighborData(d, loopback.CiscoIOSXENativeLoopback[0].IP.Address.Primary.Address, svc.Role, fmt.Sprintf("host %v", svc.Device))
+			if dataDiags.HasError() {
+				setDevices(d, devices)
+				return dataDiags
+			}
 			if data == nil {
 				return diag.Errorf("No data in yang model")
 			}
@@ -180,18 +267,29 @@ func resourceCiscoNativeBgpNeighborUpdate(ctx context.Context, d *schema.Resourc
 			if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 				svc.Payload = string(b)
 			}
-			if svc.Provider.Get("debug").(bool) {
-				debugJson(fmt.Sprintf("bgp_neighbors_%v_%v", svc.Role, loopback.CiscoIOSXENativeLoopback[0].IP.Address.Primary.Address), svc.Payload)
+			svc.CLI = data.CLI()
+			if svc.Config.Debug {
+				debugPayload(fmt.Sprintf("bgp_neighbors_%v_%v", svc.Role, loopback.CiscoIOSXENativeLoopback[0].IP.Address.Primary.Address), svc)
+			}
+			hash := payloadHash(svc)
+			if isApplied(devices, svc.Device, svc.Role, hash) {
+				log.Println("[DEBUG] Payload already applied on: ", svc.Device)
+				continue
 			}
 
 			svc.Method = "PATCH" // TODO Read Config and Patch
 			svc.Path = "/data/Cisco-IOS-XE-native:native/router/bgp"
 			_, err = iosxe.SingleSession(svc)
+			c.markSent(devices, svc.Device, svc, hash, err == nil)
 			if err != nil {
+				setDevices(d, devices)
 				return diag.FromErr(err)
 			}
 		}
 	}
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
 
 	err = d.Set("update_source", fmt.Sprintf("Loopback%v", id))
 	if err != nil {
@@ -206,48 +304,105 @@ func resourceCiscoNativeBgpNeighborDelete(ctx context.Context, d *schema.Resourc
 	var diags diag.Diagnostics
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Provider: c.Provider,
+		Context: ctx,
+		Config:  c.Config,
 	}
 
 	id := loopbackId(d.Get("update_source").(string))
 	d.Set("update_source", id)
 
-	roles := d.Get("roles").([]interface{})
-	for _, role := range roles {
-		svc.Role = role.(string)
-		devices := iosxe.HostRoles(c.Devices.List(), svc.Role)
-		for _, device := range devices {
-			svc.Device = device.(string)
-			svc.Method = "GET"
-			svc.Path = fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Loopback=%v", d.Get("update_source").(string))
-			loopback, err := c.loopbackIP(svc)
-			if err != nil {
-				return diag.FromErr(err)
-			}
-			for _, id := range d.Get("neighbors").([]interface{}) {
-				if id != loopback.CiscoIOSXENativeLoopback[0].IP.Address.Primary.Address {
-					svc.Method = "DELETE"
+	hosts := make(map[string]string)
+	for host, state := range getDevices(d) {
+		hosts[host] = state.Role
+	}
+	if len(hosts) == 0 {
+		hosts = c.roleHosts(d.Get("roles").([]interface{}))
+	}
+	for host, role := range hosts {
+		svc.Role = role
+		svc.Device = host
+		if err := c.resourceCiscoNativeBgpNeighborRemove(d, svc); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 
-					if d.Get("l2vpn_evpn").(bool) {
-						svc.Path = fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v/address-family/no-vrf/l2vpn/evpn/l2vpn-evpn/neighbor=%v", d.Get("bgp_id").(int), id)
-					}
-					_, err = iosxe.SingleSession(svc)
-					if err != nil {
-						return diag.FromErr(err)
-					}
+	d.SetId("")
+	return diags
+}
 
-					svc.Path = fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v/neighbor=%v", d.Get("bgp_id").(int), id)
-					_, err = iosxe.SingleSession(svc)
-					if err != nil {
-						return diag.FromErr(err)
-					}
+func resourceCiscoNativeBgpNeighborImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco BGP NEIGHBORS IMPORT")
+	c, _ := meta.(*providerClient)
+	parts, err := importID(d.Id(), "<roles>:<bgp_id>")
+	if err != nil {
+		return nil, err
+	}
+	id, err := importInt(parts[1], "bgp_id")
+	if err != nil {
+		return nil, err
+	}
+	if err = c.importRoles(d, parts[0]); err != nil {
+		return nil, err
+	}
+	d.Set("bgp_id", id)
+
+	// The neighbors are the union of the neighbors on the devices
+	bodies, err := c.readRoles(ctx, d, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v", id))
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]bool)
+	neighbors := []string{}
+	for _, body := range bodies {
+		data := &bgp.CiscoIOSXEBgpNeighbors{}
+		if err = unmarshalBody(body, data); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return nil, err
+		}
+		for _, system := range data.CiscoIOSXEBgpBgp {
+			for _, n := range system.Neighbor {
+				if !found[n.ID] {
+					found[n.ID] = true
+					neighbors = append(neighbors, n.ID)
 				}
+				d.Set("update_source", fmt.Sprintf("Loopback%v", n.UpdateSource.Interface.Loopback))
 			}
 		}
 	}
+	if len(neighbors) == 0 {
+		return nil, fmt.Errorf("no BGP neighbors found in BGP %v", id)
+	}
+	sort.Strings(neighbors)
+	d.Set("neighbors", neighbors)
+	d.SetId(fmt.Sprintf("bgp_id_%v", id))
+	return importRead(ctx, d, meta, resourceCiscoNativeBgpNeighborRead)
+}
 
-	d.SetId("")
-	return diags
+// resourceCiscoNativeBgpNeighborRemove deletes the neighbors on svc.Device
+func (c *providerClient) resourceCiscoNativeBgpNeighborRemove(d *schema.ResourceData, svc *service.Client) error {
+	svc.Method = "GET"
+	svc.Path = fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Loopback=%v", d.Get("update_source").(string))
+	loopback, err := c.loopbackIP(svc)
+	if err != nil {
+		return err
+	}
+	for _, id := range d.Get("neighbors").([]interface{}) {
+		if id != loopback.CiscoIOSXENativeLoopback[0].IP.Address.Primary.Address {
+			svc.Method = "DELETE"
+
+			if d.Get("l2vpn_evpn").(bool) {
+				svc.Path = fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v/address-family/no-vrf/l2vpn/evpn/l2vpn-evpn/neighbor=%v", d.Get("bgp_id").(int), id)
+				if _, err = iosxe.SingleSession(svc); err != nil {
+					return err
+				}
+			}
+
+			svc.Path = fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v/neighbor=%v", d.Get("bgp_id").(int), id)
+			if _, err = iosxe.SingleSession(svc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 func (*providerClient) loopbackIP(svc *service.Client) (*loopback.CiscoIOSXENativeLoopbackInterface, error) {
@@ -260,12 +415,15 @@ func (*providerClient) loopbackIP(svc *service.Client) (*loopback.CiscoIOSXENati
 
 	lp := &loopback.CiscoIOSXENativeLoopbackInterface{}
 	if err = json.Unmarshal([]byte(payload), &lp); err != nil {
-		log.Panicln("[PANIC] Error with JSON data: ", err)
+		return nil, fmt.Errorf("invalid Loopback data from %v: %v", svc.Device, err)
+	}
+	if len(lp.CiscoIOSXENativeLoopback) == 0 {
+		return nil, fmt.Errorf("no Loopback found on %v: %v", svc.Device, svc.Path)
 	}
 	return lp, nil
 }
 
-func (*providerClient) resourceCiscoIOSXEBgpNeighborData(d *schema.ResourceData, localIP string, role string) *bgp.CiscoIOSXEBgpNeighbors {
+func (*providerClient) resourceCiscoIOSXEBgpNeighborData(d *schema.ResourceData, localIP string, role string, target string) (*bgp.CiscoIOSXEBgpNeighbors, diag.Diagnostics) {
 	var n interface{}
 	data := &bgp.CiscoIOSXEBgpNeighbors{}
 	system := &bgp.CiscoIOSXEBgp{}
@@ -275,9 +433,9 @@ func (*providerClient) resourceCiscoIOSXEBgpNeighborData(d *schema.ResourceData,
 			systemNeighbor := &bgp.CiscoIOSXEBgpNeighborsNeighbor{}
 			systemNeighbor.ID = id.(string)
 			systemNeighbor.RemoteAs = d.Get("remote_as").(int)
-			loopback, err := strconv.Atoi(d.Get("update_source").(string))
-			if err != nil {
-				log.Panicln("[PANIC] Can't find Loopback ID", err)
+			loopback, diags := loopbackNumber(d, "update_source", target)
+			if diags.HasError() {
+				return nil, diags
 			}
 			systemNeighbor.UpdateSource.Interface.Loopback = loopback
 			system.Neighbor = append(system.Neighbor, *systemNeighbor)
@@ -310,6 +468,41 @@ func (*providerClient) resourceCiscoIOSXEBgpNeighborData(d *schema.ResourceData,
 		system.AddressFamily.NoVrf.L2Vpn = append(system.AddressFamily.NoVrf.L2Vpn, *EvpnAf)
 	}
 	data.CiscoIOSXEBgpBgp = append(data.CiscoIOSXEBgpBgp, *system)
-	return data
+	return data, nil
+
+}
 
+func (*providerClient) resourceCiscoIOSXEBgpNeighborState(d *schema.ResourceData, data *bgp.CiscoIOSXEBgpNeighbors, localIP string, role string) map[string]interface{} {
+	system := data.CiscoIOSXEBgpBgp[0]
+	state := make(map[string]interface{})
+	found := make(map[string]bool)
+	for _, n := range system.Neighbor {
+		found[n.ID] = true
+		state["remote_as"] = n.RemoteAs
+		state["update_source"] = fmt.Sprintf("Loopback%v", n.UpdateSource.Interface.Loopback)
+	}
+	// The local address is never configured as neighbor on the device itself
+	neighbors := []interface{}{}
+	for _, id := range d.Get("neighbors").([]interface{}) {
+		if id.(string) == localIP || found[id.(string)] {
+			neighbors = append(neighbors, id)
+		}
+	}
+	state["neighbors"] = neighbors
+
+	evpnNeighbors := []bgp.CiscoIOSXEBgpNeighborsEvpnNeighbor{}
+	for _, af := range system.AddressFamily.NoVrf.L2Vpn {
+		if af.AfName == "evpn" {
+			evpnNeighbors = append(evpnNeighbors, af.L2VpnEvpn.Neighbor...)
+		}
+	}
+	state["l2vpn_evpn"] = len(evpnNeighbors) > 0
+	for _, n := range evpnNeighbors {
+		state["activate"] = n.Activate != nil
+		state["send_community"] = n.SendCommunity.SendCommunityWhere
+		if role == "spines" {
+			state["route_reflector_client"] = n.RouteReflectorClient != nil
+		}
+	}
+	return state
 }
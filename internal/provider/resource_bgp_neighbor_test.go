@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnBgpNeighbor(t *testing.T) {
+	f := newTestAccFabric(t, 2, 2)
+	path := "Cisco-IOS-XE-native:native/router/bgp=65534/neighbor=100.119.100.1"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDevicesRemoved(f.Leafs, path),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnBgpNeighborConfig(f, "both"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_bgp_neighbor.test", "neighbors.#", "2"),
+					testAccCheckDevices(f.Leafs, path, `"remote-as":65534`, `"Loopback":100`),
+					testAccCheckDevices(f.Leafs, "Cisco-IOS-XE-native:native/router/bgp=65534/address-family/no-vrf/l2vpn/evpn/l2vpn-evpn/neighbor=100.119.100.2", `"both"`),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnBgpNeighborConfig(f, "extended"),
+				Check:  testAccCheckDevices(f.Leafs, "Cisco-IOS-XE-native:native/router/bgp=65534/address-family/no-vrf/l2vpn/evpn/l2vpn-evpn/neighbor=100.119.100.2", `"extended"`),
+			},
+		},
+	})
+}
+
+func testAccCiscoEvpnBgpNeighborConfig(f *testAccFabric, sendCommunity string) string {
+	return f.Config() + f.LoopbacksConfig(100) + fmt.Sprintf(`
+resource "ciscoevpn_bgp_system" "test" {
+  depends_on = [ciscoevpn_loopback.spine0_100, ciscoevpn_loopback.spine1_100, ciscoevpn_loopback.leaf0_100, ciscoevpn_loopback.leaf1_100]
+  roles      = ["spines", "leafs"]
+  router_id  = ciscoevpn_loopback.spine0_100.interface_name
+  bgp_id     = 65534
+}
+
+resource "ciscoevpn_bgp_neighbor" "test" {
+  roles  = ["leafs"]
+  bgp_id = ciscoevpn_bgp_system.test.bgp_id
+  neighbors = [
+    ciscoevpn_loopback.spine0_100.ipv4_address,
+    ciscoevpn_loopback.spine1_100.ipv4_address,
+  ]
+  update_source  = ciscoevpn_loopback.leaf0_100.interface_name
+  remote_as      = ciscoevpn_bgp_system.test.bgp_id
+  send_community = %q
+  activate       = true
+  l2vpn_evpn     = true
+}
+`, sendCommunity)
+}
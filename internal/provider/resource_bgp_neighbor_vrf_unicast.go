@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 
@@ -21,6 +22,9 @@ func resourceCiscoNativeBgpNeighborVrfUnicast() *schema.Resource {
 		ReadContext:   resourceCiscoNativeBgpNeighborVrfUnicastRead,
 		UpdateContext: resourceCiscoNativeBgpNeighborVrfUnicastUpdate,
 		DeleteContext: resourceCiscoNativeBgpNeighborVrfUnicastDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativeBgpNeighborVrfUnicastImport,
+		},
 		Schema: map[string]*schema.Schema{
 			"host": {
 				Type:         schema.TypeString,
@@ -50,6 +54,8 @@ func resourceCiscoNativeBgpNeighborVrfUnicast() *schema.Resource {
 				Required: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
 		},
 	}
 }
@@ -61,9 +67,10 @@ func resourceCiscoNativeBgpNeighborVrfUnicastCreate(ctx context.Context, d *sche
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Provider: c.Provider,
-		Device:   d.Get("host").(string),
+		Context: ctx,
+		Method:  "PATCH",
+		Config:  c.Config,
+		Device:  d.Get("host").(string),
 	}
 	if _, ok := d.GetOk("ipv4_neighbors"); ok {
 		data := c.resourceCiscoNativeBgpNeighborVrfUnicastIpv4Data(d)
@@ -75,22 +82,49 @@ func resourceCiscoNativeBgpNeighborVrfUnicastCreate(ctx context.Context, d *sche
 		if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 			svc.Payload = string(b)
 		}
-		if svc.Provider.Get("debug").(bool) {
-			debugJson(fmt.Sprintf("bgp_neighbor_vrf_unicast_ipv4_%v_%v", svc.Device, d.Get("vrf").(string)), svc.Payload)
+		svc.CLI = data.CLI(d.Get("bgp_id").(int), d.Get("vrf").(string))
+		if svc.Config.Debug {
+			debugPayload(fmt.Sprintf("bgp_neighbor_vrf_unicast_ipv4_%v_%v", svc.Device, d.Get("vrf").(string)), svc)
 		}
 
 		_, err = iosxe.SingleSession(svc)
 		if err != nil {
 			return diag.FromErr(err)
 		}
+		if err = c.setHostPreview(d, svc); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 	d.SetId(fmt.Sprintf("bgp_neighbor_vrf_unicast_%v", d.Get("vrf").(string)))
 	return diags
 }
 
 func resourceCiscoNativeBgpNeighborVrfUnicastRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	log.Println("[DEBUG] TODO")
-	return nil
+	log.Println("[DEBUG] Cisco NEIGHBORS VRF UNICAST READ")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	body, err := c.readHost(ctx, d, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v/address-family/with-vrf/ipv4/unicast/vrf=%v/ipv4-unicast", d.Get("bgp_id").(int), d.Get("vrf").(string)))
+	data := &bgp.CiscoIOSXEBgpVrfIpv4Unicast{}
+	if err == nil {
+		err = unmarshalBody(body, data)
+	}
+	if errors.Is(err, iosxe.ErrNotFound) || (err == nil && len(data.CiscoIOSXEBgpIpv4Unicast.Neighbor) == 0) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	state := c.resourceCiscoNativeBgpNeighborVrfUnicastIpv4State(d, data)
+	if err = setDriftState(d, map[string]map[string]interface{}{d.Get("host").(string): state}); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = setHostCli(d, c.resourceCiscoNativeBgpNeighborVrfUnicastIpv4Data(d).CLI(d.Get("bgp_id").(int), d.Get("vrf").(string))); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
 }
 
 func resourceCiscoNativeBgpNeighborVrfUnicastUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -111,9 +145,10 @@ func resourceCiscoNativeBgpNeighborVrfUnicastUpdate(ctx context.Context, d *sche
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Provider: c.Provider,
-		Device:   d.Get("host").(string),
+		Context: ctx,
+		Method:  "PATCH",
+		Config:  c.Config,
+		Device:  d.Get("host").(string),
 	}
 
 	if _, ok := d.GetOk("ipv4_neighbors"); ok {
@@ -126,14 +161,18 @@ func resourceCiscoNativeBgpNeighborVrfUnicastUpdate(ctx context.Context, d *sche
 		if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 			svc.Payload = string(b)
 		}
-		if svc.Provider.Get("debug").(bool) {
-			debugJson(fmt.Sprintf("bgp_neighbor_vrf_unicast_ipv4_%v_%v", svc.Device, d.Get("vrf").(string)), svc.Payload)
+		svc.CLI = data.CLI(d.Get("bgp_id").(int), d.Get("vrf").(string))
+		if svc.Config.Debug {
+			debugPayload(fmt.Sprintf("bgp_neighbor_vrf_unicast_ipv4_%v_%v", svc.Device, d.Get("vrf").(string)), svc)
 		}
 
 		_, err = iosxe.SingleSession(svc)
 		if err != nil {
 			return diag.FromErr(err)
 		}
+		if err = c.setHostPreview(d, svc); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 	d.SetId(fmt.Sprintf("bgp_neighbor_vrf_unicast_%v", d.Get("vrf").(string)))
 	return diags
@@ -145,9 +184,10 @@ func resourceCiscoNativeBgpNeighborVrfUnicastDelete(ctx context.Context, d *sche
 	var err error
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "DELETE",
-		Provider: c.Provider,
-		Device:   d.Get("host").(string),
+		Context: ctx,
+		Method:  "DELETE",
+		Config:  c.Config,
+		Device:  d.Get("host").(string),
 	}
 
 	if _, ok := d.GetOk("ipv4_neighbors"); ok {
@@ -165,6 +205,23 @@ func resourceCiscoNativeBgpNeighborVrfUnicastDelete(ctx context.Context, d *sche
 	return diags
 }
 
+func resourceCiscoNativeBgpNeighborVrfUnicastImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco NEIGHBORS VRF UNICAST IMPORT")
+	parts, err := importHostID(d.Id(), "<host>:<bgp_id>:<vrf>")
+	if err != nil {
+		return nil, err
+	}
+	id, err := importInt(parts[1], "bgp_id")
+	if err != nil {
+		return nil, err
+	}
+	d.Set("host", parts[0])
+	d.Set("bgp_id", id)
+	d.Set("vrf", parts[2])
+	d.SetId(fmt.Sprintf("bgp_neighbor_vrf_unicast_%v", parts[2]))
+	return importRead(ctx, d, meta, resourceCiscoNativeBgpNeighborVrfUnicastRead)
+}
+
 func (*providerClient) resourceCiscoNativeBgpNeighborVrfUnicastIpv4Data(d *schema.ResourceData) *bgp.CiscoIOSXEBgpVrfIpv4Unicast {
 	data := &bgp.CiscoIOSXEBgpVrfIpv4Unicast{}
 
@@ -181,3 +238,16 @@ func (*providerClient) resourceCiscoNativeBgpNeighborVrfUnicastIpv4Data(d *schem
 	}
 	return data
 }
+
+func (*providerClient) resourceCiscoNativeBgpNeighborVrfUnicastIpv4State(d *schema.ResourceData, data *bgp.CiscoIOSXEBgpVrfIpv4Unicast) map[string]interface{} {
+	neighbors := []interface{}{}
+	for _, n := range data.CiscoIOSXEBgpIpv4Unicast.Neighbor {
+		neighbors = append(neighbors, n.ID)
+	}
+	n := data.CiscoIOSXEBgpIpv4Unicast.Neighbor[0]
+	return map[string]interface{}{
+		"ipv4_neighbors": orderLike(d.Get("ipv4_neighbors").([]interface{}), neighbors),
+		"remote_as":      n.RemoteAs,
+		"activate":       n.Activate != nil,
+	}
+}
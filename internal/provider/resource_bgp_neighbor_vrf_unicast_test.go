@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/simulator"
+)
+
+func TestAccCiscoEvpnBgpNeighborVrfUnicast(t *testing.T) {
+	f := newTestAccFabric(t, 1, 1)
+	leaf := []*simulator.Device{f.Leafs[0]}
+	path := "Cisco-IOS-XE-native:native/router/bgp=65534/address-family/with-vrf/ipv4/unicast/vrf=green/ipv4-unicast"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnBgpNeighborVrfUnicastConfig(f, `"100.119.253.9"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_bgp_neighbor_vrf_unicast.test", "ipv4_neighbors.#", "1"),
+					testAccCheckDevices(leaf, path, `"100.119.253.9"`, `"remote-as":65000`),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnBgpNeighborVrfUnicastConfig(f, `"100.119.253.9", "100.119.253.13"`),
+				Check:  testAccCheckDevices(leaf, path, `"100.119.253.9"`, `"100.119.253.13"`),
+			},
+		},
+	})
+}
+
+func testAccCiscoEvpnBgpNeighborVrfUnicastConfig(f *testAccFabric, neighbors string) string {
+	return f.Config() + f.LoopbacksConfig(100) + fmt.Sprintf(`
+resource "ciscoevpn_bgp_system" "test" {
+  depends_on = [ciscoevpn_loopback.leaf0_100]
+  roles      = ["leafs"]
+  router_id  = ciscoevpn_loopback.leaf0_100.interface_name
+  bgp_id     = 65534
+}
+
+resource "ciscoevpn_bgp_vrf" "test" {
+  roles  = ["leafs"]
+  bgp_id = ciscoevpn_bgp_system.test.bgp_id
+  vrf    = "green"
+}
+
+resource "ciscoevpn_bgp_neighbor_vrf_unicast" "test" {
+  depends_on     = [ciscoevpn_bgp_vrf.test]
+  host           = %q
+  bgp_id         = ciscoevpn_bgp_system.test.bgp_id
+  remote_as      = 65000
+  vrf            = ciscoevpn_bgp_vrf.test.vrf
+  ipv4_neighbors = [%v]
+}
+`, f.Leafs[0].Host(), neighbors)
+}
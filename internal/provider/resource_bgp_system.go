@@ -3,13 +3,12 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/bgp"
 	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/iosxe"
 	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
@@ -18,10 +17,14 @@ import (
 func resourceCiscoNativeBgpSystem() *schema.Resource {
 	return &schema.Resource{
 		Description:   "Cisco BGP System",
-		CreateContext: resourceCiscoNativeBgpSystemCreate,
+		CreateContext: transactional(resourceCiscoNativeBgpSystemCreate),
 		ReadContext:   resourceCiscoNativeBgpSystemRead,
-		UpdateContext: resourceCiscoNativeBgpSystemUpdate,
-		DeleteContext: resourceCiscoNativeBgpSystemDelete,
+		UpdateContext: transactional(resourceCiscoNativeBgpSystemUpdate),
+		DeleteContext: transactional(resourceCiscoNativeBgpSystemDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativeBgpSystemImport,
+		},
+		CustomizeDiff: devicesCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"roles": {
 				Type:     schema.TypeList,
@@ -35,9 +38,10 @@ func resourceCiscoNativeBgpSystem() *schema.Resource {
 				Required: true,
 			},
 			"router_id": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateLoopback(),
+				DiffSuppressFunc: suppressLoopbackDiff,
 			},
 			"log_neighbor_changes": {
 				Type:     schema.TypeBool,
@@ -49,6 +53,9 @@ func resourceCiscoNativeBgpSystem() *schema.Resource {
 				Default:  false,
 				Optional: true,
 			},
+			"devices":           devicesSchema(),
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
 		},
 	}
 }
@@ -59,19 +66,25 @@ func resourceCiscoNativeBgpSystemCreate(ctx context.Context, d *schema.ResourceD
 	var err error
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Path:     "/data/Cisco-IOS-XE-native:native/router/bgp",
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    "/data/Cisco-IOS-XE-native:native/router/bgp",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 
 	id := loopbackId(d.Get("router_id").(string))
 	d.Set("router_id", id)
 
+	devices := getDevices(d)
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
-		data := c.resourceCiscoIOSXEBgpSystemData(d)
+		data, dataDiags := c.resourceCiscoIOSXEBgpSystemData(d, c.roleTarget(svc.Role))
+		if dataDiags.HasError() {
+			setDevices(d, devices)
+			return dataDiags
+		}
 		if data == nil {
 			return diag.Errorf("No data in yang model")
 		}
@@ -79,12 +92,14 @@ func resourceCiscoNativeBgpSystemCreate(ctx context.Context, d *schema.ResourceD
 		if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 			svc.Payload = string(b)
 		}
-		if svc.Provider.Get("debug").(bool) {
-			debugJson(fmt.Sprintf("bgp_system_%v_%v", svc.Role, d.Get("bgp_id")), svc.Payload)
+		svc.CLI = data.CLI()
+		if svc.Config.Debug {
+			debugPayload(fmt.Sprintf("bgp_system_%v_%v", svc.Role, d.Get("bgp_id")), svc)
 		}
 
-		_, err = iosxe.MultiSession(svc)
+		err = c.applyRole(svc, devices)
 		if err != nil {
+			setDevices(d, devices)
 			return diag.FromErr(err)
 		}
 	}
@@ -94,12 +109,40 @@ func resourceCiscoNativeBgpSystemCreate(ctx context.Context, d *schema.ResourceD
 		return diag.FromErr(err)
 	}
 	d.SetId(fmt.Sprintf("bgp_systems_%v", d.Get("bgp_id").(int)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
 func resourceCiscoNativeBgpSystemRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	log.Println("[DEBUG] TODO")
-	return nil
+	log.Println("[DEBUG] Cisco BGP SYSTEM READ")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	bodies, err := c.readRoles(ctx, d, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v", d.Get("bgp_id").(int)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	states := make(map[string]map[string]interface{})
+	for host, body := range bodies {
+		data := &bgp.CiscoIOSXEBgpBgpSystem{}
+		if err = unmarshalBody(body, data); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		if len(data.CiscoIOSXEBgpBgp) == 0 {
+			log.Printf("[DEBUG] BGP %v not found on: %v\n", d.Get("bgp_id").(int), host)
+			d.SetId("")
+			return diags
+		}
+		states[host] = c.resourceCiscoIOSXEBgpSystemState(data)
+	}
+
+	if err = setDevicesDriftState(d, states); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
 }
 
 func resourceCiscoNativeBgpSystemUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -111,27 +154,33 @@ func resourceCiscoNativeBgpSystemUpdate(ctx context.Context, d *schema.ResourceD
 		d.Set("bgp_id", oldState)
 		return diag.Errorf("Not supported to change BGP AS number")
 	}
-	if d.HasChange("roles") {
-		oldState, _ := d.GetChange("roles")
-		d.Set("roles", oldState)
-		return diag.Errorf("Not supported to change Roles")
-	}
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Path:     fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v", d.Get("bgp_id").(int)),
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v", d.Get("bgp_id").(int)),
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 
 	// TODO support interface vs ip_address
 	id := loopbackId(d.Get("router_id").(string))
 	d.Set("router_id", id)
 
+	devices := getDevices(d)
+	if err = c.removeHosts(ctx, d, devices, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v", d.Get("bgp_id").(int))); err != nil {
+		setDevices(d, devices)
+		return diag.FromErr(err)
+	}
+
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
-		data := c.resourceCiscoIOSXEBgpSystemData(d)
+		data, dataDiags := c.resourceCiscoIOSXEBgpSystemData(d, c.roleTarget(svc.Role))
+		if dataDiags.HasError() {
+			setDevices(d, devices)
+			return dataDiags
+		}
 		if data == nil {
 			return diag.Errorf("No data in yang model")
 		}
@@ -139,12 +188,14 @@ func resourceCiscoNativeBgpSystemUpdate(ctx context.Context, d *schema.ResourceD
 		if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 			svc.Payload = string(b)
 		}
-		if svc.Provider.Get("debug").(bool) {
-			debugJson(fmt.Sprintf("bgp_system_%v_%v", svc.Role, d.Get("bgp_id")), svc.Payload)
+		svc.CLI = data.CLI()
+		if svc.Config.Debug {
+			debugPayload(fmt.Sprintf("bgp_system_%v_%v", svc.Role, d.Get("bgp_id")), svc)
 		}
 
-		_, err = iosxe.MultiSession(svc)
+		err = c.applyRole(svc, devices)
 		if err != nil {
+			setDevices(d, devices)
 			return diag.FromErr(err)
 		}
 	}
@@ -154,6 +205,9 @@ func resourceCiscoNativeBgpSystemUpdate(ctx context.Context, d *schema.ResourceD
 		return diag.FromErr(err)
 	}
 	d.SetId(fmt.Sprintf("bgp_systems_%v", d.Get("bgp_id").(int)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
@@ -162,16 +216,18 @@ func resourceCiscoNativeBgpSystemDelete(ctx context.Context, d *schema.ResourceD
 	var diags diag.Diagnostics
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "DELETE",
-		Path:     fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v", d.Get("bgp_id").(int)),
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "DELETE",
+		Path:    fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v", d.Get("bgp_id").(int)),
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 
+	devices := getDevices(d)
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
-		_, err := iosxe.MultiSession(svc)
+		err := c.deleteRole(svc, devices)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -180,14 +236,33 @@ func resourceCiscoNativeBgpSystemDelete(ctx context.Context, d *schema.ResourceD
 	return diags
 }
 
-func (*providerClient) resourceCiscoIOSXEBgpSystemData(d *schema.ResourceData) *bgp.CiscoIOSXEBgpBgpSystem {
+func resourceCiscoNativeBgpSystemImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco BGP SYSTEM IMPORT")
+	c, _ := meta.(*providerClient)
+	parts, err := importID(d.Id(), "<roles>:<bgp_id>")
+	if err != nil {
+		return nil, err
+	}
+	id, err := importInt(parts[1], "bgp_id")
+	if err != nil {
+		return nil, err
+	}
+	if err = c.importRoles(d, parts[0]); err != nil {
+		return nil, err
+	}
+	d.Set("bgp_id", id)
+	d.SetId(fmt.Sprintf("bgp_systems_%v", id))
+	return importRead(ctx, d, meta, resourceCiscoNativeBgpSystemRead)
+}
+
+func (*providerClient) resourceCiscoIOSXEBgpSystemData(d *schema.ResourceData, target string) (*bgp.CiscoIOSXEBgpBgpSystem, diag.Diagnostics) {
 	data := &bgp.CiscoIOSXEBgpBgpSystem{}
 	system := &bgp.CiscoIOSXEBgp{}
 	system.ID = d.Get("bgp_id").(int)
 	system.Bgp.LogNeighborChanges = d.Get("log_neighbor_changes").(bool)
-	id, err := strconv.Atoi(d.Get("router_id").(string))
-	if err != nil {
-		log.Panicln("[PANIC] Can't find Loopback ID", err)
+	id, diags := loopbackNumber(d, "router_id", target)
+	if diags.HasError() {
+		return nil, diags
 	}
 	system.Bgp.RouterID.Interface.Loopback = id
 	if d.Get("default_ipv4_unicast").(bool) {
@@ -196,5 +271,14 @@ func (*providerClient) resourceCiscoIOSXEBgpSystemData(d *schema.ResourceData) *
 		system.Bgp.Default.Ipv4Unicast = false
 	}
 	data.CiscoIOSXEBgpBgp = append(data.CiscoIOSXEBgpBgp, *system)
-	return data
+	return data, nil
+}
+
+func (*providerClient) resourceCiscoIOSXEBgpSystemState(data *bgp.CiscoIOSXEBgpBgpSystem) map[string]interface{} {
+	system := data.CiscoIOSXEBgpBgp[0]
+	return map[string]interface{}{
+		"router_id":            fmt.Sprintf("Loopback%v", system.Bgp.RouterID.Interface.Loopback),
+		"log_neighbor_changes": system.Bgp.LogNeighborChanges,
+		"default_ipv4_unicast": system.Bgp.Default.Ipv4Unicast,
+	}
 }
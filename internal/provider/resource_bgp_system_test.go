@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnBgpSystem(t *testing.T) {
+	f := newTestAccFabric(t, 2, 2)
+	path := "Cisco-IOS-XE-native:native/router/bgp=65534"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDevicesRemoved(f.Devices(), path),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnBgpSystemConfig(f, 100),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_bgp_system.test", "devices.#", "4"),
+					testAccCheckDevices(f.Devices(), path, `"id":65534`, `"Loopback":100`, `"log-neighbor-changes"`),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnBgpSystemConfig(f, 200),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_bgp_system.test", "router_id", "Loopback200"),
+					testAccCheckDevices(f.Devices(), path, `"Loopback":200`),
+				),
+			},
+		},
+	})
+}
+
+func testAccCiscoEvpnBgpSystemConfig(f *testAccFabric, routerID int) string {
+	return f.Config() + f.LoopbacksConfig(100) + f.LoopbacksConfig(200) + fmt.Sprintf(`
+resource "ciscoevpn_bgp_system" "test" {
+  depends_on           = [ciscoevpn_loopback.spine0_%[1]v, ciscoevpn_loopback.spine1_%[1]v, ciscoevpn_loopback.leaf0_%[1]v, ciscoevpn_loopback.leaf1_%[1]v]
+  roles                = ["spines", "leafs"]
+  router_id            = ciscoevpn_loopback.spine0_%[1]v.interface_name
+  bgp_id               = 65534
+  log_neighbor_changes = true
+}
+`, routerID)
+}
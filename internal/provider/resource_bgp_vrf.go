@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 
@@ -17,10 +18,14 @@ import (
 func resourceCiscoNativeBgpVrf() *schema.Resource {
 	return &schema.Resource{
 		Description:   "Cisco BGP VRF",
-		CreateContext: resourceCiscoNativeBgpVrfCreate,
+		CreateContext: transactional(resourceCiscoNativeBgpVrfCreate),
 		ReadContext:   resourceCiscoNativeBgpVrfRead,
-		UpdateContext: resourceCiscoNativeBgpVrfUpdate,
-		DeleteContext: resourceCiscoNativeBgpVrfDelete,
+		UpdateContext: transactional(resourceCiscoNativeBgpVrfUpdate),
+		DeleteContext: transactional(resourceCiscoNativeBgpVrfDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativeBgpVrfImport,
+		},
+		CustomizeDiff: devicesCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"roles": {
 				Type:     schema.TypeList,
@@ -58,6 +63,9 @@ func resourceCiscoNativeBgpVrf() *schema.Resource {
 				Default:  true,
 				Optional: true,
 			},
+			"devices":           devicesSchema(),
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
 		},
 	}
 }
@@ -69,12 +77,14 @@ func resourceCiscoNativeBgpVrfCreate(ctx context.Context, d *schema.ResourceData
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Path:     fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v/address-family/with-vrf", d.Get("bgp_id").(int)), // TODO use GET for BGP id
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v/address-family/with-vrf", d.Get("bgp_id").(int)), // TODO use GET for BGP id
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 
+	devices := getDevices(d)
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
@@ -86,23 +96,54 @@ func resourceCiscoNativeBgpVrfCreate(ctx context.Context, d *schema.ResourceData
 		if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 			svc.Payload = string(b)
 		}
-		if svc.Provider.Get("debug").(bool) {
-			debugJson(fmt.Sprintf("bgp_vrf_%v_%v", svc.Role, d.Get("vrf").(string)), svc.Payload)
+		svc.CLI = data.CLI(d.Get("bgp_id").(int))
+		if svc.Config.Debug {
+			debugPayload(fmt.Sprintf("bgp_vrf_%v_%v", svc.Role, d.Get("vrf").(string)), svc)
 		}
 
-		_, err = iosxe.MultiSession(svc)
+		err = c.applyRole(svc, devices)
 		if err != nil {
+			setDevices(d, devices)
 			return diag.FromErr(err)
 		}
 	}
 
 	d.SetId(fmt.Sprintf("bgp_vrf_%v", d.Get("vrf").(string)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
 func resourceCiscoNativeBgpVrfRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	log.Println("[DEBUG] TODO")
-	return nil
+	log.Println("[DEBUG] Cisco BGP VRF READ")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	bodies, err := c.readRoles(ctx, d, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v/address-family/with-vrf", d.Get("bgp_id").(int)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	states := make(map[string]map[string]interface{})
+	for host, body := range bodies {
+		data := &bgp.CiscoIOSXEBgpWithVrfs{}
+		if err = unmarshalBody(body, data); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		state := c.CiscoIOSXENativeVrfBgpState(d, data)
+		if state == nil {
+			log.Printf("[DEBUG] BGP VRF %v not found on: %v\n", d.Get("vrf").(string), host)
+			d.SetId("")
+			return diags
+		}
+		states[host] = state
+	}
+
+	if err = setDevicesDriftState(d, states); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
 }
 
 func resourceCiscoNativeBgpVrfUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -120,16 +161,25 @@ func resourceCiscoNativeBgpVrfUpdate(ctx context.Context, d *schema.ResourceData
 		d.Set("vrf", oldState)
 		return diag.Errorf("Not supported to change VRF name")
 	}
-	if d.HasChange("roles") {
-		oldState, _ := d.GetChange("roles")
-		d.Set("roles", oldState)
-		return diag.Errorf("Not supported to change Roles")
-	}
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	devices := getDevices(d)
+	var paths []string
+	if ipv4, _ := d.GetChange("ipv4"); ipv4.(bool) {
+		paths = append(paths, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v/address-family/with-vrf/ipv4/unicast/vrf=%v", d.Get("bgp_id").(int), d.Get("vrf").(string)))
+	}
+	if ipv6, _ := d.GetChange("ipv6"); ipv6.(bool) {
+		paths = append(paths, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v/address-family/with-vrf/ipv6/unicast/vrf=%v", d.Get("bgp_id").(int), d.Get("vrf").(string)))
+	}
+	if err = c.removeHosts(ctx, d, devices, paths...); err != nil {
+		setDevices(d, devices)
+		return diag.FromErr(err)
 	}
 
 	roles := d.Get("roles").([]interface{})
@@ -139,8 +189,9 @@ func resourceCiscoNativeBgpVrfUpdate(ctx context.Context, d *schema.ResourceData
 			if !d.Get("ipv4").(bool) {
 				svc.Method = "DELETE"
 				svc.Path = fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v/address-family/with-vrf/ipv4/unicast/vrf=%v", d.Get("bgp_id").(int), d.Get("vrf").(string))
-				_, err = iosxe.MultiSession(svc)
+				err = c.deleteRole(svc, devices)
 				if err != nil {
+					setDevices(d, devices)
 					return diag.FromErr(err)
 				}
 			}
@@ -149,8 +200,9 @@ func resourceCiscoNativeBgpVrfUpdate(ctx context.Context, d *schema.ResourceData
 			if !d.Get("ipv6").(bool) {
 				svc.Method = "DELETE"
 				svc.Path = fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v/address-family/with-vrf/ipv6/unicast/vrf=%v", d.Get("bgp_id").(int), d.Get("vrf").(string))
-				_, err = iosxe.MultiSession(svc)
+				err = c.deleteRole(svc, devices)
 				if err != nil {
+					setDevices(d, devices)
 					return diag.FromErr(err)
 				}
 			}
@@ -166,17 +218,22 @@ func resourceCiscoNativeBgpVrfUpdate(ctx context.Context, d *schema.ResourceData
 		if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 			svc.Payload = string(b)
 		}
-		if svc.Provider.Get("debug").(bool) {
-			debugJson(fmt.Sprintf("bgp_vrf_%v_%v", svc.Role, d.Get("vrf").(string)), svc.Payload)
+		svc.CLI = data.CLI(d.Get("bgp_id").(int))
+		if svc.Config.Debug {
+			debugPayload(fmt.Sprintf("bgp_vrf_%v_%v", svc.Role, d.Get("vrf").(string)), svc)
 		}
 
-		_, err = iosxe.MultiSession(svc)
+		err = c.applyRole(svc, devices)
 		if err != nil {
+			setDevices(d, devices)
 			return diag.FromErr(err)
 		}
 	}
 
 	d.SetId(fmt.Sprintf("bgp_vrf_%v", d.Get("vrf").(string)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
@@ -187,24 +244,26 @@ func resourceCiscoNativeBgpVrfDelete(ctx context.Context, d *schema.ResourceData
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "DELETE",
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "DELETE",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 
+	devices := getDevices(d)
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
 		if d.Get("ipv4").(bool) {
 			svc.Path = fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v/address-family/with-vrf/ipv4/unicast/vrf=%v", d.Get("bgp_id").(int), d.Get("vrf").(string))
-			_, err = iosxe.MultiSession(svc)
+			err = c.deleteRole(svc, devices)
 			if err != nil {
 				return diag.FromErr(err)
 			}
 		}
 		if d.Get("ipv6").(bool) {
 			svc.Path = fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v/address-family/with-vrf/ipv6/unicast/vrf=%v", d.Get("bgp_id").(int), d.Get("vrf").(string))
-			_, err = iosxe.MultiSession(svc)
+			err = c.deleteRole(svc, devices)
 			if err != nil {
 				return diag.FromErr(err)
 			}
@@ -215,6 +274,26 @@ func resourceCiscoNativeBgpVrfDelete(ctx context.Context, d *schema.ResourceData
 	return diags
 }
 
+func resourceCiscoNativeBgpVrfImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco BGP VRF IMPORT")
+	c, _ := meta.(*providerClient)
+	parts, err := importID(d.Id(), "<roles>:<bgp_id>:<vrf>")
+	if err != nil {
+		return nil, err
+	}
+	id, err := importInt(parts[1], "bgp_id")
+	if err != nil {
+		return nil, err
+	}
+	if err = c.importRoles(d, parts[0]); err != nil {
+		return nil, err
+	}
+	d.Set("bgp_id", id)
+	d.Set("vrf", parts[2])
+	d.SetId(fmt.Sprintf("bgp_vrf_%v", parts[2]))
+	return importRead(ctx, d, meta, resourceCiscoNativeBgpVrfRead)
+}
+
 func (*providerClient) CiscoIOSXENativeVrfBgp(d *schema.ResourceData) *bgp.CiscoIOSXEBgpWithVrfs {
 	data := &bgp.CiscoIOSXEBgpWithVrf{}
 
@@ -250,3 +329,40 @@ func (*providerClient) CiscoIOSXENativeVrfBgp(d *schema.ResourceData) *bgp.Cisco
 	}
 	return &bgp.CiscoIOSXEBgpWithVrfs{*data}
 }
+
+func (*providerClient) CiscoIOSXENativeVrfBgpState(d *schema.ResourceData, data *bgp.CiscoIOSXEBgpWithVrfs) map[string]interface{} {
+	var ipv4 *bgp.CiscoIOSXEBgpWithVrfVrfIpv4
+	var ipv6 *bgp.CiscoIOSXEBgpWithVrfVrfIpv6
+	name := d.Get("vrf").(string)
+
+	for _, af := range data.CiscoIOSXEBgpWithVrf.Ipv4 {
+		for i := range af.Vrf {
+			if af.AfName == "unicast" && af.Vrf[i].Name == name {
+				ipv4 = &af.Vrf[i]
+			}
+		}
+	}
+	for _, af := range data.CiscoIOSXEBgpWithVrf.Ipv6 {
+		for i := range af.Vrf {
+			if af.AfName == "unicast" && af.Vrf[i].Name == name {
+				ipv6 = &af.Vrf[i]
+			}
+		}
+	}
+	if ipv4 == nil && ipv6 == nil {
+		return nil
+	}
+
+	state := map[string]interface{}{
+		"ipv4": ipv4 != nil,
+		"ipv6": ipv6 != nil,
+	}
+	if ipv4 != nil {
+		state["redistribute_connected"] = ipv4.Ipv4Unicast.RedistributeVrf.Connected != nil
+		state["redistribute_static"] = ipv4.Ipv4Unicast.RedistributeVrf.Static != nil
+	} else {
+		state["redistribute_connected"] = ipv6.Ipv6Unicast.RedistributeV6.Connected != nil
+		state["redistribute_static"] = ipv6.Ipv6Unicast.RedistributeV6.Static != nil
+	}
+	return state
+}
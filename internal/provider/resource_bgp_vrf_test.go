@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnBgpVrf(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	path := "Cisco-IOS-XE-native:native/router/bgp=65534/address-family/with-vrf/ipv4/unicast/vrf=green"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDevicesRemoved(f.Leafs, path),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnBgpVrfConfig(f, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_bgp_vrf.test", "vrf", "green"),
+					testAccCheckDevices(f.Leafs, path, `"connected"`),
+					testAccCheckDevicesRemoved(f.Leafs, "Cisco-IOS-XE-native:native/router/bgp=65534/address-family/with-vrf/ipv6/unicast/vrf=green"),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnBgpVrfConfig(f, true),
+				Check:  testAccCheckDevices(f.Leafs, "Cisco-IOS-XE-native:native/router/bgp=65534/address-family/with-vrf/ipv6/unicast/vrf=green", `"connected"`),
+			},
+		},
+	})
+}
+
+func testAccCiscoEvpnBgpVrfConfig(f *testAccFabric, ipv6 bool) string {
+	return f.Config() + f.LoopbacksConfig(100) + fmt.Sprintf(`
+resource "ciscoevpn_bgp_system" "test" {
+  depends_on = [ciscoevpn_loopback.leaf0_100, ciscoevpn_loopback.leaf1_100]
+  roles      = ["leafs"]
+  router_id  = ciscoevpn_loopback.leaf0_100.interface_name
+  bgp_id     = 65534
+}
+
+resource "ciscoevpn_vrf" "test" {
+  roles = ["leafs"]
+  name  = "green"
+  rd    = "1:1"
+}
+
+resource "ciscoevpn_bgp_vrf" "test" {
+  roles                  = ["leafs"]
+  bgp_id                 = ciscoevpn_bgp_system.test.bgp_id
+  vrf                    = ciscoevpn_vrf.test.name
+  ipv4                   = true
+  ipv6                   = %v
+  redistribute_connected = true
+  redistribute_static    = true
+}
+`, ipv6)
+}
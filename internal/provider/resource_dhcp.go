@@ -3,8 +3,10 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -16,10 +18,14 @@ import (
 func resourceCiscoNativeDhcp() *schema.Resource {
 	return &schema.Resource{
 		Description:   "Cisco DHCP Global",
-		CreateContext: resourceCiscoNativeDhcpCreate,
+		CreateContext: transactional(resourceCiscoNativeDhcpCreate),
 		ReadContext:   resourceCiscoNativeDhcpRead,
-		UpdateContext: resourceCiscoNativeDhcpUpdate,
-		DeleteContext: resourceCiscoNativeDhcpDelete,
+		UpdateContext: transactional(resourceCiscoNativeDhcpUpdate),
+		DeleteContext: transactional(resourceCiscoNativeDhcpDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativeDhcpImport,
+		},
+		CustomizeDiff: devicesCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"roles": {
 				Type:     schema.TypeList,
@@ -40,6 +46,9 @@ func resourceCiscoNativeDhcp() *schema.Resource {
 				Optional: true,
 				Default:  true,
 			},
+			"devices":           devicesSchema(),
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
 		},
 	}
 }
@@ -51,12 +60,14 @@ func resourceCiscoNativeDhcpCreate(ctx context.Context, d *schema.ResourceData,
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Path:     "/data/Cisco-IOS-XE-native:native/ip/dhcp",
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    "/data/Cisco-IOS-XE-native:native/ip/dhcp",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 
+	devices := getDevices(d)
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
@@ -68,23 +79,52 @@ func resourceCiscoNativeDhcpCreate(ctx context.Context, d *schema.ResourceData,
 		if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 			svc.Payload = string(b)
 		}
-		if svc.Provider.Get("debug").(bool) {
-			debugJson(fmt.Sprintf("dhcp_%v", svc.Role), svc.Payload)
+		svc.CLI = data.CLI()
+		if svc.Config.Debug {
+			debugPayload(fmt.Sprintf("dhcp_%v", svc.Role), svc)
 		}
 
-		_, err = iosxe.MultiSession(svc)
+		err = c.applyRole(svc, devices)
 		if err != nil {
+			setDevices(d, devices)
 			return diag.FromErr(err)
 		}
 	}
 
 	d.SetId(fmt.Sprintf("dhcp_global_%v", svc.Role))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
 func resourceCiscoNativeDhcpRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	log.Println("[DEBUG] TODO")
+	log.Println("[DEBUG] Cisco DHCP READ")
 	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	bodies, err := c.readRoles(ctx, d, "/data/Cisco-IOS-XE-native:native/ip/dhcp")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	states := make(map[string]map[string]interface{})
+	for host, body := range bodies {
+		data := &dhcp.CiscoIOSXENativeDhcps{}
+		if err = unmarshalBody(body, data); err != nil {
+			if errors.Is(err, iosxe.ErrNotFound) {
+				log.Println("[DEBUG] DHCP not found on: ", host)
+				d.SetId("")
+				return diags
+			}
+			return diag.FromErr(err)
+		}
+		states[host] = c.resourceCiscoNativeDhcpState(d, data)
+	}
+
+	if err = setDevicesDriftState(d, states); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
@@ -93,17 +133,19 @@ func resourceCiscoNativeDhcpUpdate(ctx context.Context, d *schema.ResourceData,
 	var diags diag.Diagnostics
 	var err error
 
-	if d.HasChange("roles") {
-		oldState, _ := d.GetChange("roles")
-		d.Set("roles", oldState)
-		return diag.Errorf("Not supported to change Roles")
-	}
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Path:     "/data/Cisco-IOS-XE-native:native/ip/dhcp",
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    "/data/Cisco-IOS-XE-native:native/ip/dhcp",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	devices := getDevices(d)
+	if err = c.removeHosts(ctx, d, devices, "/data/Cisco-IOS-XE-native:native/ip/dhcp"); err != nil {
+		setDevices(d, devices)
+		return diag.FromErr(err)
 	}
 
 	roles := d.Get("roles").([]interface{})
@@ -117,17 +159,22 @@ func resourceCiscoNativeDhcpUpdate(ctx context.Context, d *schema.ResourceData,
 		if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 			svc.Payload = string(b)
 		}
-		if svc.Provider.Get("debug").(bool) {
-			debugJson(fmt.Sprintf("dhcp_%v", svc.Role), svc.Payload)
+		svc.CLI = data.CLI()
+		if svc.Config.Debug {
+			debugPayload(fmt.Sprintf("dhcp_%v", svc.Role), svc)
 		}
 
-		_, err = iosxe.MultiSession(svc)
+		err = c.applyRole(svc, devices)
 		if err != nil {
+			setDevices(d, devices)
 			return diag.FromErr(err)
 		}
 	}
 
 	d.SetId(fmt.Sprintf("dhcp_global_%v", svc.Role))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
@@ -138,16 +185,18 @@ func resourceCiscoNativeDhcpDelete(ctx context.Context, d *schema.ResourceData,
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "DELETE",
-		Path:     "/data/Cisco-IOS-XE-native:native/ip/dhcp",
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "DELETE",
+		Path:    "/data/Cisco-IOS-XE-native:native/ip/dhcp",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 
+	devices := getDevices(d)
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
-		_, err = iosxe.MultiSession(svc)
+		err = c.deleteRole(svc, devices)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -157,6 +206,21 @@ func resourceCiscoNativeDhcpDelete(ctx context.Context, d *schema.ResourceData,
 	return diags
 }
 
+func resourceCiscoNativeDhcpImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco DHCP IMPORT")
+	c, _ := meta.(*providerClient)
+	parts, err := importID(d.Id(), "<roles>")
+	if err != nil {
+		return nil, err
+	}
+	if err = c.importRoles(d, parts[0]); err != nil {
+		return nil, err
+	}
+	roles := strings.Split(parts[0], ",")
+	d.SetId(fmt.Sprintf("dhcp_global_%v", roles[len(roles)-1]))
+	return importRead(ctx, d, meta, resourceCiscoNativeDhcpRead)
+}
+
 func (*providerClient) resourceCiscoNativeDhcpData(d *schema.ResourceData) *dhcp.CiscoIOSXENativeDhcps {
 	var n interface{}
 	data := &dhcp.CiscoIOSXENativeDhcps{}
@@ -184,3 +248,14 @@ func (*providerClient) resourceCiscoNativeDhcpData(d *schema.ResourceData) *dhcp
 	return data
 
 }
+
+func (*providerClient) resourceCiscoNativeDhcpState(d *schema.ResourceData, data *dhcp.CiscoIOSXENativeDhcps) map[string]interface{} {
+	vlans := []interface{}{}
+	for _, v := range data.CiscoIOSXENativeDhcp.CiscoIOSXEDhcpSnoopingConf.Snooping.VlanList {
+		vlans = append(vlans, vlanRange(v.ID)...)
+	}
+	return map[string]interface{}{
+		"vlans":     orderLike(d.Get("vlans").([]interface{}), vlans),
+		"relay_vpn": data.CiscoIOSXENativeDhcp.CiscoIOSXEDhcpRelay.Information.Option.Vpn != nil,
+	}
+}
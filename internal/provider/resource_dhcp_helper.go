@@ -3,8 +3,10 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -17,10 +19,14 @@ import (
 func resourceCiscoNativeDhcpHelper() *schema.Resource {
 	return &schema.Resource{
 		Description:   "Cisco DHCP IP Helper",
-		CreateContext: resourceCiscoNativeDhcpHelperCreate,
+		CreateContext: transactional(resourceCiscoNativeDhcpHelperCreate),
 		ReadContext:   resourceCiscoNativeDhcpHelperRead,
-		UpdateContext: resourceCiscoNativeDhcpHelperUpdate,
-		DeleteContext: resourceCiscoNativeDhcpHelperDelete,
+		UpdateContext: transactional(resourceCiscoNativeDhcpHelperUpdate),
+		DeleteContext: transactional(resourceCiscoNativeDhcpHelperDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativeDhcpHelperImport,
+		},
+		CustomizeDiff: devicesCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"roles": {
 				Type:     schema.TypeList,
@@ -46,12 +52,15 @@ func resourceCiscoNativeDhcpHelper() *schema.Resource {
 				Type:         schema.TypeString,
 				Optional:     true,
 				Default:      "global",
-				ValidateFunc: validation.StringIsNotWhiteSpace,
+				ValidateFunc: validation.StringInSlice([]string{"global"}, false),
 			},
 			"source_interface": {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"devices":           devicesSchema(),
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
 		},
 	}
 }
@@ -63,12 +72,14 @@ func resourceCiscoNativeDhcpHelperCreate(ctx context.Context, d *schema.Resource
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Path:     fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v", d.Get("svi_id").(int)),
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v", d.Get("svi_id").(int)),
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 
+	devices := getDevices(d)
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
@@ -81,24 +92,53 @@ func resourceCiscoNativeDhcpHelperCreate(ctx context.Context, d *schema.Resource
 			if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 				svc.Payload = string(b)
 			}
-			if svc.Provider.Get("debug").(bool) {
-				debugJson(fmt.Sprintf("dhcp_helper_%v", svc.Role), svc.Payload)
+			svc.CLI = data.CLI()
+			if svc.Config.Debug {
+				debugPayload(fmt.Sprintf("dhcp_helper_%v", svc.Role), svc)
 			}
 
-			_, err = iosxe.MultiSession(svc)
+			err = c.applyRole(svc, devices)
 			if err != nil {
+				setDevices(d, devices)
 				return diag.FromErr(err)
 			}
 		}
 	}
 
 	d.SetId(fmt.Sprintf("dhcp_helper_%v", svc.Role))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
 func resourceCiscoNativeDhcpHelperRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	log.Println("[DEBUG] TODO")
+	log.Println("[DEBUG] Cisco DHCP IP HELPER READ")
 	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	bodies, err := c.readRoles(ctx, d, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v", d.Get("svi_id").(int)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	states := make(map[string]map[string]interface{})
+	for host, body := range bodies {
+		data := &svi.CiscoIOSXENativeVlanDhcpHelper{}
+		if err = unmarshalBody(body, data); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		if len(data.CiscoIOSXENativeVlan) == 0 || len(data.CiscoIOSXENativeVlan[0].IP.HelperAddress) == 0 {
+			log.Printf("[DEBUG] DHCP IP Helper on SVI %v not found on: %v\n", d.Get("svi_id").(int), host)
+			d.SetId("")
+			return diags
+		}
+		states[host] = c.resourceCiscoNativeDhcpHelperState(d, data)
+	}
+
+	if err = setDevicesDriftState(d, states); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
@@ -112,17 +152,27 @@ func resourceCiscoNativeDhcpHelperUpdate(ctx context.Context, d *schema.Resource
 		d.Set("svi", oldState)
 		return diag.Errorf("Not supported to change SVI")
 	}
-	if d.HasChange("roles") {
-		oldState, _ := d.GetChange("roles")
-		d.Set("roles", oldState)
-		return diag.Errorf("Not supported to change Roles")
-	}
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Path:     fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v", d.Get("svi_id").(int)),
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v", d.Get("svi_id").(int)),
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	devices := getDevices(d)
+	var paths []string
+	helpers, _ := d.GetChange("ipv4_helper")
+	for _, helper := range helpers.([]interface{}) {
+		paths = append(paths, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v/ip/helper-address=%v", d.Get("svi_id").(int), helper.(string)))
+	}
+	if sourceInterface, _ := d.GetChange("source_interface"); sourceInterface.(string) != "" {
+		paths = append(paths, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v/ip/dhcp/relay", d.Get("svi_id").(int)))
+	}
+	if err = c.removeHosts(ctx, d, devices, paths...); err != nil {
+		setDevices(d, devices)
+		return diag.FromErr(err)
 	}
 
 	roles := d.Get("roles").([]interface{})
@@ -137,18 +187,23 @@ func resourceCiscoNativeDhcpHelperUpdate(ctx context.Context, d *schema.Resource
 			if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 				svc.Payload = string(b)
 			}
-			if svc.Provider.Get("debug").(bool) {
-				debugJson(fmt.Sprintf("dhcp_helper_%v", svc.Role), svc.Payload)
+			svc.CLI = data.CLI()
+			if svc.Config.Debug {
+				debugPayload(fmt.Sprintf("dhcp_helper_%v", svc.Role), svc)
 			}
 
-			_, err = iosxe.MultiSession(svc)
+			err = c.applyRole(svc, devices)
 			if err != nil {
+				setDevices(d, devices)
 				return diag.FromErr(err)
 			}
 		}
 	}
 
 	d.SetId(fmt.Sprintf("dhcp_helper_%v", svc.Role))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
@@ -159,12 +214,14 @@ func resourceCiscoNativeDhcpHelperDelete(ctx context.Context, d *schema.Resource
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "DELETE",
-		Path:     fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v/ip?fields=helper-address", d.Get("svi_id").(int)),
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "DELETE",
+		Path:    fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v/ip?fields=helper-address", d.Get("svi_id").(int)),
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 
+	devices := getDevices(d)
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
@@ -172,7 +229,7 @@ func resourceCiscoNativeDhcpHelperDelete(ctx context.Context, d *schema.Resource
 			helpers := d.Get("ipv4_helper").([]interface{})
 			for _, helper := range helpers {
 				svc.Path = fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v/ip/helper-address=%v", d.Get("svi_id").(int), helper.(string))
-				_, err = iosxe.MultiSession(svc)
+				err = c.deleteRole(svc, devices)
 				if err != nil {
 					return diag.FromErr(err)
 				}
@@ -180,7 +237,7 @@ func resourceCiscoNativeDhcpHelperDelete(ctx context.Context, d *schema.Resource
 		}
 		if _, ok := d.GetOk("source_interface"); ok {
 			svc.Path = fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v/ip/dhcp/relay", d.Get("svi_id").(int))
-			_, err = iosxe.MultiSession(svc)
+			err = c.deleteRole(svc, devices)
 			if err != nil {
 				return diag.FromErr(err)
 			}
@@ -191,6 +248,26 @@ func resourceCiscoNativeDhcpHelperDelete(ctx context.Context, d *schema.Resource
 	return diags
 }
 
+func resourceCiscoNativeDhcpHelperImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco DHCP IP HELPER IMPORT")
+	c, _ := meta.(*providerClient)
+	parts, err := importID(d.Id(), "<roles>:<svi_id>")
+	if err != nil {
+		return nil, err
+	}
+	id, err := importInt(parts[1], "svi_id")
+	if err != nil {
+		return nil, err
+	}
+	if err = c.importRoles(d, parts[0]); err != nil {
+		return nil, err
+	}
+	d.Set("svi_id", id)
+	roles := strings.Split(parts[0], ",")
+	d.SetId(fmt.Sprintf("dhcp_helper_%v", roles[len(roles)-1]))
+	return importRead(ctx, d, meta, resourceCiscoNativeDhcpHelperRead)
+}
+
 func (*providerClient) resourceCiscoNativeDhcpHelperCreateIpv4Data(d *schema.ResourceData) *svi.CiscoIOSXENativeVlanDhcpHelper {
 	data := &svi.CiscoIOSXENativeVlanDhcpHelper{}
 
@@ -207,8 +284,6 @@ func (*providerClient) resourceCiscoNativeDhcpHelperCreateIpv4Data(d *schema.Res
 		}
 		if d.Get("vrf").(string) == "global" {
 			h.Global = null()
-		} else {
-			log.Panicln("[PANIC] Only global supported for ip helper")
 		}
 		dhcpData.IP.HelperAddress = append(dhcpData.IP.HelperAddress, *h)
 	}
@@ -216,3 +291,15 @@ func (*providerClient) resourceCiscoNativeDhcpHelperCreateIpv4Data(d *schema.Res
 	return data
 
 }
+
+func (*providerClient) resourceCiscoNativeDhcpHelperState(d *schema.ResourceData, data *svi.CiscoIOSXENativeVlanDhcpHelper) map[string]interface{} {
+	dhcpData := data.CiscoIOSXENativeVlan[0]
+	helpers := []interface{}{}
+	for _, h := range dhcpData.IP.HelperAddress {
+		helpers = append(helpers, h.Address)
+	}
+	return map[string]interface{}{
+		"ipv4_helper":      orderLike(d.Get("ipv4_helper").([]interface{}), helpers),
+		"source_interface": dhcpData.IP.Dhcp.CiscoIOSXEDhcpRelay.SourceInterface,
+	}
+}
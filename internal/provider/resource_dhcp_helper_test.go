@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnDhcpHelper(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	path := "Cisco-IOS-XE-native:native/interface/Vlan=101/ip"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnDhcpHelperConfig(f, `"100.127.255.2"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_dhcp_helper.test", "ipv4_helper.0", "100.127.255.2"),
+					testAccCheckDevices(f.Leafs, path, `"100.127.255.2"`, `"global"`),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnDhcpHelperConfig(f, `"100.127.255.2", "100.127.255.3"`),
+				Check:  testAccCheckDevices(f.Leafs, path, `"100.127.255.2"`, `"100.127.255.3"`),
+			},
+		},
+	})
+}
+
+func testAccCiscoEvpnDhcpHelperConfig(f *testAccFabric, helpers string) string {
+	return f.Config() + f.LoopbacksConfig(200) + fmt.Sprintf(`
+resource "ciscoevpn_svi" "test" {
+  roles        = ["leafs"]
+  svi_id       = 101
+  ipv4_address = "100.119.101.1"
+  ipv4_mask    = "255.255.255.0"
+}
+
+resource "ciscoevpn_dhcp_helper" "test" {
+  depends_on       = [ciscoevpn_loopback.leaf0_200, ciscoevpn_loopback.leaf1_200]
+  roles            = ["leafs"]
+  svi_id           = ciscoevpn_svi.test.svi_id
+  ipv4_helper      = [%v]
+  vrf              = "global"
+  source_interface = ciscoevpn_loopback.leaf0_200.interface_name
+}
+`, helpers)
+}
@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnDhcp(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	path := "Cisco-IOS-XE-native:native/ip/dhcp"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnDhcpConfig(f, "101"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_dhcp.test", "vlans.#", "1"),
+					testAccCheckDevices(f.Leafs, path, `"vpn"`, `101`),
+					testAccCheckDevicesRemoved(f.Spines, path),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnDhcpConfig(f, "101, 102"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_dhcp.test", "vlans.#", "2"),
+					testAccCheckDevices(f.Leafs, path, `101`, `102`),
+				),
+			},
+		},
+	})
+}
+
+func testAccCiscoEvpnDhcpConfig(f *testAccFabric, vlans string) string {
+	return f.Config() + fmt.Sprintf(`
+resource "ciscoevpn_dhcp" "test" {
+  roles     = ["leafs"]
+  vlans     = [%v]
+  relay_vpn = true
+}
+`, vlans)
+}
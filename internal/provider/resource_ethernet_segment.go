@@ -0,0 +1,445 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/ethernet_segment"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/iosxe"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
+)
+
+var esiRe = regexp.MustCompile(`^([0-9A-Fa-f]{2}\.){8}[0-9A-Fa-f]{2}$`)
+var systemMacRe = regexp.MustCompile(`^([0-9A-Fa-f]{4}\.){2}[0-9A-Fa-f]{4}$`)
+
+func resourceCiscoNativeEthernetSegment() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Cisco EVPN multihoming ethernet segment, configured on a pair of leafs and bound to a Port-channel on each of them",
+		CreateContext: resourceCiscoNativeEthernetSegmentCreate,
+		ReadContext:   resourceCiscoNativeEthernetSegmentRead,
+		UpdateContext: resourceCiscoNativeEthernetSegmentUpdate,
+		DeleteContext: resourceCiscoNativeEthernetSegmentDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativeEthernetSegmentImport,
+		},
+		CustomizeDiff: resourceCiscoNativeEthernetSegmentCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"member": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 2,
+				MaxItems: 2,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ForceNew:     true,
+							ValidateFunc: validation.StringIsNotWhiteSpace,
+						},
+						"port_channel": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							ForceNew:     true,
+							ValidateFunc: validation.IntBetween(1, 128),
+						},
+						"segment": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							ForceNew:     true,
+							ValidateFunc: validation.IntBetween(1, 65535),
+						},
+					},
+				},
+			},
+			"identifier_type": {
+				Type:         schema.TypeInt,
+				Default:      0,
+				Optional:     true,
+				ValidateFunc: validation.IntInSlice([]int{0, 3}),
+			},
+			"esi": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringMatch(esiRe, "expected 9 octets, e.g. 00.00.00.00.00.00.00.00.01"),
+			},
+			"system_mac": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "System MAC of identifier type 3, the segment of the member is the local discriminator",
+				ValidateFunc: validation.StringMatch(systemMacRe, "expected a MAC address, e.g. 0000.0000.0001"),
+			},
+			"redundancy": {
+				Type:         schema.TypeString,
+				Default:      "all-active",
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"all-active", "single-active"}, false),
+			},
+			"esi_value": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The 10 octet ESI advertised by both members",
+			},
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
+		},
+	}
+}
+
+// resourceCiscoNativeEthernetSegmentCustomizeDiff checks the identifier and that both members advertise the same ESI
+func resourceCiscoNativeEthernetSegmentCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	for _, attr := range []string{"member", "identifier_type", "esi", "system_mac"} {
+		if !d.NewValueKnown(attr) {
+			return nil
+		}
+	}
+	esi, mac := d.Get("esi").(string), d.Get("system_mac").(string)
+	switch d.Get("identifier_type").(int) {
+	case 0:
+		if esi == "" || mac != "" {
+			return fmt.Errorf("identifier_type 0 requires esi and doesn't support system_mac")
+		}
+	case 3:
+		if mac == "" || esi != "" {
+			return fmt.Errorf("identifier_type 3 requires system_mac and doesn't support esi")
+		}
+	}
+
+	members := d.Get("member").([]interface{})
+	if len(members) != 2 {
+		return nil
+	}
+	hosts := []string{}
+	segments := []int{}
+	for _, v := range members {
+		m := v.(map[string]interface{})
+		hosts = append(hosts, m["host"].(string))
+		segments = append(segments, m["segment"].(int))
+	}
+	if hosts[0] == hosts[1] {
+		return fmt.Errorf("both members are on %v, an ethernet segment is shared by two hosts", hosts[0])
+	}
+	identifierType := d.Get("identifier_type").(int)
+	value := ethernetSegmentESI(identifierType, esi, mac, segments[0])
+	// the ESI of type 0 is configured, type 3 ends with the segment of the member as local discriminator
+	if identifierType == 3 {
+		if other := ethernetSegmentESI(identifierType, esi, mac, segments[1]); other != value {
+			return fmt.Errorf("ESI mismatch, %v advertises %v and %v advertises %v, the segment is the local discriminator of a type 3 ESI", hosts[0], value, hosts[1], other)
+		}
+	}
+	if d.Get("esi_value").(string) != value {
+		return d.SetNew("esi_value", value)
+	}
+	return nil
+}
+
+func resourceCiscoNativeEthernetSegmentCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco Ethernet Segment CREATE")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	applied := []int{}
+	for i := range d.Get("member").([]interface{}) {
+		if err := c.resourceCiscoNativeEthernetSegmentApply(ctx, d, i); err != nil {
+			// an ethernet segment on a single leaf isn't multihomed, the members applied are reverted
+			for _, j := range applied {
+				c.resourceCiscoNativeEthernetSegmentRemove(ctx, d, j)
+			}
+			return diag.FromErr(err)
+		}
+		applied = append(applied, i)
+	}
+
+	d.SetId(resourceCiscoNativeEthernetSegmentID(d))
+	if err := c.resourceCiscoNativeEthernetSegmentPreview(d); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativeEthernetSegmentRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco Ethernet Segment READ")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	states := make(map[string]map[string]interface{})
+	for i, v := range d.Get("member").([]interface{}) {
+		m := v.(map[string]interface{})
+		svc := &service.Client{
+			Context: ctx,
+			Method:  "GET",
+			Path:    resourceCiscoNativeEthernetSegmentPath(d, i),
+			Config:  c.Config,
+			Device:  m["host"].(string),
+		}
+		body, err := iosxe.SingleSession(svc)
+		data := &ethernet_segment.CiscoIOSXEL2VpnEthernetSegments{}
+		if err == nil {
+			err = unmarshalBody(body, data)
+		}
+		if err == nil {
+			svc.Path = resourceCiscoNativeEthernetSegmentBindingPath(d, i)
+			body, err = iosxe.SingleSession(svc)
+		}
+		binding := &ethernet_segment.CiscoIOSXENativePortChannelEvpnInterface{}
+		if err == nil {
+			err = unmarshalBody(body, binding)
+		}
+		if errors.Is(err, iosxe.ErrNotFound) {
+			d.SetId("")
+			return diags
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if len(data.EthernetSegment) == 0 || binding.Evpn.EthernetSegment != m["segment"].(int) {
+			log.Printf("[DEBUG] Ethernet segment not bound on: %v\n", m["host"].(string))
+			d.SetId("")
+			return diags
+		}
+		states[m["host"].(string)] = resourceCiscoNativeEthernetSegmentState(&data.EthernetSegment[0])
+	}
+
+	if err := setDriftState(d, states); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := c.resourceCiscoNativeEthernetSegmentPreview(d); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativeEthernetSegmentUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco Ethernet Segment UPDATE")
+	var diags diag.Diagnostics
+
+	// PATCH merges, the identifier and redundancy are deleted before the new config is applied
+	leaves := []string{}
+	if d.HasChanges("identifier_type", "esi", "system_mac") {
+		leaves = append(leaves, "/identifier")
+	}
+	if d.HasChange("redundancy") {
+		leaves = append(leaves, "/redundancy")
+	}
+
+	c, _ := meta.(*providerClient)
+	for i, v := range d.Get("member").([]interface{}) {
+		svc := &service.Client{
+			Context: ctx,
+			Method:  "DELETE",
+			Config:  c.Config,
+			Device:  v.(map[string]interface{})["host"].(string),
+		}
+		for _, leaf := range leaves {
+			svc.Path = resourceCiscoNativeEthernetSegmentPath(d, i) + leaf
+			if _, err := iosxe.SingleSession(svc); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+				return diag.FromErr(err)
+			}
+		}
+		if err := c.resourceCiscoNativeEthernetSegmentApply(ctx, d, i); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if err := c.resourceCiscoNativeEthernetSegmentPreview(d); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativeEthernetSegmentDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco Ethernet Segment DELETE")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	for i := range d.Get("member").([]interface{}) {
+		if err := c.resourceCiscoNativeEthernetSegmentRemove(ctx, d, i); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId("")
+	return diags
+}
+
+func resourceCiscoNativeEthernetSegmentImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco Ethernet Segment IMPORT")
+	format := "<host>:<port_channel>:<segment>,<host>:<port_channel>:<segment>"
+	sides := strings.Split(d.Id(), ",")
+	if len(sides) != 2 {
+		return nil, fmt.Errorf("unexpected import ID %q, expected %v", d.Id(), format)
+	}
+	members := []interface{}{}
+	for _, side := range sides {
+		parts, err := importHostID(side, "<host>:<port_channel>:<segment>")
+		if err != nil {
+			return nil, err
+		}
+		portChannel, err := importInt(parts[1], "port_channel")
+		if err != nil {
+			return nil, err
+		}
+		segment, err := importInt(parts[2], "segment")
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, map[string]interface{}{
+			"host":         parts[0],
+			"port_channel": portChannel,
+			"segment":      segment,
+		})
+	}
+	d.Set("member", members)
+	d.SetId(resourceCiscoNativeEthernetSegmentID(d))
+	return importRead(ctx, d, meta, resourceCiscoNativeEthernetSegmentRead)
+}
+
+// resourceCiscoNativeEthernetSegmentID is the Port-channel and segment of both members, e.g. 10.0.0.11:1:1,10.0.0.12:1:1
+func resourceCiscoNativeEthernetSegmentID(d *schema.ResourceData) string {
+	ids := []string{}
+	for _, v := range d.Get("member").([]interface{}) {
+		m := v.(map[string]interface{})
+		ids = append(ids, fmt.Sprintf("%v:%v:%v", m["host"], m["port_channel"], m["segment"]))
+	}
+	return strings.Join(ids, ",")
+}
+
+func resourceCiscoNativeEthernetSegmentMember(d *schema.ResourceData, i int) map[string]interface{} {
+	return d.Get("member").([]interface{})[i].(map[string]interface{})
+}
+
+func resourceCiscoNativeEthernetSegmentPath(d *schema.ResourceData, i int) string {
+	m := resourceCiscoNativeEthernetSegmentMember(d, i)
+	return fmt.Sprintf("/data/Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn-ethernet-segment/evpn/ethernet-segment=%v", m["segment"])
+}
+
+func resourceCiscoNativeEthernetSegmentBindingPath(d *schema.ResourceData, i int) string {
+	m := resourceCiscoNativeEthernetSegmentMember(d, i)
+	return interfacePath(fmt.Sprintf("Port-channel%v", m["port_channel"])) + "/Cisco-IOS-XE-l2vpn:evpn"
+}
+
+// ethernetSegmentESI returns the 10 octet ESI, type 3 is the system MAC followed by the segment as local discriminator
+func ethernetSegmentESI(identifierType int, esi string, systemMac string, segment int) string {
+	if identifierType == 3 {
+		mac := strings.ReplaceAll(systemMac, ".", "")
+		octets := []string{"03"}
+		for i := 0; i+2 <= len(mac); i += 2 {
+			octets = append(octets, mac[i:i+2])
+		}
+		octets = append(octets, fmt.Sprintf("%02x", segment>>16&0xff), fmt.Sprintf("%02x", segment>>8&0xff), fmt.Sprintf("%02x", segment&0xff))
+		return strings.ToLower(strings.Join(octets, "."))
+	}
+	return strings.ToLower(fmt.Sprintf("%02x.%v", identifierType, esi))
+}
+
+// resourceCiscoNativeEthernetSegmentData returns the ethernet segment of a member and its Port-channel binding
+func (*providerClient) resourceCiscoNativeEthernetSegmentData(d *schema.ResourceData, i int) []payloadStep {
+	m := resourceCiscoNativeEthernetSegmentMember(d, i)
+	es := ethernet_segment.CiscoIOSXEL2VpnEthernetSegment{
+		EsValue: m["segment"].(int),
+		Identifier: ethernet_segment.CiscoIOSXEL2VpnEthernetSegmentIdentifier{
+			Type:      d.Get("identifier_type").(int),
+			Esi:       d.Get("esi").(string),
+			SystemMac: d.Get("system_mac").(string),
+		},
+	}
+	if d.Get("redundancy").(string) == "single-active" {
+		es.Redundancy.SingleActive = null()
+	} else {
+		es.Redundancy.AllActive = null()
+	}
+	binding := ethernet_segment.CiscoIOSXENativePortChannelEvpnInterface{Name: m["port_channel"].(int)}
+	binding.Evpn.EthernetSegment = m["segment"].(int)
+
+	return []payloadStep{
+		newPayloadStep(
+			"/data/Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn-ethernet-segment/evpn/ethernet-segment",
+			&ethernet_segment.CiscoIOSXEL2VpnEthernetSegments{EthernetSegment: []ethernet_segment.CiscoIOSXEL2VpnEthernetSegment{es}},
+		),
+		newPayloadStep(
+			interfacePath(fmt.Sprintf("Port-channel%v", m["port_channel"])),
+			&ethernet_segment.CiscoIOSXENativePortChannelEvpn{PortChannel: []ethernet_segment.CiscoIOSXENativePortChannelEvpnInterface{binding}},
+		),
+	}
+}
+
+// resourceCiscoNativeEthernetSegmentApply PATCHes the ethernet segment and then binds it to the Port-channel of a member
+func (c *providerClient) resourceCiscoNativeEthernetSegmentApply(ctx context.Context, d *schema.ResourceData, i int) error {
+	steps := c.resourceCiscoNativeEthernetSegmentData(d, i)
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "PATCH",
+		Config:  c.Config,
+		Device:  resourceCiscoNativeEthernetSegmentMember(d, i)["host"].(string),
+	}
+	if svc.Config.Debug {
+		svc.Payload, svc.CLI = stepsPayload(steps)
+		debugPayload(fmt.Sprintf("ethernet_segment_%v_%v", svc.Device, resourceCiscoNativeEthernetSegmentMember(d, i)["segment"]), svc)
+	}
+	for _, step := range steps {
+		svc.Path = step.Path
+		svc.Payload = step.Payload
+		svc.CLI = step.CLI
+		if _, err := iosxe.SingleSession(svc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resourceCiscoNativeEthernetSegmentRemove unbinds the Port-channel of the member and deletes the ethernet segment
+func (c *providerClient) resourceCiscoNativeEthernetSegmentRemove(ctx context.Context, d *schema.ResourceData, i int) error {
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "DELETE",
+		Config:  c.Config,
+		Device:  resourceCiscoNativeEthernetSegmentMember(d, i)["host"].(string),
+	}
+	for _, path := range []string{resourceCiscoNativeEthernetSegmentBindingPath(d, i), resourceCiscoNativeEthernetSegmentPath(d, i)} {
+		svc.Path = path
+		if _, err := iosxe.SingleSession(svc); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// resourceCiscoNativeEthernetSegmentPreview sets the CLI (and in dry run mode the payloads) of both members
+func (c *providerClient) resourceCiscoNativeEthernetSegmentPreview(d *schema.ResourceData) error {
+	cli := make(map[string]interface{})
+	payloads := make(map[string]interface{})
+	for i := range d.Get("member").([]interface{}) {
+		host := resourceCiscoNativeEthernetSegmentMember(d, i)["host"].(string)
+		payload, lines := stepsPayload(c.resourceCiscoNativeEthernetSegmentData(d, i))
+		cli[host] = strings.Join(lines, "\n")
+		payloads[host] = payload
+	}
+	if err := d.Set("cli_preview", cli); err != nil {
+		return err
+	}
+	if !c.Config.DryRun {
+		return d.Set("rendered_payloads", map[string]interface{}{})
+	}
+	return d.Set("rendered_payloads", payloads)
+}
+
+func resourceCiscoNativeEthernetSegmentState(es *ethernet_segment.CiscoIOSXEL2VpnEthernetSegment) map[string]interface{} {
+	redundancy := "all-active"
+	if es.Redundancy.SingleActive != nil {
+		redundancy = "single-active"
+	}
+	return map[string]interface{}{
+		"identifier_type": es.Identifier.Type,
+		"esi":             es.Identifier.Esi,
+		"system_mac":      es.Identifier.SystemMac,
+		"redundancy":      redundancy,
+		"esi_value":       ethernetSegmentESI(es.Identifier.Type, es.Identifier.Esi, es.Identifier.SystemMac, es.EsValue),
+	}
+}
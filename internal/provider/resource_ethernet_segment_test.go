@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnEthernetSegment(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	es := "Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn-ethernet-segment/evpn/ethernet-segment=1"
+	po := "Cisco-IOS-XE-native:native/interface/Port-channel=10"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckDevicesRemoved(f.Leafs, es),
+			testAccCheckDevicesRemoved(f.Leafs, po+"/Cisco-IOS-XE-l2vpn:evpn"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCiscoEvpnEthernetSegmentConfig(f, `identifier_type = 3`, `system_mac = "0000.0000.0001"`, 2),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ESI mismatch"),
+			},
+			{
+				// the ESI of type 0 doesn't depend on the segment of the member
+				Config:             testAccCiscoEvpnEthernetSegmentConfig(f, `identifier_type = 0`, `esi = "00.00.00.00.00.00.00.00.01"`, 2),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccCiscoEvpnEthernetSegmentConfig(f, `identifier_type = 0`, `esi = "00.00.00.00.00.00.00.00.01"`, 1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDevices(f.Leafs, es, `"type":0`, `"esi":"00.00.00.00.00.00.00.00.01"`, `"all-active"`),
+					testAccCheckDevices(f.Leafs, po, `"ethernet-segment":1`),
+					resource.TestCheckResourceAttr("ciscoevpn_ethernet_segment.test", "esi_value", "00.00.00.00.00.00.00.00.00.01"),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnEthernetSegmentConfig(f, `identifier_type = 3`, `system_mac = "0000.0000.0001"`+"\n  redundancy = \"single-active\"", 1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDevicesRemoved(f.Leafs, es+"/identifier/esi"),
+					testAccCheckDevicesRemoved(f.Leafs, es+"/redundancy/all-active"),
+					testAccCheckDevices(f.Leafs, es, `"type":3`, `"system-mac":"0000.0000.0001"`, `"single-active"`),
+					resource.TestCheckResourceAttr("ciscoevpn_ethernet_segment.test", "esi_value", "03.00.00.00.00.00.01.00.00.01"),
+				),
+			},
+			{
+				ResourceName:      "ciscoevpn_ethernet_segment.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%v:10:1,%v:10:1", f.Leafs[0].Host(), f.Leafs[1].Host()),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCiscoEvpnEthernetSegmentConfig(f *testAccFabric, identifier string, value string, segment int) string {
+	return f.Config() + fmt.Sprintf(`
+resource "ciscoevpn_ethernet_segment" "test" {
+  %v
+  %v
+
+  member {
+    host         = %q
+    port_channel = 10
+    segment      = 1
+  }
+  member {
+    host         = %q
+    port_channel = 10
+    segment      = %v
+  }
+}
+`, identifier, value, f.Leafs[0].Host(), f.Leafs[1].Host(), segment)
+}
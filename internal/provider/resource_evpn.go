@@ -3,9 +3,9 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -19,10 +19,14 @@ func resourceCiscoNativeL2VpnEvpn() *schema.Resource {
 	return &schema.Resource{
 		Description: "Cisco L2VPN EVPN",
 
-		CreateContext: resourceCiscoNativeL2VpnEvpnCreate,
+		CreateContext: transactional(resourceCiscoNativeL2VpnEvpnCreate),
 		ReadContext:   resourceCiscoNativeL2VpnEvpnRead,
-		UpdateContext: resourceCiscoNativeL2VpnEvpnUpdate, //Todo Update
-		DeleteContext: resourceCiscoNativeL2VpnEvpnDelete,
+		UpdateContext: transactional(resourceCiscoNativeL2VpnEvpnUpdate), //Todo Update
+		DeleteContext: transactional(resourceCiscoNativeL2VpnEvpnDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativeL2VpnEvpnImport,
+		},
+		CustomizeDiff: devicesCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"roles": {
@@ -59,8 +63,10 @@ func resourceCiscoNativeL2VpnEvpn() *schema.Resource {
 				Optional: true,
 			},
 			"router_id": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateLoopback(),
+				DiffSuppressFunc: suppressLoopbackDiff,
 			},
 			"default_gateway": {
 				Type:     schema.TypeString,
@@ -77,6 +83,9 @@ func resourceCiscoNativeL2VpnEvpn() *schema.Resource {
 				Default:  "vni",
 				Optional: true,
 			},
+			"devices":           devicesSchema(),
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
 		},
 	}
 }
@@ -87,19 +96,25 @@ func resourceCiscoNativeL2VpnEvpnCreate(ctx context.Context, d *schema.ResourceD
 	var err error
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Path:     "/data/Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn",
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    "/data/Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 
 	id := loopbackId(d.Get("router_id").(string))
 	d.Set("router_id", id)
 
+	devices := getDevices(d)
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
-		data := c.resourceCiscoNativeL2VpnEvpnData(d)
+		data, dataDiags := c.resourceCiscoNativeL2VpnEvpnData(d, c.roleTarget(svc.Role))
+		if dataDiags.HasError() {
+			setDevices(d, devices)
+			return dataDiags
+		}
 		if data == nil {
 			return diag.Errorf("No data in yang model") // TODO refactor
 		}
@@ -107,12 +122,14 @@ func resourceCiscoNativeL2VpnEvpnCreate(ctx context.Context, d *schema.ResourceD
 		if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 			svc.Payload = string(b)
 		}
-		if svc.Provider.Get("debug").(bool) {
-			debugJson(fmt.Sprintf("evpn_loopback%v", d.Get("router_id").(string)), svc.Payload) // TODO
+		svc.CLI = data.CLI()
+		if svc.Config.Debug {
+			debugPayload(fmt.Sprintf("evpn_loopback%v", d.Get("router_id").(string)), svc) // TODO
 		}
 
-		_, err = iosxe.MultiSession(svc)
+		err = c.applyRole(svc, devices)
 		if err != nil {
+			setDevices(d, devices)
 			return diag.FromErr(err)
 		}
 	}
@@ -122,12 +139,39 @@ func resourceCiscoNativeL2VpnEvpnCreate(ctx context.Context, d *schema.ResourceD
 		return diag.FromErr(err)
 	}
 	d.SetId(fmt.Sprintf("l2vpn_evpn_%v", d.Get("router_id").(string)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
 func resourceCiscoNativeL2VpnEvpnRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	log.Println("[DEBUG] Cisco EVPN READ TODO")
+	log.Println("[DEBUG] Cisco L2VPN EVPN READ")
 	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	bodies, err := c.readRoles(ctx, d, "/data/Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	states := make(map[string]map[string]interface{})
+	for host, body := range bodies {
+		data := &evpn.CiscoIOSXEL2Evpn{}
+		if err = unmarshalBody(body, data); err != nil {
+			if errors.Is(err, iosxe.ErrNotFound) {
+				log.Println("[DEBUG] L2VPN EVPN not found on: ", host)
+				d.SetId("")
+				return diags
+			}
+			return diag.FromErr(err)
+		}
+		states[host] = c.resourceCiscoNativeL2VpnEvpnState(data)
+	}
+
+	if err = setDevicesDriftState(d, states); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
@@ -135,25 +179,31 @@ func resourceCiscoNativeL2VpnEvpnUpdate(ctx context.Context, d *schema.ResourceD
 	log.Println("[DEBUG] Cisco L2VPN EVPN UPDATE")
 	var diags diag.Diagnostics
 	var err error
-	if d.HasChange("roles") {
-		oldState, _ := d.GetChange("roles")
-		d.Set("roles", oldState)
-		return diag.Errorf("Not supported to change Roles")
-	}
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Path:     "/data/Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn",
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    "/data/Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 	id := loopbackId(d.Get("router_id").(string))
 	d.Set("router_id", id)
 
+	devices := getDevices(d)
+	if err = c.removeHosts(ctx, d, devices, "/data/Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn"); err != nil {
+		setDevices(d, devices)
+		return diag.FromErr(err)
+	}
+
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = fmt.Sprintf("%v", role)
-		data := c.resourceCiscoNativeL2VpnEvpnData(d)
+		data, dataDiags := c.resourceCiscoNativeL2VpnEvpnData(d, c.roleTarget(svc.Role))
+		if dataDiags.HasError() {
+			setDevices(d, devices)
+			return dataDiags
+		}
 		if data == nil {
 			return diag.Errorf("No data in yang model") // TODO refactor
 		}
@@ -161,12 +211,14 @@ func resourceCiscoNativeL2VpnEvpnUpdate(ctx context.Context, d *schema.ResourceD
 		if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 			svc.Payload = string(b)
 		}
-		if svc.Provider.Get("debug").(bool) {
-			debugJson(fmt.Sprintf("evpn_loopback%v", d.Get("router_id").(string)), svc.Payload) // TODO
+		svc.CLI = data.CLI()
+		if svc.Config.Debug {
+			debugPayload(fmt.Sprintf("evpn_loopback%v", d.Get("router_id").(string)), svc) // TODO
 		}
 
-		_, err = iosxe.MultiSession(svc)
+		err = c.applyRole(svc, devices)
 		if err != nil {
+			setDevices(d, devices)
 			return diag.FromErr(err)
 		}
 	}
@@ -176,6 +228,9 @@ func resourceCiscoNativeL2VpnEvpnUpdate(ctx context.Context, d *schema.ResourceD
 		return diag.FromErr(err)
 	}
 	d.SetId(fmt.Sprintf("l2vpn_evpn_%v", d.Get("router_id").(string)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
@@ -185,16 +240,18 @@ func resourceCiscoNativeL2VpnEvpnDelete(ctx context.Context, d *schema.ResourceD
 	var err error
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "DELETE",
-		Path:     "/data/Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn",
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "DELETE",
+		Path:    "/data/Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 
+	devices := getDevices(d)
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
-		_, err = iosxe.MultiSession(svc)
+		err = c.deleteRole(svc, devices)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -204,8 +261,27 @@ func resourceCiscoNativeL2VpnEvpnDelete(ctx context.Context, d *schema.ResourceD
 	return diags
 }
 
-func (*providerClient) resourceCiscoNativeL2VpnEvpnData(d *schema.ResourceData) *evpn.CiscoIOSXEL2Evpn {
-	var err error
+func resourceCiscoNativeL2VpnEvpnImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco L2VPN EVPN IMPORT")
+	c, _ := meta.(*providerClient)
+	parts, err := importID(d.Id(), "<roles>")
+	if err != nil {
+		return nil, err
+	}
+	if err = c.importRoles(d, parts[0]); err != nil {
+		return nil, err
+	}
+	d.SetId("l2vpn_evpn")
+	imported, err := importRead(ctx, d, meta, resourceCiscoNativeL2VpnEvpnRead)
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(fmt.Sprintf("l2vpn_evpn_%v", d.Get("router_id").(string)))
+	return imported, nil
+}
+
+func (*providerClient) resourceCiscoNativeL2VpnEvpnData(d *schema.ResourceData, target string) (*evpn.CiscoIOSXEL2Evpn, diag.Diagnostics) {
+	var diags diag.Diagnostics
 	empty := make([]int, 0) // TODO
 	data := &evpn.CiscoIOSXEL2Evpn{}
 	if d.Get("replication_type").(string) == "static" {
@@ -216,8 +292,8 @@ func (*providerClient) resourceCiscoNativeL2VpnEvpnData(d *schema.ResourceData)
 	data.CiscoIOSXEL2VpnEvpn.IP.Duplication.Limit = d.Get("ip_duplication_limit").(int)
 	data.CiscoIOSXEL2VpnEvpn.IP.Duplication.Time = d.Get("ip_duplication_time").(int)
 
-	if data.CiscoIOSXEL2VpnEvpn.RouterID.Interface.Loopback, err = strconv.Atoi(d.Get("router_id").(string)); err != nil {
-		log.Panicln("[PANIC] Can't find Loopback ID", err)
+	if data.CiscoIOSXEL2VpnEvpn.RouterID.Interface.Loopback, diags = loopbackNumber(d, "router_id", target); diags.HasError() {
+		return nil, diags
 	}
 	if d.Get("default_gateway").(string) == "advertise" {
 		data.CiscoIOSXEL2VpnEvpn.DefaultGateway.Advertise = empty
@@ -229,5 +305,30 @@ func (*providerClient) resourceCiscoNativeL2VpnEvpnData(d *schema.ResourceData)
 		data.CiscoIOSXEL2VpnEvpn.RouteTarget.Auto.Vni = empty
 	}
 
-	return data
+	return data, nil
+}
+
+func (*providerClient) resourceCiscoNativeL2VpnEvpnState(data *evpn.CiscoIOSXEL2Evpn) map[string]interface{} {
+	evpnData := data.CiscoIOSXEL2VpnEvpn
+	state := map[string]interface{}{
+		"replication_type":      "",
+		"mac_duplication_limit": evpnData.Mac.Duplication.Limit,
+		"mac_duplication_time":  evpnData.Mac.Duplication.Time,
+		"ip_duplication_limit":  evpnData.IP.Duplication.Limit,
+		"ip_duplication_time":   evpnData.IP.Duplication.Time,
+		"router_id":             fmt.Sprintf("Loopback%v", evpnData.RouterID.Interface.Loopback),
+		"default_gateway":       "",
+		"logging_peer_state":    evpnData.Logging.Peer.State != nil,
+		"route_target_auto":     "",
+	}
+	if evpnData.ReplicationType.Static != nil {
+		state["replication_type"] = "static"
+	}
+	if evpnData.DefaultGateway.Advertise != nil {
+		state["default_gateway"] = "advertise"
+	}
+	if evpnData.RouteTarget.Auto.Vni != nil {
+		state["route_target_auto"] = "vni"
+	}
+	return state
 }
@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 
@@ -17,10 +18,14 @@ import (
 func resourceCiscoNativeEvpnInstance() *schema.Resource {
 	return &schema.Resource{
 		Description:   "Cisco EVPN Instance",
-		CreateContext: resourceCiscoNativeEvpnInstanceCreate,
+		CreateContext: transactional(resourceCiscoNativeEvpnInstanceCreate),
 		ReadContext:   resourceCiscoNativeEvpnInstanceRead,
-		UpdateContext: resourceCiscoNativeEvpnInstanceUpdate,
-		DeleteContext: resourceCiscoNativeEvpnInstanceDelete,
+		UpdateContext: transactional(resourceCiscoNativeEvpnInstanceUpdate),
+		DeleteContext: transactional(resourceCiscoNativeEvpnInstanceDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativeEvpnInstanceImport,
+		},
+		CustomizeDiff: devicesCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"roles": {
 				Type:     schema.TypeList,
@@ -48,7 +53,7 @@ func resourceCiscoNativeEvpnInstance() *schema.Resource {
 				Type:         schema.TypeString,
 				Default:      "static",
 				Optional:     true,
-				ValidateFunc: validation.StringIsNotWhiteSpace,
+				ValidateFunc: validation.StringInSlice([]string{"static", "ingress"}, false),
 			},
 			"rd": {
 				Type:         schema.TypeString,
@@ -63,7 +68,7 @@ func resourceCiscoNativeEvpnInstance() *schema.Resource {
 			"rt_type": {
 				Type:         schema.TypeString,
 				Optional:     true,
-				ValidateFunc: validation.StringIsNotWhiteSpace,
+				ValidateFunc: validation.StringInSlice([]string{"both"}, false),
 			},
 			"ip_learning": {
 				Type:     schema.TypeBool,
@@ -78,8 +83,11 @@ func resourceCiscoNativeEvpnInstance() *schema.Resource {
 			"re_originate": {
 				Type:         schema.TypeString,
 				Optional:     true,
-				ValidateFunc: validation.StringIsNotWhiteSpace,
+				ValidateFunc: validation.StringInSlice([]string{"route-type5"}, false),
 			},
+			"devices":           devicesSchema(),
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
 		},
 	}
 }
@@ -91,12 +99,14 @@ func resourceCiscoNativeEvpnInstanceCreate(ctx context.Context, d *schema.Resour
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Path:     "/data/Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn-instance/evpn/instance",
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    "/data/Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn-instance/evpn/instance",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 
+	devices := getDevices(d)
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
@@ -108,23 +118,53 @@ func resourceCiscoNativeEvpnInstanceCreate(ctx context.Context, d *schema.Resour
 		if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 			svc.Payload = string(b)
 		}
-		if svc.Provider.Get("debug").(bool) {
-			debugJson(fmt.Sprintf("evpn_instance_%v", d.Get("instance_id").(int)), svc.Payload)
+		svc.CLI = data.CLI()
+		if svc.Config.Debug {
+			debugPayload(fmt.Sprintf("evpn_instance_%v", d.Get("instance_id").(int)), svc)
 		}
 
-		_, err = iosxe.MultiSession(svc)
+		err = c.applyRole(svc, devices)
 		if err != nil {
+			setDevices(d, devices)
 			return diag.FromErr(err)
 		}
 	}
 
 	d.SetId(fmt.Sprintf("evpn_instance_%v", d.Get("instance_id").(int)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
 func resourceCiscoNativeEvpnInstanceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	log.Println("[DEBUG] Cisco EVPN INSTANCE READ") // TODO
+	log.Println("[DEBUG] Cisco EVPN INSTANCE READ")
 	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	bodies, err := c.readRoles(ctx, d, "/data/Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn-instance/evpn/instance")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	states := make(map[string]map[string]interface{})
+	for host, body := range bodies {
+		data := &evpn_instance.CiscoIOSXEL2VpnInstanceCiscoIOSXEL2VpnInstanceEvpn{}
+		if err = unmarshalBody(body, data); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		state := c.CiscoIOSXENativeEvpnInstanceState(d, data)
+		if state == nil {
+			log.Printf("[DEBUG] EVPN Instance %v not found on: %v\n", d.Get("instance_id").(int), host)
+			d.SetId("")
+			return diags
+		}
+		states[host] = state
+	}
+
+	if err = setDevicesDriftState(d, states); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
@@ -138,18 +178,20 @@ func resourceCiscoNativeEvpnInstanceUpdate(ctx context.Context, d *schema.Resour
 		d.Set("instance_id", oldState)
 		return diag.Errorf("Not supported to change EVPN Instance ID number")
 	}
-	if d.HasChange("roles") {
-		oldState, _ := d.GetChange("roles")
-		d.Set("roles", oldState)
-		return diag.Errorf("Not supported to change Roles")
-	}
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Path:     "/data/Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn-instance/evpn/instance",
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    "/data/Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn-instance/evpn/instance",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	devices := getDevices(d)
+	if err = c.removeHosts(ctx, d, devices, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn-instance/evpn/instance/instance=%v", d.Get("instance_id").(int))); err != nil {
+		setDevices(d, devices)
+		return diag.FromErr(err)
 	}
 
 	roles := d.Get("roles").([]interface{})
@@ -163,18 +205,22 @@ func resourceCiscoNativeEvpnInstanceUpdate(ctx context.Context, d *schema.Resour
 		if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 			svc.Payload = string(b)
 		}
-		if svc.Provider.Get("debug").(bool) {
-			debugJson(fmt.Sprintf("evpn_instance_%v", d.Get("instance_id").(int)), svc.Payload)
+		svc.CLI = data.CLI()
+		if svc.Config.Debug {
+			debugPayload(fmt.Sprintf("evpn_instance_%v", d.Get("instance_id").(int)), svc)
 		}
 
-		_, err = iosxe.MultiSession(svc)
-
+		err = c.applyRole(svc, devices)
 		if err != nil {
+			setDevices(d, devices)
 			return diag.FromErr(err)
 		}
 	}
 
 	d.SetId(fmt.Sprintf("evpn_instance_%v", d.Get("instance_id").(int)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
@@ -183,16 +229,18 @@ func resourceCiscoNativeEvpnInstanceDelete(ctx context.Context, d *schema.Resour
 	var diags diag.Diagnostics
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "DELETE",
-		Path:     fmt.Sprintf("/data/Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn-instance/evpn/instance/instance=%v", d.Get("instance_id").(int)),
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "DELETE",
+		Path:    fmt.Sprintf("/data/Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn-instance/evpn/instance/instance=%v", d.Get("instance_id").(int)),
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 
+	devices := getDevices(d)
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
-		_, err := iosxe.MultiSession(svc)
+		err := c.deleteRole(svc, devices)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -202,6 +250,25 @@ func resourceCiscoNativeEvpnInstanceDelete(ctx context.Context, d *schema.Resour
 	return diags
 }
 
+func resourceCiscoNativeEvpnInstanceImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco EVPN INSTANCE IMPORT")
+	c, _ := meta.(*providerClient)
+	parts, err := importID(d.Id(), "<roles>:<instance_id>")
+	if err != nil {
+		return nil, err
+	}
+	id, err := importInt(parts[1], "instance_id")
+	if err != nil {
+		return nil, err
+	}
+	if err = c.importRoles(d, parts[0]); err != nil {
+		return nil, err
+	}
+	d.Set("instance_id", id)
+	d.SetId(fmt.Sprintf("evpn_instance_%v", id))
+	return importRead(ctx, d, meta, resourceCiscoNativeEvpnInstanceRead)
+}
+
 func (*providerClient) CiscoIOSXENativeEvpnInstanceData(d *schema.ResourceData) *evpn_instance.CiscoIOSXEL2VpnInstanceCiscoIOSXEL2VpnInstanceEvpn {
 	data := &evpn_instance.CiscoIOSXEL2VpnInstanceCiscoIOSXEL2VpnInstanceEvpn{}
 	ei := &evpn_instance.CiscoIOSXEL2VpnInstanceInstance{}
@@ -214,19 +281,7 @@ func (*providerClient) CiscoIOSXENativeEvpnInstanceData(d *schema.ResourceData)
 				ei.VlanBased.ReplicationType.Static = null()
 			case "ingress":
 				ei.VlanBased.ReplicationType.Ingress = null()
-			default:
-				log.Panicf("[PANIC] Replication Type (%v) not supported", v.(string))
 			}
-			/*
-				if v.(string) == "ingress" {
-					ei.VlanBased.ReplicationType.Ingress = null()
-				} else {
-				if v.(string) == "ingress" {
-					ei.VlanBased.ReplicationType.Ingress = null()
-				} else {
-					log.Panicf("[PANIC] Replication Type (%v) not supported", v.(string))
-				}
-			*/
 		}
 		if v, ok := d.GetOk("rd"); ok {
 			ei.VlanBased.Rd.RdValue = v.(string)
@@ -234,15 +289,11 @@ func (*providerClient) CiscoIOSXENativeEvpnInstanceData(d *schema.ResourceData)
 		if v, ok := d.GetOk("rt_type"); ok {
 			if v.(string) == "both" {
 				ei.VlanBased.RouteTarget.Both.RtValue = d.Get("rd").(string)
-			} else {
-				log.Panicf("[PANIC] RT Type (%v) not supported", v.(string))
 			}
 		}
 		if v, ok := d.GetOk("re_originate"); ok {
 			if v.(string) == "route-type5" {
 				ei.VlanBased.ReOriginate.RouteType5 = null()
-			} else {
-				log.Panicf("[PANIC] Reoriginates Type (%v) not supported", v.(string))
 			}
 		}
 
@@ -258,3 +309,38 @@ func (*providerClient) CiscoIOSXENativeEvpnInstanceData(d *schema.ResourceData)
 	data.CiscoIOSXEL2VpnInstance.Instance = append(data.CiscoIOSXEL2VpnInstance.Instance, *ei)
 	return data
 }
+
+func (*providerClient) CiscoIOSXENativeEvpnInstanceState(d *schema.ResourceData, data *evpn_instance.CiscoIOSXEL2VpnInstanceCiscoIOSXEL2VpnInstanceEvpn) map[string]interface{} {
+	for _, ei := range data.CiscoIOSXEL2VpnInstance.Instance {
+		if ei.EvpnInstanceNum != d.Get("instance_id").(int) {
+			continue
+		}
+		vlanBased := ei.VlanBased
+		if vlanBased.Encapsulation == "" {
+			return map[string]interface{}{"vlan_based": false}
+		}
+		state := map[string]interface{}{
+			"vlan_based":                true,
+			"encapsulation":             vlanBased.Encapsulation,
+			"replication_type":          "",
+			"rd":                        vlanBased.Rd.RdValue,
+			"rt_type":                   "",
+			"ip_learning":               vlanBased.IP.LocalLearning.Disable == nil,
+			"default_gateway_advertise": vlanBased.DefaultGateway.Advertise == "enable",
+			"re_originate":              "",
+		}
+		if vlanBased.ReplicationType.Static != nil {
+			state["replication_type"] = "static"
+		} else if vlanBased.ReplicationType.Ingress != nil {
+			state["replication_type"] = "ingress"
+		}
+		if vlanBased.RouteTarget.Both.RtValue != "" {
+			state["rt_type"] = "both"
+		}
+		if vlanBased.ReOriginate.RouteType5 != nil {
+			state["re_originate"] = "route-type5"
+		}
+		return state
+	}
+	return nil
+}
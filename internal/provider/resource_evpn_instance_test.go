@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnEvpnInstance(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	path := "Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn-instance/evpn/instance/instance=101"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDevicesRemoved(f.Leafs, path),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnEvpnInstanceConfig(f, "101:101"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_evpn_instance.test", "instance_id", "101"),
+					testAccCheckDevices(f.Leafs, path, `"rd-value":"101:101"`, `"vxlan"`),
+					testAccCheckDevicesRemoved(f.Spines, path),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnEvpnInstanceConfig(f, "101:102"),
+				Check:  testAccCheckDevices(f.Leafs, path, `"rd-value":"101:102"`),
+			},
+		},
+	})
+}
+
+func testAccCiscoEvpnEvpnInstanceConfig(f *testAccFabric, rd string) string {
+	return f.Config() + fmt.Sprintf(`
+resource "ciscoevpn_evpn_instance" "test" {
+  roles            = ["leafs"]
+  instance_id      = 101
+  vlan_based       = true
+  encapsulation    = "vxlan"
+  replication_type = "ingress"
+  rd               = %q
+  rt               = %q
+  rt_type          = "both"
+  ip_learning      = true
+  re_originate     = "route-type5"
+}
+`, rd, rd)
+}
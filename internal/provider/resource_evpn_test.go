@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnEvpn(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	path := "Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnEvpnConfig(f, 20),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_evpn.test", "router_id", "Loopback200"),
+					testAccCheckDevices(f.Leafs, path, `"Loopback":200`, `"limit":20`),
+					testAccCheckDevicesRemoved(f.Spines, path),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnEvpnConfig(f, 30),
+				Check:  testAccCheckDevices(f.Leafs, path, `"limit":30`),
+			},
+		},
+	})
+}
+
+func testAccCiscoEvpnEvpnConfig(f *testAccFabric, limit int) string {
+	return f.Config() + f.LoopbacksConfig(200) + fmt.Sprintf(`
+resource "ciscoevpn_evpn" "test" {
+  depends_on            = [ciscoevpn_loopback.leaf0_200, ciscoevpn_loopback.leaf1_200]
+  roles                 = ["leafs"]
+  replication_type      = "static"
+  mac_duplication_limit = %v
+  ip_duplication_limit  = %v
+  router_id             = ciscoevpn_loopback.leaf0_200.interface_name
+  logging_peer_state    = true
+}
+`, limit, limit)
+}
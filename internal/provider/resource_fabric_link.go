@@ -0,0 +1,394 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/subinterface"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/iosxe"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
+)
+
+func resourceCiscoNativeFabricLink() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Cisco fabric point-to-point link, both endpoints are configured together as routed interfaces with the description naming the far end. The endpoints can have different interface speeds, e.g. a HundredGigE spine to a TwentyFiveGigE leaf",
+		CreateContext: resourceCiscoNativeFabricLinkCreate,
+		ReadContext:   resourceCiscoNativeFabricLinkRead,
+		UpdateContext: resourceCiscoNativeFabricLinkUpdate,
+		DeleteContext: resourceCiscoNativeFabricLinkDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativeFabricLinkImport,
+		},
+		CustomizeDiff: resourceCiscoNativeFabricLinkCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"endpoint": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 2,
+				MaxItems: 2,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ForceNew:     true,
+							ValidateFunc: validation.StringIsNotWhiteSpace,
+						},
+						"ethernet": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+						"interface_speed": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							ForceNew:     true,
+							ValidateFunc: validation.IntInSlice([]int{1, 10, 25, 40, 100, 400}),
+						},
+						"ipv4_address": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.IsIPv4Address,
+						},
+					},
+				},
+			},
+			"ipv4_mask": {
+				Type:         schema.TypeString,
+				Default:      "255.255.255.254",
+				Optional:     true,
+				ValidateFunc: validation.IsIPv4Address,
+			},
+			"unnumbered": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateLoopback(),
+				DiffSuppressFunc: suppressLoopbackDiff,
+			},
+			"mtu": {
+				Type:         schema.TypeInt,
+				Default:      9198,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1500, 9216),
+			},
+			"pim_sm": {
+				Type:     schema.TypeBool,
+				Default:  false,
+				Optional: true,
+			},
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
+		},
+	}
+}
+
+// resourceCiscoNativeFabricLinkCustomizeDiff requires either unnumbered or an address on both endpoints
+func resourceCiscoNativeFabricLinkCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("unnumbered") || !d.NewValueKnown("endpoint") {
+		return nil
+	}
+	addresses := 0
+	for _, v := range d.Get("endpoint").([]interface{}) {
+		if e, ok := v.(map[string]interface{}); ok && e["ipv4_address"].(string) != "" {
+			addresses++
+		}
+	}
+	switch {
+	case d.Get("unnumbered").(string) != "" && addresses > 0:
+		return fmt.Errorf("ipv4_address of the endpoints isn't supported with unnumbered")
+	case d.Get("unnumbered").(string) == "" && addresses != 2:
+		return fmt.Errorf("either unnumbered or the ipv4_address of both endpoints is required")
+	}
+	return nil
+}
+
+func resourceCiscoNativeFabricLinkCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco Fabric Link CREATE")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	applied := []int{}
+	for i := range d.Get("endpoint").([]interface{}) {
+		if err := c.resourceCiscoNativeFabricLinkApply(ctx, d, i); err != nil {
+			// both endpoints or none, the endpoints applied are reverted
+			for _, j := range applied {
+				c.resourceCiscoNativeFabricLinkRemove(ctx, d, j)
+			}
+			return diag.FromErr(err)
+		}
+		applied = append(applied, i)
+	}
+
+	d.SetId(resourceCiscoNativeFabricLinkID(d))
+	if err := c.resourceCiscoNativeFabricLinkPreview(d); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativeFabricLinkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco Fabric Link READ")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	endpoints := d.Get("endpoint").([]interface{})
+	states := make(map[string]map[string]interface{})
+	for i, v := range endpoints {
+		e := v.(map[string]interface{})
+		svc := &service.Client{
+			Context: ctx,
+			Method:  "GET",
+			Path:    c.resourceCiscoNativeFabricLinkPath(d, i),
+			Config:  c.Config,
+			Device:  e["host"].(string),
+		}
+		body, err := iosxe.SingleSession(svc)
+		data := &subinterface.CiscoIOSXENativeEthernet{}
+		if err == nil {
+			err = unmarshalBody(body, data)
+		}
+		if errors.Is(err, iosxe.ErrNotFound) {
+			d.SetId("")
+			return diags
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		ethernet := ethernetInterfaces(data)
+		if len(ethernet) == 0 || ethernet[0].Description == "" {
+			log.Printf("[DEBUG] Fabric link not found on: %v\n", e["host"].(string))
+			d.SetId("")
+			return diags
+		}
+		states[e["host"].(string)] = c.resourceCiscoNativeFabricLinkState(d, &ethernet[0])
+		e["ipv4_address"] = ethernet[0].IP.PrimaryAddress().Address
+	}
+
+	if err := setDriftState(d, states); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("endpoint", endpoints); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := c.resourceCiscoNativeFabricLinkPreview(d); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativeFabricLinkUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco Fabric Link UPDATE")
+	var diags diag.Diagnostics
+
+	// PATCH merges, the addressing and PIM are deleted before the new config is applied
+	leaves := []string{}
+	if d.HasChange("unnumbered") {
+		leaves = append(leaves, "/ip/address", "/ip/unnumbered")
+	}
+	if d.HasChange("pim_sm") {
+		leaves = append(leaves, "/ip/pim")
+	}
+
+	c, _ := meta.(*providerClient)
+	for i, v := range d.Get("endpoint").([]interface{}) {
+		svc := &service.Client{
+			Context: ctx,
+			Method:  "DELETE",
+			Config:  c.Config,
+			Device:  v.(map[string]interface{})["host"].(string),
+		}
+		for _, leaf := range leaves {
+			svc.Path = c.resourceCiscoNativeFabricLinkPath(d, i) + leaf
+			if _, err := iosxe.SingleSession(svc); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+				return diag.FromErr(err)
+			}
+		}
+		if err := c.resourceCiscoNativeFabricLinkApply(ctx, d, i); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(resourceCiscoNativeFabricLinkID(d))
+	if err := c.resourceCiscoNativeFabricLinkPreview(d); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativeFabricLinkDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco Fabric Link DELETE")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	for i := range d.Get("endpoint").([]interface{}) {
+		if err := c.resourceCiscoNativeFabricLinkRemove(ctx, d, i); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId("")
+	return diags
+}
+
+func resourceCiscoNativeFabricLinkImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco Fabric Link IMPORT")
+	format := "<host>:<interface>,<host>:<interface>"
+	sides := strings.Split(d.Id(), ",")
+	if len(sides) != 2 {
+		return nil, fmt.Errorf("unexpected import ID %q, expected %v", d.Id(), format)
+	}
+	endpoints := []interface{}{}
+	for _, side := range sides {
+		parts, err := importHostID(side, "<host>:<interface>")
+		if err != nil {
+			return nil, err
+		}
+		speed, ethernet, err := interfaceSpeed(parts[1], "TenGigabitEthernet1/0/1")
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, map[string]interface{}{
+			"host":            parts[0],
+			"ethernet":        ethernet,
+			"interface_speed": speed,
+		})
+	}
+	d.Set("endpoint", endpoints)
+	d.SetId(resourceCiscoNativeFabricLinkID(d))
+	return importRead(ctx, d, meta, resourceCiscoNativeFabricLinkRead)
+}
+
+// resourceCiscoNativeFabricLinkID is the interfaces of both endpoints, e.g. 10.0.0.1:TenGigabitEthernet1/0/1,10.0.0.11:TenGigabitEthernet1/0/49
+func resourceCiscoNativeFabricLinkID(d *schema.ResourceData) string {
+	ids := []string{}
+	for i := range d.Get("endpoint").([]interface{}) {
+		host, name := resourceCiscoNativeFabricLinkEndpoint(d, i)
+		ids = append(ids, fmt.Sprintf("%v:%v", host, name))
+	}
+	return strings.Join(ids, ",")
+}
+
+// resourceCiscoNativeFabricLinkEndpoint returns the host and the interface name of an endpoint
+func resourceCiscoNativeFabricLinkEndpoint(d *schema.ResourceData, i int) (string, string) {
+	e := d.Get("endpoint").([]interface{})[i].(map[string]interface{})
+	name := interfaceSpeeds[e["interface_speed"].(int)]
+	if name == "" {
+		name = interfaceSpeeds[1]
+	}
+	return e["host"].(string), name + e["ethernet"].(string)
+}
+
+func (*providerClient) resourceCiscoNativeFabricLinkPath(d *schema.ResourceData, i int) string {
+	_, name := resourceCiscoNativeFabricLinkEndpoint(d, i)
+	return interfacePath(name)
+}
+
+// resourceCiscoNativeFabricLinkData returns the interface of an endpoint, the description names the other endpoint
+func (*providerClient) resourceCiscoNativeFabricLinkData(d *schema.ResourceData, i int) *subinterface.CiscoIOSXENativeEthernet {
+	e := d.Get("endpoint").([]interface{})[i].(map[string]interface{})
+	host, name := resourceCiscoNativeFabricLinkEndpoint(d, 1-i)
+	ethernet := &subinterface.CiscoIOSXENativeEthernetInterface{
+		Name:        e["ethernet"].(string),
+		Description: fmt.Sprintf("Fabric link to %v %v", host, name),
+		Mtu:         d.Get("mtu").(int),
+		Switchport:  &subinterface.CiscoIOSXENativeEthernetInterfaceSwitchportConf{Switchport: false},
+	}
+	if v, ok := d.GetOk("unnumbered"); ok {
+		ethernet.IP.Unnumbered = fmt.Sprintf("Loopback%v", loopbackId(v.(string)))
+	} else {
+		ethernet.IP.Address = &subinterface.CiscoIOSXENativeEthernetInterfaceAddress{}
+		ethernet.IP.Address.Primary.Address = e["ipv4_address"].(string)
+		ethernet.IP.Address.Primary.Mask = d.Get("ipv4_mask").(string)
+	}
+	if d.Get("pim_sm").(bool) {
+		ethernet.IP.Pim = &subinterface.CiscoIOSXENativeEthernetInterfacePim{}
+		ethernet.IP.Pim.CiscoIOSXEMulticastPimModeChoiceCfg.SparseMode = map[string]string{}
+	}
+	data := &subinterface.CiscoIOSXENativeEthernet{}
+	ethernetData(data, e["interface_speed"].(int), ethernet)
+	return data
+}
+
+// resourceCiscoNativeFabricLinkApply PATCHes the interface of an endpoint
+func (c *providerClient) resourceCiscoNativeFabricLinkApply(ctx context.Context, d *schema.ResourceData, i int) error {
+	host, _ := resourceCiscoNativeFabricLinkEndpoint(d, i)
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    c.resourceCiscoNativeFabricLinkPath(d, i),
+		Config:  c.Config,
+		Device:  host,
+	}
+	data := c.resourceCiscoNativeFabricLinkData(d, i)
+	if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
+		svc.Payload = string(b)
+	}
+	svc.CLI = data.CLI()
+
+	if svc.Config.Debug {
+		debugPayload(fmt.Sprintf("fabric_link_%v", strings.ReplaceAll(resourceCiscoNativeFabricLinkID(d), "/", "_")), svc)
+	}
+	_, err := iosxe.SingleSession(svc)
+	return err
+}
+
+// resourceCiscoNativeFabricLinkRemove deletes the config of the endpoint, the physical interface stays
+func (c *providerClient) resourceCiscoNativeFabricLinkRemove(ctx context.Context, d *schema.ResourceData, i int) error {
+	host, _ := resourceCiscoNativeFabricLinkEndpoint(d, i)
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "DELETE",
+		Config:  c.Config,
+		Device:  host,
+	}
+	for _, leaf := range []string{"/ip/pim", "/ip/unnumbered", "/ip/address", "/mtu", "/description", "/switchport-conf"} {
+		svc.Path = c.resourceCiscoNativeFabricLinkPath(d, i) + leaf
+		if _, err := iosxe.SingleSession(svc); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// resourceCiscoNativeFabricLinkPreview sets the CLI (and in dry run mode the payloads) of both endpoints
+func (c *providerClient) resourceCiscoNativeFabricLinkPreview(d *schema.ResourceData) error {
+	cli := make(map[string]interface{})
+	payloads := make(map[string]interface{})
+	for i := range d.Get("endpoint").([]interface{}) {
+		host, _ := resourceCiscoNativeFabricLinkEndpoint(d, i)
+		data := c.resourceCiscoNativeFabricLinkData(d, i)
+		cli[host] = strings.Join(data.CLI(), "\n")
+		if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
+			payloads[host] = string(b)
+		}
+	}
+	if err := d.Set("cli_preview", cli); err != nil {
+		return err
+	}
+	if !c.Config.DryRun {
+		return d.Set("rendered_payloads", map[string]interface{}{})
+	}
+	return d.Set("rendered_payloads", payloads)
+}
+
+func (*providerClient) resourceCiscoNativeFabricLinkState(d *schema.ResourceData, ethernet *subinterface.CiscoIOSXENativeEthernetInterface) map[string]interface{} {
+	state := map[string]interface{}{
+		"mtu":        ethernet.Mtu,
+		"pim_sm":     ethernet.IP.Pim != nil && ethernet.IP.Pim.CiscoIOSXEMulticastPimModeChoiceCfg.SparseMode != nil,
+		"unnumbered": ethernet.IP.Unnumbered,
+	}
+	if v := d.Get("unnumbered").(string); v != "" && ethernet.IP.Unnumbered == fmt.Sprintf("Loopback%v", loopbackId(v)) {
+		state["unnumbered"] = v
+	}
+	if mask := ethernet.IP.PrimaryAddress().Mask; mask != "" {
+		state["ipv4_mask"] = mask
+	}
+	return state
+}
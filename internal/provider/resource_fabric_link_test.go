@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// TestAccCiscoEvpnFabricLink links a HundredGigE spine to a TwentyFiveGigE leaf
+func TestAccCiscoEvpnFabricLink(t *testing.T) {
+	f := newTestAccFabric(t, 1, 1)
+	spine := "Cisco-IOS-XE-native:native/interface/HundredGigE=1%2F1%2F1"
+	leaf := "Cisco-IOS-XE-native:native/interface/TwentyFiveGigE=1%2F1%2F49"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckDevicesRemoved(f.Spines, spine+"/description"),
+			testAccCheckDevicesRemoved(f.Leafs, leaf+"/ip/unnumbered"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCiscoEvpnFabricLinkConfig(f, "null", `"100.119.1.0"`, "null", false),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("either unnumbered or the ipv4_address of both endpoints is required"),
+			},
+			{
+				Config: testAccCiscoEvpnFabricLinkConfig(f, "null", `"100.119.1.0"`, `"100.119.1.1"`, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDevices(f.Spines, spine, `"address":"100.119.1.0"`, `"mask":"255.255.255.254"`, `"mtu":9198`, `"switchport":false`,
+						fmt.Sprintf(`"description":"Fabric link to %v TwentyFiveGigE1/1/49"`, f.Leafs[0].Host())),
+					testAccCheckDevices(f.Leafs, leaf, `"address":"100.119.1.1"`,
+						fmt.Sprintf(`"description":"Fabric link to %v HundredGigE1/1/1"`, f.Spines[0].Host())),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnFabricLinkConfig(f, `"Loopback0"`, "null", "null", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDevicesRemoved(f.Spines, spine+"/ip/address"),
+					testAccCheckDevices(f.Spines, spine, `"unnumbered":"Loopback0"`, `"sparse-mode"`),
+					testAccCheckDevices(f.Leafs, leaf, `"unnumbered":"Loopback0"`, `"sparse-mode"`),
+				),
+			},
+			{
+				ResourceName:      "ciscoevpn_fabric_link.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%v:HundredGigE1/1/1,%v:TwentyFiveGigE1/1/49", f.Spines[0].Host(), f.Leafs[0].Host()),
+				ImportStateVerify: true,
+				// The mask isn't configured on unnumbered links
+				ImportStateVerifyIgnore: []string{"ipv4_mask"},
+			},
+		},
+	})
+}
+
+func testAccCiscoEvpnFabricLinkConfig(f *testAccFabric, unnumbered string, spine string, leaf string, pim bool) string {
+	return f.Config() + fmt.Sprintf(`
+resource "ciscoevpn_fabric_link" "test" {
+  unnumbered = %v
+  pim_sm     = %v
+
+  endpoint {
+    host            = %q
+    ethernet        = "1/1/1"
+    interface_speed = 100
+    ipv4_address    = %v
+  }
+  endpoint {
+    host            = %q
+    ethernet        = "1/1/49"
+    interface_speed = 25
+    ipv4_address    = %v
+  }
+}
+`, unnumbered, pim, f.Spines[0].Host(), spine, f.Leafs[0].Host(), leaf)
+}
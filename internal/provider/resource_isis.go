@@ -0,0 +1,479 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/isis"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/loopback"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/iosxe"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
+)
+
+var isisAreaRe = regexp.MustCompile(`^[0-9A-Fa-f]{2}(\.[0-9A-Fa-f]{4}){0,6}$`)
+
+func resourceCiscoNativeIsis() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Cisco IS-IS underlay, the NET system-id is derived from the address of the loopback on each device",
+		CreateContext: transactional(resourceCiscoNativeIsisCreate),
+		ReadContext:   resourceCiscoNativeIsisRead,
+		UpdateContext: transactional(resourceCiscoNativeIsisUpdate),
+		DeleteContext: transactional(resourceCiscoNativeIsisDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativeIsisImport,
+		},
+		CustomizeDiff: devicesCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"roles": {
+				Type:     schema.TypeList,
+				Required: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"area_tag": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"loopback": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateLoopback(),
+				DiffSuppressFunc: suppressLoopbackDiff,
+			},
+			"area": {
+				Type:             schema.TypeString,
+				Default:          "49.0001",
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(isisAreaRe, "expected an area address, e.g. 49.0001")),
+			},
+			"level_2_only": {
+				Type:     schema.TypeBool,
+				Default:  true,
+				Optional: true,
+			},
+			"interfaces": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateInterface(),
+				},
+			},
+			"point_to_point": {
+				Type:     schema.TypeBool,
+				Default:  true,
+				Optional: true,
+			},
+			"bfd": {
+				Type:     schema.TypeBool,
+				Default:  false,
+				Optional: true,
+			},
+			"passive_loopbacks": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringMatch(loopbackRe, "expected a Loopback interface, e.g. Loopback0"),
+				},
+			},
+			"devices":           devicesSchema(),
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
+		},
+	}
+}
+
+func resourceCiscoNativeIsisCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco IS-IS CREATE")
+	var diags diag.Diagnostics
+	var err error
+
+	c, _ := meta.(*providerClient)
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "PATCH",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	devices := getDevices(d)
+	roles := d.Get("roles").([]interface{})
+	for _, role := range roles {
+		svc.Role = role.(string)
+		if diags = c.resourceCiscoNativeIsisApply(svc, d, devices); diags.HasError() {
+			setDevices(d, devices)
+			return diags
+		}
+	}
+
+	d.SetId(fmt.Sprintf("isis_%v", d.Get("area_tag").(string)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativeIsisRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco IS-IS READ")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	bodies, err := c.readRoles(ctx, d, c.resourceCiscoNativeIsisPath(d))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	loopbacks, err := c.readRoles(ctx, d, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Loopback=%v", loopbackId(d.Get("loopback").(string))))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// IS-IS config of the fabric links per host
+	routers := make(map[string]map[string]*isis.CiscoIOSXEIsisInterfaceRouter)
+	circuits := make(map[string]map[string]*isis.CiscoIOSXEIsisInterface)
+	for _, v := range d.Get("interfaces").([]interface{}) {
+		name := v.(string)
+		data, err := c.readRoles(ctx, d, interfacePath(name)+"/ip/router/Cisco-IOS-XE-isis:isis")
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		for host, body := range data {
+			router := &isis.CiscoIOSXEIsisInterfaceRouters{}
+			if err = unmarshalBody(body, router); err != nil {
+				if errors.Is(err, iosxe.ErrNotFound) {
+					continue
+				}
+				return diag.FromErr(err)
+			}
+			if routers[host] == nil {
+				routers[host] = make(map[string]*isis.CiscoIOSXEIsisInterfaceRouter)
+			}
+			routers[host][name] = &router.Isis
+		}
+		data, err = c.readRoles(ctx, d, interfacePath(name)+"/Cisco-IOS-XE-isis:isis")
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		for host, body := range data {
+			circuit := &isis.CiscoIOSXEIsisInterfaces{}
+			if err = unmarshalBody(body, circuit); err != nil {
+				if errors.Is(err, iosxe.ErrNotFound) {
+					continue
+				}
+				return diag.FromErr(err)
+			}
+			if circuits[host] == nil {
+				circuits[host] = make(map[string]*isis.CiscoIOSXEIsisInterface)
+			}
+			circuits[host][name] = &circuit.Isis
+		}
+	}
+
+	states := make(map[string]map[string]interface{})
+	for host, body := range bodies {
+		data := &isis.CiscoIOSXENativeRouterIsis{}
+		if err = unmarshalBody(body, data); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		if len(data.CiscoIOSXEIsis) == 0 {
+			log.Printf("[DEBUG] IS-IS %v not found on: %v\n", d.Get("area_tag").(string), host)
+			d.SetId("")
+			return diags
+		}
+		lp := &loopback.CiscoIOSXENativeLoopbackInterface{}
+		if err = unmarshalBody(loopbacks[host], lp); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		states[host] = c.resourceCiscoNativeIsisState(d, &data.CiscoIOSXEIsis[0], lp, routers[host], circuits[host])
+	}
+
+	if err = setDevicesDriftState(d, states); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativeIsisUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco IS-IS UPDATE")
+	var diags diag.Diagnostics
+	var err error
+
+	c, _ := meta.(*providerClient)
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "PATCH",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	devices := getDevices(d)
+	if err = c.removeHosts(ctx, d, devices, c.resourceCiscoNativeIsisPaths(d)...); err != nil {
+		setDevices(d, devices)
+		return diag.FromErr(err)
+	}
+
+	// PATCH merges, changed leaves and removed interfaces are deleted before the new config is applied
+	paths := []string{}
+	current := make(map[string]bool)
+	for _, v := range d.Get("interfaces").([]interface{}) {
+		current[v.(string)] = true
+	}
+	old, _ := d.GetChange("interfaces")
+	for _, v := range old.([]interface{}) {
+		name := v.(string)
+		if !current[name] {
+			paths = append(paths, interfacePath(name)+"/ip/router/Cisco-IOS-XE-isis:isis")
+		}
+		if !current[name] || d.HasChanges("level_2_only", "point_to_point") {
+			paths = append(paths, interfacePath(name)+"/Cisco-IOS-XE-isis:isis")
+		}
+	}
+	router := c.resourceCiscoNativeIsisPath(d)
+	if d.HasChanges("area", "loopback") {
+		paths = append(paths, router+"/net")
+	}
+	if d.HasChange("level_2_only") {
+		paths = append(paths, router+"/is-type")
+	}
+	if d.HasChange("bfd") {
+		paths = append(paths, router+"/bfd")
+	}
+	if d.HasChange("passive_loopbacks") {
+		paths = append(paths, router+"/passive-interface")
+	}
+
+	roles := d.Get("roles").([]interface{})
+	for _, role := range roles {
+		svc.Role = role.(string)
+		if len(paths) > 0 {
+			if err = c.deleteRoleSteps(svc, paths, devices); err != nil {
+				setDevices(d, devices)
+				return diag.FromErr(err)
+			}
+		}
+		if diags = c.resourceCiscoNativeIsisApply(svc, d, devices); diags.HasError() {
+			setDevices(d, devices)
+			return diags
+		}
+	}
+
+	d.SetId(fmt.Sprintf("isis_%v", d.Get("area_tag").(string)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativeIsisDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco IS-IS DELETE")
+	var err error
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "DELETE",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	devices := getDevices(d)
+	paths := c.resourceCiscoNativeIsisPaths(d)
+	roles := d.Get("roles").([]interface{})
+	for _, role := range roles {
+		svc.Role = role.(string)
+		err = c.deleteRoleSteps(svc, paths, devices)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId("")
+	return diags
+}
+
+func resourceCiscoNativeIsisImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco IS-IS IMPORT")
+	c, _ := meta.(*providerClient)
+	parts, err := importID(d.Id(), "<roles>:<area_tag>:<loopback>")
+	if err != nil {
+		return nil, err
+	}
+	if err = c.importRoles(d, parts[0]); err != nil {
+		return nil, err
+	}
+	d.Set("area_tag", parts[1])
+	d.Set("loopback", parts[2])
+	d.SetId(fmt.Sprintf("isis_%v", parts[1]))
+	return importRead(ctx, d, meta, resourceCiscoNativeIsisRead)
+}
+
+// resourceCiscoNativeIsisApply builds the payloads of each host in the role with its NET and applies them
+func (c *providerClient) resourceCiscoNativeIsisApply(svc *service.Client, d *schema.ResourceData, devices map[string]*deviceState) diag.Diagnostics {
+	id, diags := loopbackNumber(d, "loopback", c.roleTarget(svc.Role))
+	if diags.HasError() {
+		return diags
+	}
+	addresses, err := c.loopbackAddresses(svc.Context, svc.Role, id)
+	if err != nil {
+		return attrDiag("loopback", c.roleTarget(svc.Role), err)
+	}
+
+	hostSteps := make(map[string][]payloadStep)
+	for host, address := range addresses {
+		netID, err := isisNet(d.Get("area").(string), address)
+		if err != nil {
+			return attrDiag("loopback", host, err)
+		}
+		hostSteps[host] = c.resourceCiscoNativeIsisSteps(d, netID)
+		if svc.Config.Debug {
+			svc.Payload, svc.CLI = stepsPayload(hostSteps[host])
+			debugPayload(fmt.Sprintf("isis_%v_%v", svc.Role, host), svc)
+		}
+	}
+	if err = c.applyHostSteps(svc, hostSteps, devices); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// isisNet returns the NET of the area with the system-id of the IPv4 address, e.g. 100.119.100.11 is 1001.1910.0011
+func isisNet(area string, address string) (string, error) {
+	ip := net.ParseIP(address).To4()
+	if ip == nil {
+		return "", fmt.Errorf("invalid IPv4 address %q", address)
+	}
+	digits := fmt.Sprintf("%03d%03d%03d%03d", ip[0], ip[1], ip[2], ip[3])
+	return fmt.Sprintf("%v.%v.%v.%v.00", area, digits[0:4], digits[4:8], digits[8:12]), nil
+}
+
+func (*providerClient) resourceCiscoNativeIsisPath(d *schema.ResourceData) string {
+	return fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/Cisco-IOS-XE-isis:isis=%v", d.Get("area_tag").(string))
+}
+
+// resourceCiscoNativeIsisPaths returns the objects of the IS-IS process in the order they are deleted
+func (c *providerClient) resourceCiscoNativeIsisPaths(d *schema.ResourceData) []string {
+	paths := []string{}
+	for _, v := range d.Get("interfaces").([]interface{}) {
+		paths = append(paths,
+			interfacePath(v.(string))+"/ip/router/Cisco-IOS-XE-isis:isis",
+			interfacePath(v.(string))+"/Cisco-IOS-XE-isis:isis",
+		)
+	}
+	return append(paths, c.resourceCiscoNativeIsisPath(d))
+}
+
+// resourceCiscoNativeIsisSteps returns the IS-IS process and the IS-IS config of the fabric links of a host
+func (c *providerClient) resourceCiscoNativeIsisSteps(d *schema.ResourceData, netID string) []payloadStep {
+	process := isis.CiscoIOSXEIsis{
+		AreaTag: d.Get("area_tag").(string),
+		Net:     []isis.CiscoIOSXEIsisNet{{Tag: netID}},
+	}
+	if d.Get("level_2_only").(bool) {
+		process.IsType = "level-2-only"
+	}
+	if d.Get("bfd").(bool) {
+		process.Bfd = &isis.CiscoIOSXEIsisBfd{AllInterfaces: null()}
+	}
+	if v := d.Get("passive_loopbacks").([]interface{}); len(v) > 0 {
+		process.PassiveInterface = &isis.CiscoIOSXEIsisPassiveInterface{}
+		for _, name := range v {
+			process.PassiveInterface.Interface = append(process.PassiveInterface.Interface, fmt.Sprintf("Loopback%v", loopbackId(name.(string))))
+		}
+	}
+	router := &isis.CiscoIOSXENativeRouterIsis{
+		CiscoIOSXEIsis: []isis.CiscoIOSXEIsis{process},
+	}
+	steps := []payloadStep{
+		newPayloadStep("/data/Cisco-IOS-XE-native:native/router", router),
+	}
+
+	names := d.Get("interfaces").([]interface{})
+	if len(names) == 0 {
+		return steps
+	}
+	interfaces := &isis.CiscoIOSXENativeIsisInterfaces{
+		Interface: make(map[string][]isis.CiscoIOSXENativeIsisInterface),
+	}
+	for _, v := range names {
+		list, id := interfaceName(v.(string))
+		data := isis.CiscoIOSXENativeIsisInterface{Name: id}
+		data.IP.Router.Isis.Tag = process.AreaTag
+		circuit := &isis.CiscoIOSXEIsisInterface{}
+		if d.Get("level_2_only").(bool) {
+			circuit.CircuitType = "level-2-only"
+		}
+		if d.Get("point_to_point").(bool) {
+			circuit.Network = &isis.CiscoIOSXEIsisInterfaceNetwork{PointToPoint: null()}
+		}
+		if circuit.CircuitType != "" || circuit.Network != nil {
+			data.Isis = circuit
+		}
+		interfaces.Interface[list] = append(interfaces.Interface[list], data)
+	}
+	return append(steps, newPayloadStep("/data/Cisco-IOS-XE-native:native/interface", interfaces))
+}
+
+func (*providerClient) resourceCiscoNativeIsisState(d *schema.ResourceData, process *isis.CiscoIOSXEIsis, lp *loopback.CiscoIOSXENativeLoopbackInterface, routers map[string]*isis.CiscoIOSXEIsisInterfaceRouter, circuits map[string]*isis.CiscoIOSXEIsisInterface) map[string]interface{} {
+	state := map[string]interface{}{
+		"level_2_only": process.IsType == "level-2-only",
+		"bfd":          process.Bfd != nil && process.Bfd.AllInterfaces != nil,
+	}
+
+	// the NET is the area followed by the system-id and the selector
+	state["loopback"] = ""
+	if len(process.Net) > 0 {
+		netID := process.Net[0].Tag
+		state["loopback"] = netID
+		if parts := strings.Split(netID, "."); len(parts) > 4 {
+			state["area"] = strings.Join(parts[:len(parts)-4], ".")
+		}
+		for _, l := range lp.CiscoIOSXENativeLoopback {
+			if expected, err := isisNet(fmt.Sprint(state["area"]), l.IP.Address.Primary.Address); err == nil && expected == netID {
+				state["loopback"] = d.Get("loopback").(string)
+			}
+		}
+	}
+
+	passive := make(map[string]bool)
+	if process.PassiveInterface != nil {
+		for _, v := range process.PassiveInterface.Interface {
+			passive[v] = true
+		}
+	}
+	loopbacks := []interface{}{}
+	for _, v := range d.Get("passive_loopbacks").([]interface{}) {
+		if passive[fmt.Sprintf("Loopback%v", loopbackId(v.(string)))] {
+			loopbacks = append(loopbacks, v)
+		}
+	}
+	state["passive_loopbacks"] = loopbacks
+
+	// only interfaces running the process are part of the state, the network type is read from them
+	pointToPoint := true
+	links := []interface{}{}
+	for _, v := range d.Get("interfaces").([]interface{}) {
+		name := v.(string)
+		if r, ok := routers[name]; !ok || r.Tag != process.AreaTag {
+			continue
+		}
+		links = append(links, v)
+		circuit := circuits[name]
+		pointToPoint = pointToPoint && circuit != nil && circuit.Network != nil && circuit.Network.PointToPoint != nil
+	}
+	state["interfaces"] = links
+	if len(links) > 0 {
+		state["point_to_point"] = pointToPoint
+	}
+	return state
+}
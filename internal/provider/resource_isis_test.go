@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnIsis(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	path := "Cisco-IOS-XE-native:native/router/Cisco-IOS-XE-isis:isis=1"
+	link := "Cisco-IOS-XE-native:native/interface/TenGigabitEthernet=1%2F1%2F2/ip/router/Cisco-IOS-XE-isis:isis"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckDevicesRemoved(f.Devices(), path),
+			testAccCheckDevicesRemoved(f.Devices(), "Cisco-IOS-XE-native:native/interface/TenGigabitEthernet=1%2F1%2F1/Cisco-IOS-XE-isis:isis"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnIsisConfig(f, `"TenGigabitEthernet1/1/1", "TenGigabitEthernet1/1/2"`, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_isis.test", "devices.#", "3"),
+					testAccCheckDevices(f.Spines, path, `"tag":"49.0001.1001.1910.0001.00"`, `"Loopback100"`, `"all-interfaces"`),
+					testAccCheckDevices(f.Leafs[:1], path, `"tag":"49.0001.1001.1910.0011.00"`),
+					testAccCheckDevices(f.Leafs[1:], path, `"tag":"49.0001.1001.1910.0012.00"`),
+					testAccCheckDevices(f.Devices(), link, `"tag":"1"`),
+					testAccCheckDevices(f.Devices(), "Cisco-IOS-XE-native:native/interface/TenGigabitEthernet=1%2F1%2F2/Cisco-IOS-XE-isis:isis", `"point-to-point"`, `"level-2-only"`),
+					resource.TestMatchResourceAttr("ciscoevpn_isis.test", fmt.Sprintf("cli_preview.%v", f.Spines[0].Host()), regexp.MustCompile(`net 49\.0001\.1001\.1910\.0001\.00\n`)),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnIsisConfig(f, `"TenGigabitEthernet1/1/1"`, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDevicesRemoved(f.Devices(), link),
+					testAccCheckDevicesRemoved(f.Devices(), path+"/bfd"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCiscoEvpnIsisConfig(f *testAccFabric, interfaces string, bfd bool) string {
+	return f.Config() + f.LoopbacksConfig(100) + fmt.Sprintf(`
+resource "ciscoevpn_isis" "test" {
+  depends_on        = [ciscoevpn_loopback.spine0_100, ciscoevpn_loopback.leaf0_100, ciscoevpn_loopback.leaf1_100]
+  roles             = ["spines", "leafs"]
+  area_tag          = "1"
+  loopback          = "Loopback100"
+  interfaces        = [%v]
+  bfd               = %v
+  passive_loopbacks = ["Loopback100"]
+}
+`, interfaces, bfd)
+}
@@ -0,0 +1,497 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/evpn_instance"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/nve"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/svi"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/vlan"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/iosxe"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
+)
+
+func resourceCiscoNativeL2vni() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Cisco L2VNI (EVPN instance, VLAN, NVE member VNI and optional SVI gateway)",
+		CreateContext: transactional(resourceCiscoNativeL2vniCreate),
+		ReadContext:   resourceCiscoNativeL2vniRead,
+		UpdateContext: transactional(resourceCiscoNativeL2vniUpdate),
+		DeleteContext: transactional(resourceCiscoNativeL2vniDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativeL2vniImport,
+		},
+		CustomizeDiff: customdiff.All(devicesCustomizeDiff, resourceCiscoNativeL2vniCustomizeDiff),
+		Schema: map[string]*schema.Schema{
+			"roles": {
+				Type:     schema.TypeList,
+				Required: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"vlan_id": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(1, 4094),
+			},
+			"vni": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(1, 16777215),
+			},
+			"evi": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(1, 65535),
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "ManagedByTerraform",
+			},
+			"replication_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "ingress",
+				ValidateFunc: validation.StringInSlice([]string{"ingress", "static"}, false),
+			},
+			"multicast_group": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsIPv4Address,
+			},
+			"rd": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"rt": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"gateway": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"vrf": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"ipv4_address": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.IsIPv4Address,
+						},
+						"ipv4_mask": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.IsIPv4Address,
+						},
+					},
+				},
+			},
+			"devices":           devicesSchema(),
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
+		},
+	}
+}
+
+// resourceCiscoNativeL2vniCustomizeDiff requires a multicast group for static replication
+func resourceCiscoNativeL2vniCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("replication_type") || !d.NewValueKnown("multicast_group") {
+		return nil
+	}
+	group := d.Get("multicast_group").(string)
+	switch d.Get("replication_type").(string) {
+	case "static":
+		if group == "" {
+			return fmt.Errorf("multicast_group is required with replication_type static")
+		}
+	default:
+		if group != "" {
+			return fmt.Errorf("multicast_group is only supported with replication_type static")
+		}
+	}
+	return nil
+}
+
+func resourceCiscoNativeL2vniCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco L2VNI CREATE")
+	var diags diag.Diagnostics
+	var err error
+
+	c, _ := meta.(*providerClient)
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "PATCH",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	devices := getDevices(d)
+	roles := d.Get("roles").([]interface{})
+	for _, role := range roles {
+		svc.Role = role.(string)
+		steps := c.resourceCiscoNativeL2vniSteps(d)
+		if svc.Config.Debug {
+			svc.Payload, svc.CLI = stepsPayload(steps)
+			debugPayload(fmt.Sprintf("l2vni_%v_%v", svc.Role, d.Get("vlan_id").(int)), svc)
+		}
+
+		err = c.applyRoleSteps(svc, steps, devices)
+		if err != nil {
+			setDevices(d, devices)
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(fmt.Sprintf("l2vni_%v", d.Get("vlan_id").(int)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativeL2vniRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco L2VNI READ")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	id := d.Get("vlan_id").(int)
+	vlans, err := c.readRoles(ctx, d, "/data/Cisco-IOS-XE-native:native/vlan")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	instances, err := c.readRoles(ctx, d, "/data/Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn-instance/evpn/instance")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	nves, err := c.readRoles(ctx, d, "/data/Cisco-IOS-XE-native:native/interface/nve=1")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	svis, err := c.readRoles(ctx, d, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v", id))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	states := make(map[string]map[string]interface{})
+	for host, body := range vlans {
+		vlanData := &vlan.CiscoIOSXENativeVlans{}
+		if err = unmarshalBody(body, vlanData); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		instanceData := &evpn_instance.CiscoIOSXEL2VpnInstanceCiscoIOSXEL2VpnInstanceEvpn{}
+		if err = unmarshalBody(instances[host], instanceData); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		nveData := &nve.CiscoIOSXENativeNves{}
+		if err = unmarshalBody(nves[host], nveData); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		sviData := &svi.CiscoIOSXENativeSvis{}
+		if err = unmarshalBody(svis[host], sviData); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+
+		state := c.resourceCiscoNativeL2vniState(id, vlanData, instanceData, nveData, sviData)
+		if state == nil {
+			log.Printf("[DEBUG] L2VNI %v not found on: %v\n", id, host)
+			d.SetId("")
+			return diags
+		}
+		states[host] = state
+	}
+
+	if err = setDevicesDriftState(d, states); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativeL2vniUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco L2VNI UPDATE")
+	var diags diag.Diagnostics
+	var err error
+
+	c, _ := meta.(*providerClient)
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "PATCH",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	oldGateway, newGateway := d.GetChange("gateway")
+	hadGateway := len(oldGateway.([]interface{})) > 0
+	devices := getDevices(d)
+	if err = c.removeHosts(ctx, d, devices, c.resourceCiscoNativeL2vniPaths(d, hadGateway)...); err != nil {
+		setDevices(d, devices)
+		return diag.FromErr(err)
+	}
+
+	// PATCH merges, replaced objects are removed before the new config is applied
+	paths := []string{}
+	if d.HasChanges("replication_type", "multicast_group") {
+		paths = append(paths, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/nve=1/member/vni=%v", d.Get("vni").(int)))
+	}
+	if hadGateway && len(newGateway.([]interface{})) == 0 {
+		paths = append(paths, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v", d.Get("vlan_id").(int)))
+	}
+
+	roles := d.Get("roles").([]interface{})
+	for _, role := range roles {
+		svc.Role = role.(string)
+		if len(paths) > 0 {
+			if err = c.deleteRoleSteps(svc, paths, devices); err != nil {
+				setDevices(d, devices)
+				return diag.FromErr(err)
+			}
+		}
+
+		steps := c.resourceCiscoNativeL2vniSteps(d)
+		if svc.Config.Debug {
+			svc.Payload, svc.CLI = stepsPayload(steps)
+			debugPayload(fmt.Sprintf("l2vni_%v_%v", svc.Role, d.Get("vlan_id").(int)), svc)
+		}
+
+		err = c.applyRoleSteps(svc, steps, devices)
+		if err != nil {
+			setDevices(d, devices)
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(fmt.Sprintf("l2vni_%v", d.Get("vlan_id").(int)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativeL2vniDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco L2VNI DELETE")
+	var err error
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "DELETE",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	devices := getDevices(d)
+	paths := c.resourceCiscoNativeL2vniPaths(d, len(d.Get("gateway").([]interface{})) > 0)
+	roles := d.Get("roles").([]interface{})
+	for _, role := range roles {
+		svc.Role = role.(string)
+		err = c.deleteRoleSteps(svc, paths, devices)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId("")
+	return diags
+}
+
+func resourceCiscoNativeL2vniImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco L2VNI IMPORT")
+	c, _ := meta.(*providerClient)
+	parts, err := importID(d.Id(), "<roles>:<vlan_id>")
+	if err != nil {
+		return nil, err
+	}
+	id, err := importInt(parts[1], "vlan_id")
+	if err != nil {
+		return nil, err
+	}
+	if err = c.importRoles(d, parts[0]); err != nil {
+		return nil, err
+	}
+	d.Set("vlan_id", id)
+	d.SetId(fmt.Sprintf("l2vni_%v", id))
+	return importRead(ctx, d, meta, resourceCiscoNativeL2vniRead)
+}
+
+// resourceCiscoNativeL2vniPaths returns the objects of the L2VNI in the order they are deleted
+func (*providerClient) resourceCiscoNativeL2vniPaths(d *schema.ResourceData, gateway bool) []string {
+	paths := []string{}
+	if gateway {
+		paths = append(paths, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v", d.Get("vlan_id").(int)))
+	}
+	return append(paths,
+		fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/nve=1/member/vni=%v", d.Get("vni").(int)),
+		fmt.Sprintf("/data/Cisco-IOS-XE-native:native/vlan/configuration-entry=%v", d.Get("vlan_id").(int)),
+		fmt.Sprintf("/data/Cisco-IOS-XE-native:native/vlan/vlan-list=%v", d.Get("vlan_id").(int)),
+		fmt.Sprintf("/data/Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn-instance/evpn/instance/instance=%v", d.Get("evi").(int)),
+	)
+}
+
+// resourceCiscoNativeL2vniSteps returns the objects of the L2VNI in the order they are created
+func (*providerClient) resourceCiscoNativeL2vniSteps(d *schema.ResourceData) []payloadStep {
+	vlanID := d.Get("vlan_id").(int)
+	vni := d.Get("vni").(int)
+	evi := d.Get("evi").(int)
+	gateway := d.Get("gateway").([]interface{})
+
+	instance := &evpn_instance.CiscoIOSXEL2VpnInstanceInstance{}
+	instance.EvpnInstanceNum = evi
+	instance.VlanBased.Encapsulation = "vxlan"
+	if d.Get("replication_type").(string) == "static" {
+		instance.VlanBased.ReplicationType.Static = null()
+	} else {
+		instance.VlanBased.ReplicationType.Ingress = null()
+	}
+	instance.VlanBased.Rd.RdValue = d.Get("rd").(string)
+	instance.VlanBased.RouteTarget.Both.RtValue = d.Get("rt").(string)
+	instance.VlanBased.DefaultGateway.Advertise = "disable"
+	if len(gateway) > 0 {
+		instance.VlanBased.DefaultGateway.Advertise = "enable"
+	}
+	instances := &evpn_instance.CiscoIOSXEL2VpnInstanceCiscoIOSXEL2VpnInstanceEvpn{}
+	instances.CiscoIOSXEL2VpnInstance.Instance = append(instances.CiscoIOSXEL2VpnInstance.Instance, *instance)
+
+	vlans := &vlan.CiscoIOSXENativeVlans{}
+	vlans.CiscoIOSXENativeVlan.CiscoIOSXEVlanConfigurationEntry = append(vlans.CiscoIOSXENativeVlan.CiscoIOSXEVlanConfigurationEntry, vlan.CiscoIOSXEVlanConfigurationEntry{
+		VlanID: fmt.Sprintf("%v", vlanID),
+		Member: vlan.CiscoIOSXEVlanConfigurationEntryMember{
+			EvpnInstance: &vlan.CiscoIOSXEVlanConfigurationEntryEvpnInstance{
+				EvpnInstance: evi,
+				Vni:          vni,
+			},
+		},
+	})
+	vlanList := vlan.CiscoIOSXEVlanVlanList{ID: vlanID, Name: d.Get("name").(string)}
+	if vlanList.Name == "ManagedByTerraform" {
+		vlanList.Name = fmt.Sprintf("%v_%v", vlanList.Name, vlanID)
+	}
+	vlans.CiscoIOSXENativeVlan.CiscoIOSXEVlanVlanList = append(vlans.CiscoIOSXENativeVlan.CiscoIOSXEVlanVlanList, vlanList)
+
+	member := nve.CiscoIOSXENativeNveVni{VniRange: fmt.Sprintf("%v", vni)}
+	if d.Get("replication_type").(string) == "static" {
+		member.McastGroup = &nve.CiscoIOSXENativeNveMcastGroup{MulticastGroupMin: d.Get("multicast_group").(string)}
+	} else {
+		member.IrCpConfig = &nve.CiscoIOSXENativeNveIrCpConfig{IngressReplication: null()}
+	}
+	nveData := nve.CiscoIOSXENativeNve{Name: 1}
+	nveData.Member.Vni = append(nveData.Member.Vni, member)
+	nves := &nve.CiscoIOSXENativeNves{}
+	nves.CiscoIOSXENativeNve = append(nves.CiscoIOSXENativeNve, nveData)
+
+	steps := []payloadStep{
+		newPayloadStep("/data/Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn-instance/evpn/instance", instances),
+		newPayloadStep("/data/Cisco-IOS-XE-native:native/vlan", vlans),
+		newPayloadStep("/data/Cisco-IOS-XE-native:native/interface/nve", nves),
+	}
+
+	if len(gateway) > 0 {
+		gw := gateway[0].(map[string]interface{})
+		sviData := svi.CiscoIOSXENativeSvi{
+			Name: vlanID,
+			Vrf:  &svi.CiscoIOSXENativeVlanVrf{Forwarding: gw["vrf"].(string)},
+		}
+		sviData.IP.Address = &svi.CiscoIOSXENativeVlanAddress{
+			Primary: svi.CiscoIOSXENativeVlanPrimary{
+				Address: gw["ipv4_address"].(string),
+				Mask:    gw["ipv4_mask"].(string),
+			},
+		}
+		svis := &svi.CiscoIOSXENativeSvis{}
+		svis.CiscoIOSXENativeVlan = append(svis.CiscoIOSXENativeVlan, sviData)
+		steps = append(steps, newPayloadStep("/data/Cisco-IOS-XE-native:native/interface/Vlan", svis))
+	}
+	return steps
+}
+
+func (*providerClient) resourceCiscoNativeL2vniState(id int, vlans *vlan.CiscoIOSXENativeVlans, instances *evpn_instance.CiscoIOSXEL2VpnInstanceCiscoIOSXEL2VpnInstanceEvpn, nves *nve.CiscoIOSXENativeNves, svis *svi.CiscoIOSXENativeSvis) map[string]interface{} {
+	var state map[string]interface{}
+
+	for _, v := range vlans.CiscoIOSXENativeVlan.CiscoIOSXEVlanConfigurationEntry {
+		if v.VlanID == fmt.Sprintf("%v", id) && v.Member.EvpnInstance != nil {
+			state = map[string]interface{}{
+				"evi": v.Member.EvpnInstance.EvpnInstance,
+				"vni": v.Member.EvpnInstance.Vni,
+			}
+		}
+	}
+	if state == nil {
+		return nil
+	}
+	state["name"] = ""
+	for _, v := range vlans.CiscoIOSXENativeVlan.CiscoIOSXEVlanVlanList {
+		if v.ID == id {
+			state["name"] = v.Name
+			if v.Name == fmt.Sprintf("ManagedByTerraform_%v", id) {
+				state["name"] = "ManagedByTerraform"
+			}
+		}
+	}
+
+	found := false
+	for _, i := range instances.CiscoIOSXEL2VpnInstance.Instance {
+		if i.EvpnInstanceNum != state["evi"] {
+			continue
+		}
+		found = true
+		state["replication_type"] = "ingress"
+		if i.VlanBased.ReplicationType.Static != nil {
+			state["replication_type"] = "static"
+		}
+		state["rd"] = i.VlanBased.Rd.RdValue
+		state["rt"] = i.VlanBased.RouteTarget.Both.RtValue
+	}
+	if !found {
+		return nil
+	}
+
+	found = false
+	state["multicast_group"] = ""
+	for _, n := range nves.CiscoIOSXENativeNve {
+		for _, v := range n.Member.Vni {
+			if v.VniRange != fmt.Sprintf("%v", state["vni"]) {
+				continue
+			}
+			found = true
+			if v.McastGroup != nil {
+				state["multicast_group"] = v.McastGroup.MulticastGroupMin
+			}
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	gateway := []interface{}{}
+	for _, v := range svis.CiscoIOSXENativeVlan {
+		if v.Name != id || v.IP.Address == nil {
+			continue
+		}
+		gw := map[string]interface{}{
+			"vrf":          "",
+			"ipv4_address": v.IP.Address.Primary.Address,
+			"ipv4_mask":    v.IP.Address.Primary.Mask,
+		}
+		if v.Vrf != nil {
+			gw["vrf"] = v.Vrf.Forwarding
+		}
+		gateway = append(gateway, gw)
+	}
+	state["gateway"] = gateway
+	return state
+}
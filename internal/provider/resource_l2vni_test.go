@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnL2vni(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	member := "Cisco-IOS-XE-native:native/interface/nve=1/member/vni=10201"
+	svi := "Cisco-IOS-XE-native:native/interface/Vlan=201"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckDevicesRemoved(f.Leafs, member),
+			testAccCheckDevicesRemoved(f.Leafs, "Cisco-IOS-XE-native:native/vlan/configuration-entry=201"),
+			testAccCheckDevicesRemoved(f.Leafs, "Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn-instance/evpn/instance/instance=201"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnL2vniConfig(f, "ingress", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_l2vni.test", "devices.#", "2"),
+					testAccCheckDevices(f.Leafs, "Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn-instance/evpn/instance/instance=201", `"rd-value":"1:201"`, `"advertise":"enable"`),
+					testAccCheckDevices(f.Leafs, "Cisco-IOS-XE-native:native/vlan/configuration-entry=201", `"vni":10201`, `"evpn-instance":201`),
+					testAccCheckDevices(f.Leafs, member, `"ingress-replication"`),
+					testAccCheckDevices(f.Leafs, "Cisco-IOS-XE-native:native/interface/nve=1", `"Loopback":200`),
+					testAccCheckDevices(f.Leafs, svi, `"forwarding":"green"`, `"10.201.0.1"`),
+					testAccCheckDevicesRemoved(f.Spines, member),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnL2vniConfig(f, "static", false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDevices(f.Leafs, member, `"multicast-group-min":"225.0.2.1"`),
+					testAccCheckDevices(f.Leafs, "Cisco-IOS-XE-native:native/l2vpn/evpn_cont/evpn-instance/evpn/instance/instance=201", `"static"`, `"advertise":"disable"`),
+					testAccCheckDevicesRemoved(f.Leafs, svi),
+				),
+			},
+		},
+	})
+}
+
+func testAccCiscoEvpnL2vniConfig(f *testAccFabric, replication string, gateway bool) string {
+	var group, gw string
+	if replication == "static" {
+		group = `multicast_group  = "225.0.2.1"`
+	}
+	if gateway {
+		gw = `
+  gateway {
+    vrf          = "green"
+    ipv4_address = "10.201.0.1"
+    ipv4_mask    = "255.255.255.0"
+  }`
+	}
+	return f.Config() + f.LoopbacksConfig(200) + fmt.Sprintf(`
+resource "ciscoevpn_nve" "test" {
+  depends_on       = [ciscoevpn_loopback.leaf0_200, ciscoevpn_loopback.leaf1_200]
+  roles            = ["leafs"]
+  source_interface = ciscoevpn_loopback.leaf0_200.interface_name
+}
+
+resource "ciscoevpn_l2vni" "test" {
+  depends_on       = [ciscoevpn_nve.test]
+  roles            = ["leafs"]
+  vlan_id          = 201
+  vni              = 10201
+  evi              = 201
+  rd               = "1:201"
+  rt               = "1:201"
+  replication_type = %q
+  %v
+  %v
+}
+`, replication, group, gw)
+}
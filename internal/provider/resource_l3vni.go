@@ -0,0 +1,429 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/bgp"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/nve"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/svi"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/vlan"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/vrf"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/iosxe"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
+)
+
+func resourceCiscoNativeL3vni() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Cisco L3VNI tenant (VRF, core VLAN and SVI, NVE member VNI and BGP VRF)",
+		CreateContext: transactional(resourceCiscoNativeL3vniCreate),
+		ReadContext:   resourceCiscoNativeL3vniRead,
+		UpdateContext: transactional(resourceCiscoNativeL3vniUpdate),
+		DeleteContext: transactional(resourceCiscoNativeL3vniDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativeL3vniImport,
+		},
+		CustomizeDiff: devicesCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"roles": {
+				Type:     schema.TypeList,
+				Required: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"vrf": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"rd": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"vlan_id": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(1, 4094),
+			},
+			"vni": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(1, 16777215),
+			},
+			"bgp_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"unnumbered": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"ipv4": {
+				Type:     schema.TypeBool,
+				Default:  true,
+				Optional: true,
+			},
+			"ipv6": {
+				Type:     schema.TypeBool,
+				Default:  true,
+				Optional: true,
+			},
+			"redistribute_connected": {
+				Type:     schema.TypeBool,
+				Default:  true,
+				Optional: true,
+			},
+			"redistribute_static": {
+				Type:     schema.TypeBool,
+				Default:  true,
+				Optional: true,
+			},
+			"devices":           devicesSchema(),
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
+		},
+	}
+}
+
+func resourceCiscoNativeL3vniCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco L3VNI CREATE")
+	var diags diag.Diagnostics
+	var err error
+
+	c, _ := meta.(*providerClient)
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "PATCH",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	devices := getDevices(d)
+	roles := d.Get("roles").([]interface{})
+	for _, role := range roles {
+		svc.Role = role.(string)
+		steps := c.resourceCiscoNativeL3vniSteps(d)
+		if svc.Config.Debug {
+			svc.Payload, svc.CLI = stepsPayload(steps)
+			debugPayload(fmt.Sprintf("l3vni_%v_%v", svc.Role, d.Get("vrf").(string)), svc)
+		}
+
+		err = c.applyRoleSteps(svc, steps, devices)
+		if err != nil {
+			setDevices(d, devices)
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(fmt.Sprintf("l3vni_%v", d.Get("vrf").(string)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativeL3vniRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco L3VNI READ")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	name := d.Get("vrf").(string)
+	vrfs, err := c.readRoles(ctx, d, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/vrf/definition=%v", name))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	vlans, err := c.readRoles(ctx, d, "/data/Cisco-IOS-XE-native:native/vlan")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	svis, err := c.readRoles(ctx, d, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v", d.Get("vlan_id").(int)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	nves, err := c.readRoles(ctx, d, "/data/Cisco-IOS-XE-native:native/interface/nve=1")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	bgps, err := c.readRoles(ctx, d, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v/address-family/with-vrf", d.Get("bgp_id").(int)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	states := make(map[string]map[string]interface{})
+	for host, body := range vrfs {
+		vrfData := &vrf.CiscoIOSXENativeVrf{}
+		if err = unmarshalBody(body, vrfData); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		vlanData := &vlan.CiscoIOSXENativeVlans{}
+		if err = unmarshalBody(vlans[host], vlanData); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		sviData := &svi.CiscoIOSXENativeSvis{}
+		if err = unmarshalBody(svis[host], sviData); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		nveData := &nve.CiscoIOSXENativeNves{}
+		if err = unmarshalBody(nves[host], nveData); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		bgpData := &bgp.CiscoIOSXEBgpWithVrfs{}
+		if err = unmarshalBody(bgps[host], bgpData); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+
+		state := c.resourceCiscoNativeL3vniState(d, vrfData, vlanData, sviData, nveData, bgpData)
+		if state == nil {
+			log.Printf("[DEBUG] L3VNI %v not found on: %v\n", name, host)
+			d.SetId("")
+			return diags
+		}
+		states[host] = state
+	}
+
+	if err = setDevicesDriftState(d, states); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativeL3vniUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco L3VNI UPDATE")
+	var diags diag.Diagnostics
+	var err error
+
+	c, _ := meta.(*providerClient)
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "PATCH",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	devices := getDevices(d)
+	if err = c.removeHosts(ctx, d, devices, c.resourceCiscoNativeL3vniPaths(d)...); err != nil {
+		setDevices(d, devices)
+		return diag.FromErr(err)
+	}
+
+	// PATCH merges, disabled address families and the SVI address are removed before the new config is applied
+	paths := []string{}
+	if d.HasChange("ipv4") && !d.Get("ipv4").(bool) {
+		paths = append(paths, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v/address-family/with-vrf/ipv4/unicast/vrf=%v", d.Get("bgp_id").(int), d.Get("vrf").(string)))
+	}
+	if d.HasChange("ipv6") && !d.Get("ipv6").(bool) {
+		paths = append(paths, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v/address-family/with-vrf/ipv6/unicast/vrf=%v", d.Get("bgp_id").(int), d.Get("vrf").(string)))
+	}
+	if d.HasChange("unnumbered") && d.Get("unnumbered").(string) == "" {
+		paths = append(paths, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v/ip/unnumbered", d.Get("vlan_id").(int)))
+	}
+
+	roles := d.Get("roles").([]interface{})
+	for _, role := range roles {
+		svc.Role = role.(string)
+		if len(paths) > 0 {
+			if err = c.deleteRoleSteps(svc, paths, devices); err != nil {
+				setDevices(d, devices)
+				return diag.FromErr(err)
+			}
+		}
+
+		steps := c.resourceCiscoNativeL3vniSteps(d)
+		if svc.Config.Debug {
+			svc.Payload, svc.CLI = stepsPayload(steps)
+			debugPayload(fmt.Sprintf("l3vni_%v_%v", svc.Role, d.Get("vrf").(string)), svc)
+		}
+
+		err = c.applyRoleSteps(svc, steps, devices)
+		if err != nil {
+			setDevices(d, devices)
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(fmt.Sprintf("l3vni_%v", d.Get("vrf").(string)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativeL3vniDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco L3VNI DELETE")
+	var err error
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "DELETE",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	devices := getDevices(d)
+	paths := c.resourceCiscoNativeL3vniPaths(d)
+	roles := d.Get("roles").([]interface{})
+	for _, role := range roles {
+		svc.Role = role.(string)
+		err = c.deleteRoleSteps(svc, paths, devices)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId("")
+	return diags
+}
+
+func resourceCiscoNativeL3vniImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco L3VNI IMPORT")
+	c, _ := meta.(*providerClient)
+	parts, err := importID(d.Id(), "<roles>:<bgp_id>:<vrf>:<vlan_id>")
+	if err != nil {
+		return nil, err
+	}
+	bgpID, err := importInt(parts[1], "bgp_id")
+	if err != nil {
+		return nil, err
+	}
+	vlanID, err := importInt(parts[3], "vlan_id")
+	if err != nil {
+		return nil, err
+	}
+	if err = c.importRoles(d, parts[0]); err != nil {
+		return nil, err
+	}
+	d.Set("bgp_id", bgpID)
+	d.Set("vrf", parts[2])
+	d.Set("vlan_id", vlanID)
+	d.SetId(fmt.Sprintf("l3vni_%v", parts[2]))
+	return importRead(ctx, d, meta, resourceCiscoNativeL3vniRead)
+}
+
+// resourceCiscoNativeL3vniPaths returns the objects of the tenant in the order they are deleted
+func (*providerClient) resourceCiscoNativeL3vniPaths(d *schema.ResourceData) []string {
+	return []string{
+		fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v/address-family/with-vrf/ipv4/unicast/vrf=%v", d.Get("bgp_id").(int), d.Get("vrf").(string)),
+		fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v/address-family/with-vrf/ipv6/unicast/vrf=%v", d.Get("bgp_id").(int), d.Get("vrf").(string)),
+		fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/nve=1/member-in-one-line/member/vni=%v", d.Get("vni").(int)),
+		fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v", d.Get("vlan_id").(int)),
+		fmt.Sprintf("/data/Cisco-IOS-XE-native:native/vlan/configuration-entry=%v", d.Get("vlan_id").(int)),
+		fmt.Sprintf("/data/Cisco-IOS-XE-native:native/vlan/vlan-list=%v", d.Get("vlan_id").(int)),
+		fmt.Sprintf("/data/Cisco-IOS-XE-native:native/vrf/definition=%v", d.Get("vrf").(string)),
+	}
+}
+
+// resourceCiscoNativeL3vniSteps returns the objects of the tenant in the order they are created
+func (c *providerClient) resourceCiscoNativeL3vniSteps(d *schema.ResourceData) []payloadStep {
+	name := d.Get("vrf").(string)
+	vlanID := d.Get("vlan_id").(int)
+	vni := d.Get("vni").(int)
+
+	vlans := &vlan.CiscoIOSXENativeVlans{}
+	vlans.CiscoIOSXENativeVlan.CiscoIOSXEVlanConfigurationEntry = append(vlans.CiscoIOSXENativeVlan.CiscoIOSXEVlanConfigurationEntry, vlan.CiscoIOSXEVlanConfigurationEntry{
+		VlanID: fmt.Sprintf("%v", vlanID),
+		Member: vlan.CiscoIOSXEVlanConfigurationEntryMember{Vni: vni},
+	})
+	vlans.CiscoIOSXENativeVlan.CiscoIOSXEVlanVlanList = append(vlans.CiscoIOSXENativeVlan.CiscoIOSXEVlanVlanList, vlan.CiscoIOSXEVlanVlanList{
+		ID:   vlanID,
+		Name: fmt.Sprintf("L3VNI_%v", name),
+	})
+
+	sviData := svi.CiscoIOSXENativeSvi{
+		Name:        vlanID,
+		Description: fmt.Sprintf("L3VNI %v", name),
+		Vrf:         &svi.CiscoIOSXENativeVlanVrf{Forwarding: name},
+	}
+	sviData.IP.Unnumbered = d.Get("unnumbered").(string)
+	svis := &svi.CiscoIOSXENativeSvis{}
+	svis.CiscoIOSXENativeVlan = append(svis.CiscoIOSXENativeVlan, sviData)
+
+	nveData := nve.CiscoIOSXENativeNve{Name: 1}
+	nveData.MemberInOneLine.Member.Vni = append(nveData.MemberInOneLine.Member.Vni, nve.CiscoIOSXENativeNveVni{
+		VniRange: fmt.Sprintf("%v", vni),
+		Vrf:      name,
+	})
+	nves := &nve.CiscoIOSXENativeNves{}
+	nves.CiscoIOSXENativeNve = append(nves.CiscoIOSXENativeNve, nveData)
+
+	bgpData := c.CiscoIOSXENativeVrfBgp(d)
+	b, _ := json.MarshalIndent(bgpData, "", "\t")
+
+	return []payloadStep{
+		newPayloadStep("/data/Cisco-IOS-XE-native:native/vrf/definition", c.vrfDefinitionData(name, d)),
+		newPayloadStep("/data/Cisco-IOS-XE-native:native/vlan", vlans),
+		newPayloadStep("/data/Cisco-IOS-XE-native:native/interface/Vlan", svis),
+		newPayloadStep("/data/Cisco-IOS-XE-native:native/interface/nve", nves),
+		{
+			Path:    fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/bgp=%v/address-family/with-vrf", d.Get("bgp_id").(int)),
+			Payload: string(b),
+			CLI:     bgpData.CLI(d.Get("bgp_id").(int)),
+		},
+	}
+}
+
+func (c *providerClient) resourceCiscoNativeL3vniState(d *schema.ResourceData, vrfs *vrf.CiscoIOSXENativeVrf, vlans *vlan.CiscoIOSXENativeVlans, svis *svi.CiscoIOSXENativeSvis, nves *nve.CiscoIOSXENativeNves, bgps *bgp.CiscoIOSXEBgpWithVrfs) map[string]interface{} {
+	name := d.Get("vrf").(string)
+	vlanID := d.Get("vlan_id").(int)
+	if len(vrfs.CiscoIOSXENativeDefinition) == 0 {
+		return nil
+	}
+	state := c.CiscoIOSXENativeVrfState(vrfs)
+
+	found := false
+	for _, v := range vlans.CiscoIOSXENativeVlan.CiscoIOSXEVlanConfigurationEntry {
+		if v.VlanID == fmt.Sprintf("%v", vlanID) {
+			found = true
+			state["vni"] = v.Member.Vni
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	found = false
+	for _, v := range svis.CiscoIOSXENativeVlan {
+		if v.Name == vlanID {
+			found = true
+			state["unnumbered"] = v.IP.Unnumbered
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	found = false
+	for _, n := range nves.CiscoIOSXENativeNve {
+		for _, v := range n.MemberInOneLine.Member.Vni {
+			if v.Vrf == name && v.VniRange == fmt.Sprintf("%v", state["vni"]) {
+				found = true
+			}
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	bgpState := c.CiscoIOSXENativeVrfBgpState(d, bgps)
+	if bgpState == nil {
+		return nil
+	}
+	state["ipv4"] = state["ipv4"].(bool) && bgpState["ipv4"].(bool)
+	state["ipv6"] = state["ipv6"].(bool) && bgpState["ipv6"].(bool)
+	state["redistribute_connected"] = bgpState["redistribute_connected"]
+	state["redistribute_static"] = bgpState["redistribute_static"]
+	return state
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnL3vni(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	member := "Cisco-IOS-XE-native:native/interface/nve=1/member-in-one-line/member/vni=50001"
+	svi := "Cisco-IOS-XE-native:native/interface/Vlan=901"
+	ipv6 := "Cisco-IOS-XE-native:native/router/bgp=65534/address-family/with-vrf/ipv6/unicast/vrf=tenant1"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckDevicesRemoved(f.Leafs, member),
+			testAccCheckDevicesRemoved(f.Leafs, svi),
+			testAccCheckDevicesRemoved(f.Leafs, "Cisco-IOS-XE-native:native/vlan/configuration-entry=901"),
+			testAccCheckDevicesRemoved(f.Leafs, "Cisco-IOS-XE-native:native/vrf/definition=tenant1"),
+			testAccCheckDevicesRemoved(f.Leafs, "Cisco-IOS-XE-native:native/router/bgp=65534/address-family/with-vrf/ipv4/unicast/vrf=tenant1"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnL3vniConfig(f, false, `"Loopback100"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_l3vni.test", "devices.#", "2"),
+					testAccCheckDevices(f.Leafs, "Cisco-IOS-XE-native:native/vrf/definition=tenant1", `"rd":"1:901"`, `"stitching"`),
+					testAccCheckDevices(f.Leafs, "Cisco-IOS-XE-native:native/vlan/configuration-entry=901", `"vni":50001`),
+					testAccCheckDevices(f.Leafs, svi, `"forwarding":"tenant1"`, `"unnumbered":"Loopback100"`),
+					testAccCheckDevices(f.Leafs, member, `"vrf":"tenant1"`),
+					testAccCheckDevices(f.Leafs, "Cisco-IOS-XE-native:native/router/bgp=65534/address-family/with-vrf/ipv4/unicast/vrf=tenant1", `"evpn"`),
+					testAccCheckDevicesRemoved(f.Leafs, ipv6),
+					testAccCheckDevicesRemoved(f.Spines, member),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnL3vniConfig(f, true, "null"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDevices(f.Leafs, ipv6, `"evpn"`),
+					testAccCheckDevicesRemoved(f.Leafs, svi+"/ip/unnumbered"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCiscoEvpnL3vniConfig(f *testAccFabric, ipv6 bool, unnumbered string) string {
+	return f.Config() + f.LoopbacksConfig(100) + fmt.Sprintf(`
+resource "ciscoevpn_bgp_system" "test" {
+  depends_on = [ciscoevpn_loopback.leaf0_100, ciscoevpn_loopback.leaf1_100]
+  roles      = ["leafs"]
+  router_id  = ciscoevpn_loopback.leaf0_100.interface_name
+  bgp_id     = 65534
+}
+
+resource "ciscoevpn_l3vni" "test" {
+  roles      = ["leafs"]
+  vrf        = "tenant1"
+  rd         = "1:901"
+  vlan_id    = 901
+  vni        = 50001
+  bgp_id     = ciscoevpn_bgp_system.test.bgp_id
+  unnumbered = %v
+  ipv6       = %v
+}
+`, unnumbered, ipv6)
+}
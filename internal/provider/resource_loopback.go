@@ -22,6 +22,9 @@ func resourceCiscoNativeLoopbackInterface() *schema.Resource {
 		ReadContext:   resourceCiscoNativeLoopbackInterfaceRead,
 		UpdateContext: resourceCiscoNativeLoopbackInterfaceUpdate,
 		DeleteContext: resourceCiscoNativeLoopbackInterfaceDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativeLoopbackInterfaceImport,
+		},
 		Schema: map[string]*schema.Schema{
 			"host": {
 				Type:         schema.TypeString,
@@ -57,6 +60,8 @@ func resourceCiscoNativeLoopbackInterface() *schema.Resource {
 				Computed:    true,
 				Description: `Name of the interface`,
 			},
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
 		},
 	}
 }
@@ -68,10 +73,11 @@ func resourceCiscoNativeLoopbackInterfaceCreate(ctx context.Context, d *schema.R
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Path:     "/data/Cisco-IOS-XE-native:native/interface/Loopback",
-		Provider: c.Provider,
-		Device:   d.Get("host").(string),
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    "/data/Cisco-IOS-XE-native:native/interface/Loopback",
+		Config:  c.Config,
+		Device:  d.Get("host").(string),
 	}
 
 	data := c.resourceCiscoNativeLoopbackInterfaceData(d)
@@ -83,14 +89,18 @@ func resourceCiscoNativeLoopbackInterfaceCreate(ctx context.Context, d *schema.R
 	if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 		svc.Payload = string(b)
 	}
-	if svc.Provider.Get("debug").(bool) {
-		debugJson(fmt.Sprintf("loopback_interface_%v", svc.Device), svc.Payload)
+	svc.CLI = data.CLI()
+	if svc.Config.Debug {
+		debugPayload(fmt.Sprintf("loopback_interface_%v", svc.Device), svc)
 	}
 
 	_, err = iosxe.SingleSession(svc)
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	if err = c.setHostPreview(d, svc); err != nil {
+		return diag.FromErr(err)
+	}
 
 	d.Set("interface_name", fmt.Sprintf("Loopback%v", d.Get("loopback_id")))
 	d.SetId(fmt.Sprintf("%v", d.Get("loopback_id").(int)))
@@ -98,8 +108,30 @@ func resourceCiscoNativeLoopbackInterfaceCreate(ctx context.Context, d *schema.R
 }
 
 func resourceCiscoNativeLoopbackInterfaceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	log.Println("[DEBUG] Cisco Loopback READ") // TODO
+	log.Println("[DEBUG] Cisco Loopback READ")
 	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	body, err := c.readHost(ctx, d, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Loopback=%v", d.Get("loopback_id").(int)))
+	data := &loopback.CiscoIOSXENativeLoopbackInterface{}
+	if err == nil {
+		err = unmarshalBody(body, data)
+	}
+	if errors.Is(err, iosxe.ErrNotFound) || (err == nil && len(data.CiscoIOSXENativeLoopback) == 0) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	state := c.resourceCiscoNativeLoopbackInterfaceState(data)
+	if err = setDriftState(d, map[string]map[string]interface{}{d.Get("host").(string): state}); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = setHostCli(d, c.resourceCiscoNativeLoopbackInterfaceData(d).CLI()); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
@@ -116,10 +148,11 @@ func resourceCiscoNativeLoopbackInterfaceUpdate(ctx context.Context, d *schema.R
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Path:     "/data/Cisco-IOS-XE-native:native/interface/Loopback",
-		Provider: c.Provider,
-		Device:   d.Get("host").(string),
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    "/data/Cisco-IOS-XE-native:native/interface/Loopback",
+		Config:  c.Config,
+		Device:  d.Get("host").(string),
 	}
 
 	data := c.resourceCiscoNativeLoopbackInterfaceData(d)
@@ -131,14 +164,18 @@ func resourceCiscoNativeLoopbackInterfaceUpdate(ctx context.Context, d *schema.R
 	if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 		svc.Payload = string(b)
 	}
-	if svc.Provider.Get("debug").(bool) {
-		debugJson(fmt.Sprintf("loopback_interface_%v", svc.Device), svc.Payload)
+	svc.CLI = data.CLI()
+	if svc.Config.Debug {
+		debugPayload(fmt.Sprintf("loopback_interface_%v", svc.Device), svc)
 	}
 
 	_, err = iosxe.SingleSession(svc)
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	if err = c.setHostPreview(d, svc); err != nil {
+		return diag.FromErr(err)
+	}
 
 	d.Set("interface_name", fmt.Sprintf("Loopback%v", d.Get("loopback_id")))
 	d.SetId(fmt.Sprintf("%v", d.Get("loopback_id").(int)))
@@ -151,10 +188,11 @@ func resourceCiscoNativeLoopbackInterfaceDelete(ctx context.Context, d *schema.R
 	var err error
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "DELETE",
-		Path:     fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Loopback=%v", d.Get("loopback_id").(int)),
-		Provider: c.Provider,
-		Device:   d.Get("host").(string),
+		Context: ctx,
+		Method:  "DELETE",
+		Path:    fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Loopback=%v", d.Get("loopback_id").(int)),
+		Config:  c.Config,
+		Device:  d.Get("host").(string),
 	}
 
 	_, err = iosxe.SingleSession(svc)
@@ -165,6 +203,22 @@ func resourceCiscoNativeLoopbackInterfaceDelete(ctx context.Context, d *schema.R
 	return diags
 }
 
+func resourceCiscoNativeLoopbackInterfaceImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco Loopback IMPORT")
+	parts, err := importHostID(d.Id(), "<host>:<loopback_id>")
+	if err != nil {
+		return nil, err
+	}
+	id, err := importInt(parts[1], "loopback_id")
+	if err != nil {
+		return nil, err
+	}
+	d.Set("host", parts[0])
+	d.Set("loopback_id", id)
+	d.SetId(fmt.Sprintf("%v", id))
+	return importRead(ctx, d, meta, resourceCiscoNativeLoopbackInterfaceRead)
+}
+
 func (*providerClient) resourceCiscoNativeLoopbackInterfaceData(d *schema.ResourceData) *loopback.CiscoIOSXENativeLoopbackInterface {
 	data := &loopback.CiscoIOSXENativeLoopbackInterface{}
 	lp := &loopback.CiscoIOSXENativeLoopback{
@@ -179,3 +233,14 @@ func (*providerClient) resourceCiscoNativeLoopbackInterfaceData(d *schema.Resour
 	data.CiscoIOSXENativeLoopback = append(data.CiscoIOSXENativeLoopback, *lp)
 	return data
 }
+
+func (*providerClient) resourceCiscoNativeLoopbackInterfaceState(data *loopback.CiscoIOSXENativeLoopbackInterface) map[string]interface{} {
+	lp := data.CiscoIOSXENativeLoopback[0]
+	return map[string]interface{}{
+		"description":    lp.Description,
+		"ipv4_address":   lp.IP.Address.Primary.Address,
+		"ipv4_mask":      lp.IP.Address.Primary.Mask,
+		"pim_sm":         lp.IP.Pim.CiscoIOSXEMulticastPimModeChoiceCfg.SparseMode != nil,
+		"interface_name": fmt.Sprintf("Loopback%v", lp.Name),
+	}
+}
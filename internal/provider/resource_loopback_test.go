@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/simulator"
+)
+
+func TestAccCiscoEvpnLoopback(t *testing.T) {
+	f := newTestAccFabric(t, 1, 1)
+	leaf := []*simulator.Device{f.Leafs[0]}
+	path := "Cisco-IOS-XE-native:native/interface/Loopback=100"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDevicesRemoved(leaf, path),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnLoopbackConfig(f, "100.119.11.11"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_loopback.test", "interface_name", "Loopback100"),
+					resource.TestCheckResourceAttr("ciscoevpn_loopback.test", "ipv4_address", "100.119.11.11"),
+					testAccCheckDevices(leaf, path, `"address":"100.119.11.11"`, `"sparse-mode"`),
+					testAccCheckDevicesRemoved(f.Spines, path),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnLoopbackConfig(f, "100.119.11.12"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_loopback.test", "ipv4_address", "100.119.11.12"),
+					resource.TestMatchResourceAttr("ciscoevpn_loopback.test", "cli_preview."+f.Leafs[0].Host(), regexp.MustCompile(`interface Loopback100\n.*\n ip address 100.119.11.12 255.255.255.255`)),
+					testAccCheckDevices(leaf, path, `"address":"100.119.11.12"`),
+				),
+			},
+			{
+				// A change on the device shows up in the plan
+				PreConfig: func() {
+					if err := f.Leafs[0].Set(path+"/description", `{"Cisco-IOS-XE-native:description":"changed"}`); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             testAccCiscoEvpnLoopbackConfig(f, "100.119.11.12"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccCiscoEvpnLoopbackConfig(f, "100.119.11.12"),
+				Check:  testAccCheckDevices(leaf, path, `"description":"Managed by Terraform (ciscoevpn)"`),
+			},
+			{
+				ResourceName:      "ciscoevpn_loopback.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%v:100", f.Leafs[0].Host()),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// TestAccCiscoEvpnLoopbackLocked checks that a locked database is retried
+func TestAccCiscoEvpnLoopbackLocked(t *testing.T) {
+	f := newTestAccFabric(t, 1, 1)
+	leaf := []*simulator.Device{f.Leafs[0]}
+	f.Leafs[0].LockDatabase(2)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnLoopbackConfig(f, "100.119.11.11"),
+				Check:  testAccCheckDevices(leaf, "Cisco-IOS-XE-native:native/interface/Loopback=100", `"address":"100.119.11.11"`),
+			},
+		},
+	})
+}
+
+func testAccCiscoEvpnLoopbackConfig(f *testAccFabric, address string) string {
+	return f.Config() + fmt.Sprintf(`
+resource "ciscoevpn_loopback" "test" {
+  host         = %q
+  loopback_id  = 100
+  ipv4_address = %q
+  ipv4_mask    = "255.255.255.255"
+}
+`, f.Leafs[0].Host(), address)
+}
@@ -0,0 +1,498 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/loopback"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/iosxe"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
+)
+
+func resourceCiscoNativeMulticastUnderlay() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Cisco multicast underlay, all roles get the RP address and PIM on the fabric links, the rp_roles also get the anycast-RP Loopback and MSDP between them",
+		CreateContext: transactional(resourceCiscoNativeMulticastUnderlayCreate),
+		ReadContext:   resourceCiscoNativeMulticastUnderlayRead,
+		UpdateContext: transactional(resourceCiscoNativeMulticastUnderlayUpdate),
+		DeleteContext: transactional(resourceCiscoNativeMulticastUnderlayDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativeMulticastUnderlayImport,
+		},
+		CustomizeDiff: customdiff.All(devicesCustomizeDiff, resourceCiscoNativeMulticastUnderlayCustomizeDiff),
+		Schema: map[string]*schema.Schema{
+			"roles": {
+				Type:     schema.TypeList,
+				Required: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"rp_roles": {
+				Type:     schema.TypeList,
+				Required: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"rp_address": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.IsIPv4Address,
+			},
+			"anycast_rp_loopback": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validateLoopback(),
+				DiffSuppressFunc: suppressLoopbackDiff,
+			},
+			"msdp_loopback": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateLoopback(),
+				DiffSuppressFunc: suppressLoopbackDiff,
+			},
+			"interfaces": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateInterface(),
+				},
+			},
+			"devices":           devicesSchema(),
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
+		},
+	}
+}
+
+// resourceCiscoNativeMulticastUnderlayCustomizeDiff requires the rp_roles to be part of the roles
+func resourceCiscoNativeMulticastUnderlayCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("roles") || !d.NewValueKnown("rp_roles") {
+		return nil
+	}
+	roles := make(map[string]bool)
+	for _, role := range d.Get("roles").([]interface{}) {
+		roles[role.(string)] = true
+	}
+	for _, role := range d.Get("rp_roles").([]interface{}) {
+		if !roles[role.(string)] {
+			return fmt.Errorf("rp_roles %v is not part of roles", role)
+		}
+	}
+	return nil
+}
+
+func resourceCiscoNativeMulticastUnderlayCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco Multicast Underlay CREATE")
+	var diags diag.Diagnostics
+	var err error
+
+	c, _ := meta.(*providerClient)
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "PATCH",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	devices := getDevices(d)
+	roles := d.Get("roles").([]interface{})
+	for _, role := range roles {
+		svc.Role = role.(string)
+		if diags = c.resourceCiscoNativeMulticastUnderlayApply(svc, d, devices); diags.HasError() {
+			setDevices(d, devices)
+			return diags
+		}
+	}
+
+	d.SetId(fmt.Sprintf("multicast_%v", d.Get("rp_address").(string)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativeMulticastUnderlayRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco Multicast Underlay READ")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	bodies, err := c.readRoles(ctx, d, "/data/Cisco-IOS-XE-native:native/ip/Cisco-IOS-XE-multicast:pim/rp-address")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	routings, err := c.readRoles(ctx, d, "/data/Cisco-IOS-XE-native:native/ip/Cisco-IOS-XE-multicast:multicast-routing")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	msdps, err := c.readRoles(ctx, d, "/data/Cisco-IOS-XE-native:native/ip/Cisco-IOS-XE-multicast:msdp")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	anycasts, err := c.readRoles(ctx, d, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Loopback=%v", loopbackId(d.Get("anycast_rp_loopback").(string))))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// PIM config of the fabric links per host
+	pims := make(map[string]map[string]bool)
+	for _, v := range d.Get("interfaces").([]interface{}) {
+		name := v.(string)
+		data, err := c.readRoles(ctx, d, interfacePath(name)+"/ip/pim")
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		for host, body := range data {
+			pim := &loopback.CiscoIOSXEInterfacePims{}
+			if err = unmarshalBody(body, pim); err != nil {
+				if errors.Is(err, iosxe.ErrNotFound) {
+					continue
+				}
+				return diag.FromErr(err)
+			}
+			if pims[host] == nil {
+				pims[host] = make(map[string]bool)
+			}
+			pims[host][name] = pim.Pim.CiscoIOSXEMulticastPimModeChoiceCfg.SparseMode != nil
+		}
+	}
+
+	rps := c.resourceCiscoNativeMulticastUnderlayRpHosts(d)
+	states := make(map[string]map[string]interface{})
+	for host, body := range bodies {
+		data := &loopback.CiscoIOSXEMulticastRpAddresses{}
+		if err = unmarshalBody(body, data); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		if data.RpAddress.Address == "" {
+			log.Printf("[DEBUG] RP address not found on: %v\n", host)
+			d.SetId("")
+			return diags
+		}
+		state := map[string]interface{}{
+			"rp_address": data.RpAddress.Address,
+		}
+		routing := &loopback.CiscoIOSXEMulticastRoutings{}
+		if err = unmarshalBody(routings[host], routing); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		if routing.MulticastRouting == nil {
+			// without multicast-routing the RP isn't used
+			state["rp_address"] = ""
+		}
+		if rps[host] {
+			msdp := &loopback.CiscoIOSXEMulticastMsdps{}
+			lp := &loopback.CiscoIOSXENativeLoopbackInterface{}
+			if err = unmarshalBody(msdps[host], msdp); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+				return diag.FromErr(err)
+			}
+			if err = unmarshalBody(anycasts[host], lp); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+				return diag.FromErr(err)
+			}
+			c.resourceCiscoNativeMulticastUnderlayRpState(d, state, &msdp.Msdp, lp)
+		}
+		links := []interface{}{}
+		for _, v := range d.Get("interfaces").([]interface{}) {
+			if pims[host][v.(string)] {
+				links = append(links, v)
+			}
+		}
+		state["interfaces"] = links
+		states[host] = state
+	}
+
+	if err = setDevicesDriftState(d, states); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativeMulticastUnderlayUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco Multicast Underlay UPDATE")
+	var diags diag.Diagnostics
+	var err error
+
+	c, _ := meta.(*providerClient)
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "PATCH",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	devices := getDevices(d)
+	if err = c.removeHosts(ctx, d, devices, c.resourceCiscoNativeMulticastUnderlayPaths(d, true)...); err != nil {
+		setDevices(d, devices)
+		return diag.FromErr(err)
+	}
+
+	// PATCH merges, removed interfaces and RPs as well as the MSDP peers are deleted before the new config is applied
+	paths := []string{}
+	current := make(map[string]bool)
+	for _, v := range d.Get("interfaces").([]interface{}) {
+		current[v.(string)] = true
+	}
+	old, _ := d.GetChange("interfaces")
+	for _, v := range old.([]interface{}) {
+		if !current[v.(string)] {
+			paths = append(paths, interfacePath(v.(string))+"/ip/pim")
+		}
+	}
+	rpPaths := []string{}
+	if d.HasChanges("rp_roles", "msdp_loopback") {
+		rpPaths = append(rpPaths, "/data/Cisco-IOS-XE-native:native/ip/Cisco-IOS-XE-multicast:msdp")
+	}
+	oldRoles, _ := d.GetChange("rp_roles")
+	rpRoles := make(map[string]bool)
+	for _, role := range d.Get("rp_roles").([]interface{}) {
+		rpRoles[role.(string)] = true
+	}
+	removedRps := make(map[string]bool)
+	for _, role := range oldRoles.([]interface{}) {
+		removedRps[role.(string)] = !rpRoles[role.(string)]
+	}
+
+	roles := d.Get("roles").([]interface{})
+	for _, role := range roles {
+		svc.Role = role.(string)
+		rolePaths := paths
+		if removedRps[svc.Role] {
+			rolePaths = append(append([]string{}, paths...), c.resourceCiscoNativeMulticastUnderlayRpPaths(d)...)
+		} else if rpRoles[svc.Role] {
+			rolePaths = append(append([]string{}, paths...), rpPaths...)
+		}
+		if len(rolePaths) > 0 {
+			if err = c.deleteRoleSteps(svc, rolePaths, devices); err != nil {
+				setDevices(d, devices)
+				return diag.FromErr(err)
+			}
+		}
+		if diags = c.resourceCiscoNativeMulticastUnderlayApply(svc, d, devices); diags.HasError() {
+			setDevices(d, devices)
+			return diags
+		}
+	}
+
+	d.SetId(fmt.Sprintf("multicast_%v", d.Get("rp_address").(string)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativeMulticastUnderlayDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco Multicast Underlay DELETE")
+	var err error
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "DELETE",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	devices := getDevices(d)
+	rpRoles := make(map[string]bool)
+	for _, role := range d.Get("rp_roles").([]interface{}) {
+		rpRoles[role.(string)] = true
+	}
+	roles := d.Get("roles").([]interface{})
+	for _, role := range roles {
+		svc.Role = role.(string)
+		err = c.deleteRoleSteps(svc, c.resourceCiscoNativeMulticastUnderlayPaths(d, rpRoles[svc.Role]), devices)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId("")
+	return diags
+}
+
+func resourceCiscoNativeMulticastUnderlayImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco Multicast Underlay IMPORT")
+	c, _ := meta.(*providerClient)
+	parts, err := importID(d.Id(), "<roles>:<rp_roles>:<rp_address>:<anycast_rp_loopback>:<msdp_loopback>")
+	if err != nil {
+		return nil, err
+	}
+	if err = c.importRoles(d, parts[0]); err != nil {
+		return nil, err
+	}
+	rpRoles := []interface{}{}
+	for _, role := range strings.Split(parts[1], ",") {
+		rpRoles = append(rpRoles, role)
+	}
+	d.Set("rp_roles", rpRoles)
+	d.Set("rp_address", parts[2])
+	d.Set("anycast_rp_loopback", parts[3])
+	d.Set("msdp_loopback", parts[4])
+	d.SetId(fmt.Sprintf("multicast_%v", parts[2]))
+	return importRead(ctx, d, meta, resourceCiscoNativeMulticastUnderlayRead)
+}
+
+// resourceCiscoNativeMulticastUnderlayApply builds the payloads of each host in the role, RPs peer with the MSDP Loopback of the other RPs
+func (c *providerClient) resourceCiscoNativeMulticastUnderlayApply(svc *service.Client, d *schema.ResourceData, devices map[string]*deviceState) diag.Diagnostics {
+	hostSteps := make(map[string][]payloadStep)
+	if !rpRole(d, svc.Role) {
+		for _, host := range iosxe.HostRoles(c.Devices.List(), svc.Role) {
+			hostSteps[host.(string)] = c.resourceCiscoNativeMulticastUnderlaySteps(d, "", nil)
+		}
+	} else {
+		id, diags := loopbackNumber(d, "msdp_loopback", c.roleTarget(svc.Role))
+		if diags.HasError() {
+			return diags
+		}
+		addresses := make(map[string]string)
+		for _, role := range d.Get("rp_roles").([]interface{}) {
+			roleAddresses, err := c.loopbackAddresses(svc.Context, role.(string), id)
+			if err != nil {
+				return attrDiag("msdp_loopback", c.roleTarget(role.(string)), err)
+			}
+			for host, address := range roleAddresses {
+				addresses[host] = address
+			}
+		}
+		for _, host := range iosxe.HostRoles(c.Devices.List(), svc.Role) {
+			peers := []string{}
+			for peer, address := range addresses {
+				if peer != host.(string) {
+					peers = append(peers, address)
+				}
+			}
+			sort.Strings(peers)
+			hostSteps[host.(string)] = c.resourceCiscoNativeMulticastUnderlaySteps(d, host.(string), peers)
+		}
+	}
+
+	if svc.Config.Debug {
+		for host, steps := range hostSteps {
+			svc.Payload, svc.CLI = stepsPayload(steps)
+			debugPayload(fmt.Sprintf("multicast_underlay_%v_%v", svc.Role, host), svc)
+		}
+	}
+	if err := c.applyHostSteps(svc, hostSteps, devices); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func rpRole(d *schema.ResourceData, role string) bool {
+	for _, r := range d.Get("rp_roles").([]interface{}) {
+		if r.(string) == role {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceCiscoNativeMulticastUnderlayRpHosts returns the hosts of the rp_roles
+func (c *providerClient) resourceCiscoNativeMulticastUnderlayRpHosts(d *schema.ResourceData) map[string]bool {
+	hosts := make(map[string]bool)
+	for _, role := range d.Get("rp_roles").([]interface{}) {
+		for _, host := range iosxe.HostRoles(c.Devices.List(), role.(string)) {
+			hosts[host.(string)] = true
+		}
+	}
+	return hosts
+}
+
+// resourceCiscoNativeMulticastUnderlayRpPaths returns the objects only configured on the RPs in the order they are deleted
+func (*providerClient) resourceCiscoNativeMulticastUnderlayRpPaths(d *schema.ResourceData) []string {
+	return []string{
+		fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Loopback=%v", loopbackId(d.Get("anycast_rp_loopback").(string))),
+		"/data/Cisco-IOS-XE-native:native/ip/Cisco-IOS-XE-multicast:msdp",
+	}
+}
+
+// resourceCiscoNativeMulticastUnderlayPaths returns the objects of the multicast underlay in the order they are deleted
+func (c *providerClient) resourceCiscoNativeMulticastUnderlayPaths(d *schema.ResourceData, rp bool) []string {
+	paths := []string{}
+	for _, v := range d.Get("interfaces").([]interface{}) {
+		paths = append(paths, interfacePath(v.(string))+"/ip/pim")
+	}
+	if rp {
+		paths = append(paths, c.resourceCiscoNativeMulticastUnderlayRpPaths(d)...)
+	}
+	return append(paths,
+		"/data/Cisco-IOS-XE-native:native/ip/Cisco-IOS-XE-multicast:pim/rp-address",
+		"/data/Cisco-IOS-XE-native:native/ip/Cisco-IOS-XE-multicast:multicast-routing",
+	)
+}
+
+// resourceCiscoNativeMulticastUnderlaySteps returns the multicast config of a host, RPs (host set) also get the anycast-RP Loopback and the MSDP peers
+func (c *providerClient) resourceCiscoNativeMulticastUnderlaySteps(d *schema.ResourceData, host string, peers []string) []payloadStep {
+	ip := &loopback.CiscoIOSXENativeIPMulticast{}
+	ip.CiscoIOSXENativeIP.MulticastRouting = &loopback.CiscoIOSXEMulticastRouting{}
+	ip.CiscoIOSXENativeIP.Pim = &loopback.CiscoIOSXEMulticastPim{
+		RpAddress: loopback.CiscoIOSXEMulticastRpAddress{Address: d.Get("rp_address").(string)},
+	}
+	steps := []payloadStep{}
+	if host != "" {
+		msdpID, _ := loopbackNumber(d, "msdp_loopback", host)
+		ip.CiscoIOSXENativeIP.Msdp = &loopback.CiscoIOSXEMulticastMsdp{
+			OriginatorID: loopback.CiscoIOSXEMulticastMsdpSource{Loopback: msdpID},
+		}
+		for _, peer := range peers {
+			ip.CiscoIOSXENativeIP.Msdp.Peer = append(ip.CiscoIOSXENativeIP.Msdp.Peer, loopback.CiscoIOSXEMulticastMsdpPeer{
+				Addr:          peer,
+				ConnectSource: loopback.CiscoIOSXEMulticastMsdpSource{Loopback: msdpID},
+			})
+		}
+
+		anycastID, _ := loopbackNumber(d, "anycast_rp_loopback", host)
+		lp := loopback.CiscoIOSXENativeLoopback{
+			Name:        anycastID,
+			Description: "Anycast RP",
+		}
+		lp.IP.Address.Primary.Address = d.Get("rp_address").(string)
+		lp.IP.Address.Primary.Mask = "255.255.255.255"
+		lp.IP.Pim.CiscoIOSXEMulticastPimModeChoiceCfg.SparseMode = map[string]string{}
+		steps = append(steps, newPayloadStep("/data/Cisco-IOS-XE-native:native/interface", &loopback.CiscoIOSXENativeLoopbackInterface{
+			CiscoIOSXENativeLoopback: []loopback.CiscoIOSXENativeLoopback{lp},
+		}))
+	}
+	steps = append([]payloadStep{newPayloadStep("/data/Cisco-IOS-XE-native:native/ip", ip)}, steps...)
+
+	names := d.Get("interfaces").([]interface{})
+	if len(names) == 0 {
+		return steps
+	}
+	interfaces := &loopback.CiscoIOSXENativePimInterfaces{
+		Interface: make(map[string][]loopback.CiscoIOSXENativePimInterface),
+	}
+	for _, v := range names {
+		list, id := interfaceName(v.(string))
+		data := loopback.CiscoIOSXENativePimInterface{Name: id}
+		data.IP.Pim.CiscoIOSXEMulticastPimModeChoiceCfg.SparseMode = map[string]string{}
+		interfaces.Interface[list] = append(interfaces.Interface[list], data)
+	}
+	return append(steps, newPayloadStep("/data/Cisco-IOS-XE-native:native/interface", interfaces))
+}
+
+// resourceCiscoNativeMulticastUnderlayRpState adds the MSDP and anycast-RP Loopback of an RP to the state
+func (*providerClient) resourceCiscoNativeMulticastUnderlayRpState(d *schema.ResourceData, state map[string]interface{}, msdp *loopback.CiscoIOSXEMulticastMsdp, lp *loopback.CiscoIOSXENativeLoopbackInterface) {
+	state["msdp_loopback"] = fmt.Sprintf("Loopback%v", msdp.OriginatorID.Loopback)
+	if fmt.Sprint(msdp.OriginatorID.Loopback) == loopbackId(d.Get("msdp_loopback").(string)) {
+		state["msdp_loopback"] = d.Get("msdp_loopback").(string)
+	}
+	state["anycast_rp_loopback"] = ""
+	for _, l := range lp.CiscoIOSXENativeLoopback {
+		if l.IP.Address.Primary.Address == d.Get("rp_address").(string) && l.IP.Pim.CiscoIOSXEMulticastPimModeChoiceCfg.SparseMode != nil {
+			state["anycast_rp_loopback"] = d.Get("anycast_rp_loopback").(string)
+		}
+	}
+}
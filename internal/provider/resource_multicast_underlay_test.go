@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnMulticastUnderlay(t *testing.T) {
+	f := newTestAccFabric(t, 2, 1)
+	rp := "Cisco-IOS-XE-native:native/ip/Cisco-IOS-XE-multicast:pim/rp-address"
+	msdp := "Cisco-IOS-XE-native:native/ip/Cisco-IOS-XE-multicast:msdp"
+	anycast := "Cisco-IOS-XE-native:native/interface/Loopback=254"
+	link := "Cisco-IOS-XE-native:native/interface/TenGigabitEthernet=1%2F1%2F2/ip/pim"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckDevicesRemoved(f.Devices(), rp),
+			testAccCheckDevicesRemoved(f.Devices(), "Cisco-IOS-XE-native:native/ip/Cisco-IOS-XE-multicast:multicast-routing"),
+			testAccCheckDevicesRemoved(f.Spines, msdp),
+			testAccCheckDevicesRemoved(f.Spines, anycast),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCiscoEvpnMulticastUnderlayConfig(f, `["leafs"]`, `["spines"]`, `"TenGigabitEthernet1/1/1"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("rp_roles spines is not part of roles"),
+			},
+			{
+				Config: testAccCiscoEvpnMulticastUnderlayConfig(f, `["spines", "leafs"]`, `["spines"]`, `"TenGigabitEthernet1/1/1", "TenGigabitEthernet1/1/2"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_multicast_underlay.test", "devices.#", "3"),
+					testAccCheckDevices(f.Devices(), rp, `"address":"100.119.254.1"`),
+					testAccCheckDevices(f.Spines[:1], msdp, `"addr":"100.119.100.2"`, `"originator-id":{"Loopback":100}`),
+					testAccCheckDevices(f.Spines[1:], msdp, `"addr":"100.119.100.1"`),
+					testAccCheckDevices(f.Spines, anycast, `"address":"100.119.254.1"`, `"sparse-mode"`),
+					testAccCheckDevicesRemoved(f.Leafs, msdp),
+					testAccCheckDevicesRemoved(f.Leafs, anycast),
+					testAccCheckDevices(f.Devices(), link, `"sparse-mode"`),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnMulticastUnderlayConfig(f, `["spines", "leafs"]`, `["spines"]`, `"TenGigabitEthernet1/1/1"`),
+				Check:  testAccCheckDevicesRemoved(f.Devices(), link),
+			},
+		},
+	})
+}
+
+func testAccCiscoEvpnMulticastUnderlayConfig(f *testAccFabric, roles string, rpRoles string, interfaces string) string {
+	return f.Config() + f.LoopbacksConfig(100) + fmt.Sprintf(`
+resource "ciscoevpn_multicast_underlay" "test" {
+  depends_on          = [ciscoevpn_loopback.spine0_100, ciscoevpn_loopback.spine1_100]
+  roles               = %v
+  rp_roles            = %v
+  rp_address          = "100.119.254.1"
+  anycast_rp_loopback = "Loopback254"
+  msdp_loopback       = "Loopback100"
+  interfaces          = [%v]
+}
+`, roles, rpRoles, interfaces)
+}
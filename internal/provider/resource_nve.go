@@ -3,14 +3,16 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/nve"
 	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/iosxe"
 	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
@@ -18,11 +20,15 @@ import (
 
 func resourceCiscoNativeNve() *schema.Resource {
 	return &schema.Resource{
-		Description:   "Cisco NVE",
-		CreateContext: resourceCiscoNativeNveCreate,
+		Description:   "Cisco NVE, only the member VNIs of the resource are read and removed, the members of ciscoevpn_l2vni and ciscoevpn_l3vni resources are left to those resources",
+		CreateContext: transactional(resourceCiscoNativeNveCreate),
 		ReadContext:   resourceCiscoNativeNveRead,
-		UpdateContext: resourceCiscoNativeNveUpdate,
-		DeleteContext: resourceCiscoNativeNveDelete,
+		UpdateContext: transactional(resourceCiscoNativeNveUpdate),
+		DeleteContext: transactional(resourceCiscoNativeNveDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativeNveImport,
+		},
+		CustomizeDiff: customdiff.All(devicesCustomizeDiff, resourceCiscoNativeNveCustomizeDiff),
 		Schema: map[string]*schema.Schema{
 			"roles": {
 				Type:     schema.TypeList,
@@ -37,13 +43,15 @@ func resourceCiscoNativeNve() *schema.Resource {
 				Optional: true,
 			},
 			"source_interface": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateLoopback(),
+				DiffSuppressFunc: suppressLoopbackDiff,
 			},
 			"vni": {
 				Type:     schema.TypeMap,
 				Optional: true,
+				Computed: true,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
@@ -51,6 +59,7 @@ func resourceCiscoNativeNve() *schema.Resource {
 			"vni_ipv4_multicast_group": {
 				Type:     schema.TypeMap,
 				Optional: true,
+				Computed: true,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
@@ -58,14 +67,36 @@ func resourceCiscoNativeNve() *schema.Resource {
 			"vni_ingress_replication": {
 				Type:     schema.TypeList,
 				Optional: true,
+				Computed: true,
 				Elem: &schema.Schema{
 					Type: schema.TypeInt,
 				},
 			},
+			"devices":           devicesSchema(),
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
 		},
 	}
 }
 
+// resourceCiscoNativeNveCustomizeDiff keeps the members read from the devices when they aren't configured,
+// the SDK plans empty computed lists and maps as unknown otherwise
+func resourceCiscoNativeNveCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return nil
+	}
+	for _, k := range []string{"vni", "vni_ipv4_multicast_group", "vni_ingress_replication"} {
+		if !config.GetAttr(k).IsNull() {
+			continue
+		}
+		if err := d.Clear(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func resourceCiscoNativeNveCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Println("[DEBUG] Cisco NVE CREATE")
 	var diags diag.Diagnostics
@@ -73,19 +104,25 @@ func resourceCiscoNativeNveCreate(ctx context.Context, d *schema.ResourceData, m
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Path:     "/data/Cisco-IOS-XE-native:native/interface/nve",
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    "/data/Cisco-IOS-XE-native:native/interface/nve",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 
 	id := loopbackId(d.Get("source_interface").(string))
 	d.Set("source_interface", id)
 
+	devices := getDevices(d)
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
-		data := c.resourceCiscoNativeNveData(d)
+		data, dataDiags := c.resourceCiscoNativeNveData(d, c.roleTarget(svc.Role))
+		if dataDiags.HasError() {
+			setDevices(d, devices)
+			return dataDiags
+		}
 		if data == nil {
 			return diag.Errorf("No data in yang model")
 		}
@@ -93,12 +130,14 @@ func resourceCiscoNativeNveCreate(ctx context.Context, d *schema.ResourceData, m
 		if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 			svc.Payload = string(b)
 		}
-		if svc.Provider.Get("debug").(bool) {
-			debugJson(fmt.Sprintf("nve_%v", svc.Role), svc.Payload)
+		svc.CLI = data.CLI()
+		if svc.Config.Debug {
+			debugPayload(fmt.Sprintf("nve_%v", svc.Role), svc)
 		}
 
-		_, err = iosxe.MultiSession(svc)
+		err = c.applyRole(svc, devices)
 		if err != nil {
+			setDevices(d, devices)
 			return diag.FromErr(err)
 		}
 	}
@@ -108,12 +147,65 @@ func resourceCiscoNativeNveCreate(ctx context.Context, d *schema.ResourceData, m
 		return diag.FromErr(err)
 	}
 	d.SetId(fmt.Sprintf("nve_%v", svc.Role))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
 func resourceCiscoNativeNveRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	log.Println("[DEBUG] Cisco NVE READ") // TODO
+	return resourceCiscoNativeNveReadMembers(ctx, d, meta, resourceCiscoNativeNveOwnedMembers(d))
+}
+
+// resourceCiscoNativeNveReadMembers reads the NVE with the owned member VNIs, all the members are read when owned is nil
+func resourceCiscoNativeNveReadMembers(ctx context.Context, d *schema.ResourceData, meta interface{}, owned map[string]bool) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco NVE READ")
 	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	bodies, err := c.readRoles(ctx, d, "/data/Cisco-IOS-XE-native:native/interface/nve=1")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	states := make(map[string]map[string]interface{})
+	for host, body := range bodies {
+		data := &nve.CiscoIOSXENativeNves{}
+		if err = unmarshalBody(body, data); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		if len(data.CiscoIOSXENativeNve) == 0 {
+			log.Println("[DEBUG] NVE not found on: ", host)
+			d.SetId("")
+			return diags
+		}
+		states[host] = c.resourceCiscoNativeNveState(data)
+	}
+
+	// only the owned members are compared, so a member changed on one of the hosts marks it as drifted.
+	// The members of ciscoevpn_l2vni and ciscoevpn_l3vni resources aren't owned, they are left to those resources.
+	own := func(vni interface{}) bool {
+		return owned == nil || owned[fmt.Sprint(vni)]
+	}
+	for _, state := range states {
+		for _, k := range []string{"vni", "vni_ipv4_multicast_group"} {
+			for key, v := range state[k].(map[string]interface{}) {
+				if !own(v) {
+					delete(state[k].(map[string]interface{}), key)
+				}
+			}
+		}
+		ingress := []interface{}{}
+		for _, v := range state["vni_ingress_replication"].([]interface{}) {
+			if own(v) {
+				ingress = append(ingress, v)
+			}
+		}
+		state["vni_ingress_replication"] = orderLike(d.Get("vni_ingress_replication").([]interface{}), ingress)
+	}
+	if err = setDevicesDriftState(d, states); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
@@ -122,27 +214,50 @@ func resourceCiscoNativeNveUpdate(ctx context.Context, d *schema.ResourceData, m
 	var diags diag.Diagnostics
 	var err error
 
-	if d.HasChange("roles") {
-		oldState, _ := d.GetChange("roles")
-		d.Set("roles", oldState)
-		return diag.Errorf("Not supported to change Roles")
-	}
-
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Path:     "/data/Cisco-IOS-XE-native:native/interface/nve=1",
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    "/data/Cisco-IOS-XE-native:native/interface/nve=1",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 
 	id := loopbackId(d.Get("source_interface").(string))
 	d.Set("source_interface", id)
 
+	devices := getDevices(d)
+	if err = c.removeHosts(ctx, d, devices, "/data/Cisco-IOS-XE-native:native/interface/nve=1"); err != nil {
+		setDevices(d, devices)
+		return diag.FromErr(err)
+	}
+
+	// the PATCH merges the members, members removed or changed in the config are deleted first
+	paths := c.resourceCiscoNativeNveRemovedMembers(d)
+
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
-		data := c.resourceCiscoNativeNveData(d)
+		if len(paths) > 0 {
+			if err = c.deleteRoleSteps(svc, paths, devices); err != nil {
+				setDevices(d, devices)
+				return diag.FromErr(err)
+			}
+			// the deleted members are sent again to the hosts which already had the payload applied
+			for _, state := range devices {
+				if state.Role == svc.Role {
+					state.PayloadHash = ""
+				}
+			}
+			svc.Method = "PATCH"
+			svc.Path = "/data/Cisco-IOS-XE-native:native/interface/nve=1"
+		}
+
+		data, dataDiags := c.resourceCiscoNativeNveData(d, c.roleTarget(svc.Role))
+		if dataDiags.HasError() {
+			setDevices(d, devices)
+			return dataDiags
+		}
 		if data == nil {
 			return diag.Errorf("No data in yang model")
 		}
@@ -150,12 +265,14 @@ func resourceCiscoNativeNveUpdate(ctx context.Context, d *schema.ResourceData, m
 		if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 			svc.Payload = string(b)
 		}
-		if svc.Provider.Get("debug").(bool) {
-			debugJson(fmt.Sprintf("nve_%v", svc.Role), svc.Payload)
+		svc.CLI = data.CLI()
+		if svc.Config.Debug {
+			debugPayload(fmt.Sprintf("nve_%v", svc.Role), svc)
 		}
 
-		_, err = iosxe.MultiSession(svc)
+		err = c.applyRole(svc, devices)
 		if err != nil {
+			setDevices(d, devices)
 			return diag.FromErr(err)
 		}
 	}
@@ -165,6 +282,9 @@ func resourceCiscoNativeNveUpdate(ctx context.Context, d *schema.ResourceData, m
 		return diag.FromErr(err)
 	}
 	d.SetId(fmt.Sprintf("nve_%v", svc.Role))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
@@ -175,16 +295,18 @@ func resourceCiscoNativeNveDelete(ctx context.Context, d *schema.ResourceData, m
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "DELETE",
-		Path:     "/data/Cisco-IOS-XE-native:native/interface/nve=1",
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "DELETE",
+		Path:    "/data/Cisco-IOS-XE-native:native/interface/nve=1",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 
+	devices := getDevices(d)
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
-		_, err = iosxe.MultiSession(svc)
+		err = c.deleteRole(svc, devices)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -194,16 +316,48 @@ func resourceCiscoNativeNveDelete(ctx context.Context, d *schema.ResourceData, m
 	return diags
 }
 
-func (svc *providerClient) resourceCiscoNativeNveData(d *schema.ResourceData) *nve.CiscoIOSXENativeNves {
+func resourceCiscoNativeNveImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco NVE IMPORT")
+	c, _ := meta.(*providerClient)
+	parts, err := importID(d.Id(), "<roles>")
+	if err != nil {
+		return nil, err
+	}
+	if err = c.importRoles(d, parts[0]); err != nil {
+		return nil, err
+	}
+	roles := strings.Split(parts[0], ",")
+	d.SetId(fmt.Sprintf("nve_%v", roles[len(roles)-1]))
+	// nothing is owned yet, all the members of the devices are imported
+	return importRead(ctx, d, meta, func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		return resourceCiscoNativeNveReadMembers(ctx, d, meta, nil)
+	})
+}
+
+// resourceCiscoNativeNveOwnedMembers returns the VNIs of the members in state, the members the resource owns
+func resourceCiscoNativeNveOwnedMembers(d *schema.ResourceData) map[string]bool {
+	owned := make(map[string]bool)
+	for _, k := range []string{"vni", "vni_ipv4_multicast_group"} {
+		for _, v := range d.Get(k).(map[string]interface{}) {
+			owned[fmt.Sprint(v)] = true
+		}
+	}
+	for _, v := range d.Get("vni_ingress_replication").([]interface{}) {
+		owned[fmt.Sprint(v)] = true
+	}
+	return owned
+}
+
+func (svc *providerClient) resourceCiscoNativeNveData(d *schema.ResourceData, target string) (*nve.CiscoIOSXENativeNves, diag.Diagnostics) {
 	data := &nve.CiscoIOSXENativeNves{}
 	nveData := &nve.CiscoIOSXENativeNve{}
 
 	nveData.Name = 1
 	nveData.Description = d.Get("description").(string)
 	nveData.HostReachability.Protocol.Bgp = null()
-	id, err := strconv.Atoi(d.Get("source_interface").(string))
-	if err != nil {
-		log.Panicln("[PANIC] Can't find Loopback ID", err)
+	id, diags := loopbackNumber(d, "source_interface", target)
+	if diags.HasError() {
+		return nil, diags
 	}
 	nveData.SourceInterface.Loopback = id
 
@@ -245,7 +399,7 @@ func (svc *providerClient) resourceCiscoNativeNveData(d *schema.ResourceData) *n
 	}
 
 	data.CiscoIOSXENativeNve = append(data.CiscoIOSXENativeNve, *nveData)
-	return data
+	return data, nil
 }
 
 func (*providerClient) vniRanges(data interface{}) string {
@@ -257,3 +411,66 @@ func (*providerClient) vniRanges(data interface{}) string {
 	return fmt.Sprintf("%v-%v", vniRange[0], vniRange[1])
 
 }
+
+func (svc *providerClient) resourceCiscoNativeNveState(data *nve.CiscoIOSXENativeNves) map[string]interface{} {
+	nveData := data.CiscoIOSXENativeNve[0]
+	vnis := make(map[string]interface{})
+	mcast := make(map[string]interface{})
+	ingress := []interface{}{}
+
+	for _, vni := range nveData.MemberInOneLine.Member.Vni {
+		if vni.Vrf != "" {
+			vnis[vni.Vrf] = vni.VniRange
+		}
+	}
+	for _, vni := range nveData.Member.Vni {
+		if vni.McastGroup != nil {
+			mcast[vni.McastGroup.MulticastGroupMin] = vni.VniRange
+		}
+		if vni.IrCpConfig != nil {
+			if id, err := strconv.Atoi(vni.VniRange); err == nil {
+				ingress = append(ingress, id)
+			}
+		}
+	}
+
+	return map[string]interface{}{
+		"description":              nveData.Description,
+		"source_interface":         fmt.Sprintf("Loopback%v", nveData.SourceInterface.Loopback),
+		"vni":                      vnis,
+		"vni_ipv4_multicast_group": mcast,
+		"vni_ingress_replication":  ingress,
+	}
+}
+
+// resourceCiscoNativeNveRemovedMembers returns the paths of the member VNIs in state which are removed
+// or changed in the config
+func (svc *providerClient) resourceCiscoNativeNveRemovedMembers(d *schema.ResourceData) []string {
+	members := func(vni, mcast map[string]interface{}, ingress []interface{}) map[string]string {
+		m := make(map[string]string)
+		for vrf, v := range vni {
+			m["member-in-one-line/member/vni="+svc.vniRanges(v)] = "vrf " + vrf
+		}
+		for group, v := range mcast {
+			m["member/vni="+svc.vniRanges(v)] = "mcast-group " + group
+		}
+		for _, v := range ingress {
+			m[fmt.Sprintf("member/vni=%v", v)] = "ingress-replication"
+		}
+		return m
+	}
+	oldVni, newVni := d.GetChange("vni")
+	oldMcast, newMcast := d.GetChange("vni_ipv4_multicast_group")
+	oldIngress, newIngress := d.GetChange("vni_ingress_replication")
+	oldMembers := members(oldVni.(map[string]interface{}), oldMcast.(map[string]interface{}), oldIngress.([]interface{}))
+	newMembers := members(newVni.(map[string]interface{}), newMcast.(map[string]interface{}), newIngress.([]interface{}))
+
+	paths := []string{}
+	for member, config := range oldMembers {
+		if newMembers[member] != config {
+			paths = append(paths, "/data/Cisco-IOS-XE-native:native/interface/nve=1/"+member)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnNve(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	path := "Cisco-IOS-XE-native:native/interface/nve=1"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDevicesRemoved(f.Leafs, path),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnNveConfig(f, "225.0.0.101"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_nve.test", "source_interface", "Loopback200"),
+					testAccCheckDevices(f.Leafs, path, `"Loopback":200`, `"225.0.0.101"`, `10102`),
+					testAccCheckDevicesRemoved(f.Spines, path),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnNveConfig(f, "225.0.0.201"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDevices(f.Leafs, path, `"225.0.0.201"`),
+					testAccCheckDevicesRemoved(f.Leafs, path+"/member/vni=10101/mcast-group/multicast-group-min=225.0.0.101"),
+				),
+			},
+			{
+				// the source interface configured as the Loopback id has no diff to the Loopback<id> read
+				Config:   strings.Replace(testAccCiscoEvpnNveConfig(f, "225.0.0.201"), "ciscoevpn_loopback.leaf0_200.interface_name", `"200"`, 1),
+				PlanOnly: true,
+			},
+			{
+				// A member of the resource changed out of band is shown in the plan
+				PreConfig: func() {
+					err := f.Leafs[0].Set(path+"/member/vni=10102", `{"Cisco-IOS-XE-native:vni":[{"vni-range":"10102","mcast-group":{"multicast-group-min":"225.0.0.102"}}]}`)
+					if err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             testAccCiscoEvpnNveConfig(f, "225.0.0.201"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccCiscoEvpnNveConfig(f, "225.0.0.201"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDevices(f.Leafs, path+"/member/vni=10102", `"ingress-replication"`),
+					testAccCheckDevicesRemoved(f.Leafs, path+"/member/vni=10102/mcast-group"),
+				),
+			},
+			{
+				// A member added out of band isn't owned by the resource, it's left alone
+				PreConfig: func() {
+					err := f.Leafs[0].Set(path+"/member/vni=10199", `{"Cisco-IOS-XE-native:vni":[{"vni-range":"10199","ir-cp-config":{"ingress-replication":[null]}}]}`)
+					if err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:   testAccCiscoEvpnNveConfig(f, "225.0.0.201"),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccCiscoEvpnNveWithL2vni(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	path := "Cisco-IOS-XE-native:native/interface/nve=1"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDevicesRemoved(f.Leafs, path),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnNveWithL2vniConfig(f, "225.0.0.101"),
+				Check:  testAccCheckDevices(f.Leafs, path, `"225.0.0.101"`, `"vni-range":"10201"`),
+			},
+			{
+				// the member of the l2vni isn't in the nve config, the nve neither plans nor deletes it
+				Config:   testAccCiscoEvpnNveWithL2vniConfig(f, "225.0.0.101"),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccCiscoEvpnNveWithL2vniConfig(f, "225.0.0.201"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDevices(f.Leafs, path, `"225.0.0.201"`),
+					testAccCheckDevices(f.Leafs, path+"/member/vni=10201", `"ingress-replication"`),
+					testAccCheckDevicesRemoved(f.Leafs, path+"/member/vni=10101/mcast-group/multicast-group-min=225.0.0.101"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCiscoEvpnNveWithL2vniConfig(f *testAccFabric, group string) string {
+	return testAccCiscoEvpnNveConfig(f, group) + `
+resource "ciscoevpn_l2vni" "test" {
+  depends_on = [ciscoevpn_nve.test]
+  roles      = ["leafs"]
+  vlan_id    = 201
+  vni        = 10201
+  evi        = 201
+  rd         = "1:201"
+  rt         = "1:201"
+}
+`
+}
+
+func testAccCiscoEvpnNveConfig(f *testAccFabric, group string) string {
+	return f.Config() + f.LoopbacksConfig(200) + fmt.Sprintf(`
+resource "ciscoevpn_nve" "test" {
+  depends_on       = [ciscoevpn_loopback.leaf0_200, ciscoevpn_loopback.leaf1_200]
+  roles            = ["leafs"]
+  source_interface = ciscoevpn_loopback.leaf0_200.interface_name
+  vni = {
+    "green" = "10103"
+  }
+  vni_ipv4_multicast_group = {
+    %q = "10101"
+  }
+  vni_ingress_replication = ["10102"]
+}
+`, group)
+}
@@ -0,0 +1,434 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/loopback"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/ospf"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/iosxe"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
+)
+
+func resourceCiscoNativeOspf() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Cisco OSPF underlay, the router-id is the address of the router_id Loopback on each device",
+		CreateContext: transactional(resourceCiscoNativeOspfCreate),
+		ReadContext:   resourceCiscoNativeOspfRead,
+		UpdateContext: transactional(resourceCiscoNativeOspfUpdate),
+		DeleteContext: transactional(resourceCiscoNativeOspfDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativeOspfImport,
+		},
+		CustomizeDiff: devicesCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"roles": {
+				Type:     schema.TypeList,
+				Required: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"process_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"router_id": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateLoopback(),
+				DiffSuppressFunc: suppressLoopbackDiff,
+			},
+			"area": {
+				Type:     schema.TypeInt,
+				Default:  0,
+				Optional: true,
+			},
+			"loopbacks": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringMatch(loopbackRe, "expected a Loopback interface, e.g. Loopback0"),
+				},
+			},
+			"interfaces": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateInterface(),
+				},
+			},
+			"point_to_point": {
+				Type:     schema.TypeBool,
+				Default:  true,
+				Optional: true,
+			},
+			"passive_interfaces": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateInterface(),
+				},
+			},
+			"devices":           devicesSchema(),
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
+		},
+	}
+}
+
+func resourceCiscoNativeOspfCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco OSPF CREATE")
+	var diags diag.Diagnostics
+	var err error
+
+	c, _ := meta.(*providerClient)
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "PATCH",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	devices := getDevices(d)
+	roles := d.Get("roles").([]interface{})
+	for _, role := range roles {
+		svc.Role = role.(string)
+		if diags = c.resourceCiscoNativeOspfApply(svc, d, devices); diags.HasError() {
+			setDevices(d, devices)
+			return diags
+		}
+	}
+
+	d.SetId(fmt.Sprintf("ospf_%v", d.Get("process_id").(int)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativeOspfRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco OSPF READ")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	bodies, err := c.readRoles(ctx, d, c.resourceCiscoNativeOspfPath(d))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	routerIDs, err := c.readRoles(ctx, d, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Loopback=%v", loopbackId(d.Get("router_id").(string))))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// OSPF config of the loopbacks and interfaces per host
+	interfaces := make(map[string]map[string]*ospf.CiscoIOSXEOspfInterfaceOspf)
+	for _, name := range c.resourceCiscoNativeOspfInterfaces(d) {
+		data, err := c.readRoles(ctx, d, interfacePath(name)+"/ip/Cisco-IOS-XE-ospf:router-ospf")
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		for host, body := range data {
+			routerOspf := &ospf.CiscoIOSXEOspfInterfaceRouterOspfs{}
+			if err = unmarshalBody(body, routerOspf); err != nil {
+				if errors.Is(err, iosxe.ErrNotFound) {
+					continue
+				}
+				return diag.FromErr(err)
+			}
+			if interfaces[host] == nil {
+				interfaces[host] = make(map[string]*ospf.CiscoIOSXEOspfInterfaceOspf)
+			}
+			interfaces[host][name] = &routerOspf.CiscoIOSXEOspfRouterOspf.Ospf
+		}
+	}
+
+	states := make(map[string]map[string]interface{})
+	for host, body := range bodies {
+		data := &ospf.CiscoIOSXEOspfProcessIDs{}
+		if err = unmarshalBody(body, data); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		if len(data.ProcessID) == 0 {
+			log.Printf("[DEBUG] OSPF %v not found on: %v\n", d.Get("process_id").(int), host)
+			d.SetId("")
+			return diags
+		}
+		lp := &loopback.CiscoIOSXENativeLoopbackInterface{}
+		if err = unmarshalBody(routerIDs[host], lp); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		states[host] = c.resourceCiscoNativeOspfState(d, &data.ProcessID[0], lp, interfaces[host])
+	}
+
+	if err = setDevicesDriftState(d, states); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativeOspfUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco OSPF UPDATE")
+	var diags diag.Diagnostics
+	var err error
+
+	c, _ := meta.(*providerClient)
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "PATCH",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	devices := getDevices(d)
+	if err = c.removeHosts(ctx, d, devices, c.resourceCiscoNativeOspfPaths(d)...); err != nil {
+		setDevices(d, devices)
+		return diag.FromErr(err)
+	}
+
+	// PATCH merges, removed interfaces and passive interfaces are deleted before the new config is applied
+	paths := []string{}
+	current := make(map[string]bool)
+	for _, name := range c.resourceCiscoNativeOspfInterfaces(d) {
+		current[name] = true
+	}
+	for _, attr := range []string{"loopbacks", "interfaces"} {
+		old, _ := d.GetChange(attr)
+		for _, v := range old.([]interface{}) {
+			name := v.(string)
+			if attr == "loopbacks" {
+				name = fmt.Sprintf("Loopback%v", loopbackId(name))
+			}
+			if !current[name] || d.HasChanges("area", "point_to_point") {
+				paths = append(paths, interfacePath(name)+"/ip/Cisco-IOS-XE-ospf:router-ospf")
+			}
+		}
+	}
+	if d.HasChange("passive_interfaces") {
+		paths = append(paths, c.resourceCiscoNativeOspfPath(d)+"/passive-interface")
+	}
+
+	roles := d.Get("roles").([]interface{})
+	for _, role := range roles {
+		svc.Role = role.(string)
+		if len(paths) > 0 {
+			if err = c.deleteRoleSteps(svc, paths, devices); err != nil {
+				setDevices(d, devices)
+				return diag.FromErr(err)
+			}
+		}
+		if diags = c.resourceCiscoNativeOspfApply(svc, d, devices); diags.HasError() {
+			setDevices(d, devices)
+			return diags
+		}
+	}
+
+	d.SetId(fmt.Sprintf("ospf_%v", d.Get("process_id").(int)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativeOspfDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco OSPF DELETE")
+	var err error
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "DELETE",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	devices := getDevices(d)
+	paths := c.resourceCiscoNativeOspfPaths(d)
+	roles := d.Get("roles").([]interface{})
+	for _, role := range roles {
+		svc.Role = role.(string)
+		err = c.deleteRoleSteps(svc, paths, devices)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId("")
+	return diags
+}
+
+func resourceCiscoNativeOspfImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco OSPF IMPORT")
+	c, _ := meta.(*providerClient)
+	parts, err := importID(d.Id(), "<roles>:<process_id>:<router_id>")
+	if err != nil {
+		return nil, err
+	}
+	id, err := importInt(parts[1], "process_id")
+	if err != nil {
+		return nil, err
+	}
+	if err = c.importRoles(d, parts[0]); err != nil {
+		return nil, err
+	}
+	d.Set("process_id", id)
+	d.Set("router_id", parts[2])
+	d.SetId(fmt.Sprintf("ospf_%v", id))
+	return importRead(ctx, d, meta, resourceCiscoNativeOspfRead)
+}
+
+// resourceCiscoNativeOspfApply builds the payloads of each host in the role with its router-id and applies them
+func (c *providerClient) resourceCiscoNativeOspfApply(svc *service.Client, d *schema.ResourceData, devices map[string]*deviceState) diag.Diagnostics {
+	id, diags := loopbackNumber(d, "router_id", c.roleTarget(svc.Role))
+	if diags.HasError() {
+		return diags
+	}
+	addresses, err := c.loopbackAddresses(svc.Context, svc.Role, id)
+	if err != nil {
+		return attrDiag("router_id", c.roleTarget(svc.Role), err)
+	}
+
+	hostSteps := make(map[string][]payloadStep)
+	for host, address := range addresses {
+		hostSteps[host] = c.resourceCiscoNativeOspfSteps(d, address)
+		if svc.Config.Debug {
+			svc.Payload, svc.CLI = stepsPayload(hostSteps[host])
+			debugPayload(fmt.Sprintf("ospf_%v_%v", svc.Role, host), svc)
+		}
+	}
+	if err = c.applyHostSteps(svc, hostSteps, devices); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func (*providerClient) resourceCiscoNativeOspfPath(d *schema.ResourceData) string {
+	return fmt.Sprintf("/data/Cisco-IOS-XE-native:native/router/Cisco-IOS-XE-ospf:router-ospf/ospf/process-id=%v", d.Get("process_id").(int))
+}
+
+// resourceCiscoNativeOspfInterfaces returns the loopbacks (as Loopback<id>) and interfaces running OSPF
+func (*providerClient) resourceCiscoNativeOspfInterfaces(d *schema.ResourceData) []string {
+	names := []string{}
+	for _, v := range d.Get("loopbacks").([]interface{}) {
+		names = append(names, fmt.Sprintf("Loopback%v", loopbackId(v.(string))))
+	}
+	for _, v := range d.Get("interfaces").([]interface{}) {
+		names = append(names, v.(string))
+	}
+	return names
+}
+
+// resourceCiscoNativeOspfPaths returns the objects of the OSPF process in the order they are deleted
+func (c *providerClient) resourceCiscoNativeOspfPaths(d *schema.ResourceData) []string {
+	paths := []string{}
+	for _, name := range c.resourceCiscoNativeOspfInterfaces(d) {
+		paths = append(paths, interfacePath(name)+"/ip/Cisco-IOS-XE-ospf:router-ospf")
+	}
+	return append(paths, c.resourceCiscoNativeOspfPath(d))
+}
+
+// resourceCiscoNativeOspfSteps returns the OSPF process and the OSPF config of the interfaces of a host
+func (c *providerClient) resourceCiscoNativeOspfSteps(d *schema.ResourceData, routerID string) []payloadStep {
+	process := ospf.CiscoIOSXEOspfProcessID{
+		ID:       d.Get("process_id").(int),
+		RouterID: routerID,
+	}
+	for _, v := range d.Get("passive_interfaces").([]interface{}) {
+		process.PassiveInterface.Interface = append(process.PassiveInterface.Interface, v.(string))
+	}
+	router := &ospf.CiscoIOSXENativeRouterOspf{}
+	router.CiscoIOSXEOspfRouterOspf.Ospf.ProcessID = append(router.CiscoIOSXEOspfRouterOspf.Ospf.ProcessID, process)
+	steps := []payloadStep{
+		newPayloadStep("/data/Cisco-IOS-XE-native:native/router", router),
+	}
+
+	names := c.resourceCiscoNativeOspfInterfaces(d)
+	if len(names) == 0 {
+		return steps
+	}
+	interfaces := &ospf.CiscoIOSXENativeOspfInterfaces{
+		Interface: make(map[string][]ospf.CiscoIOSXENativeOspfInterface),
+	}
+	for _, name := range names {
+		list, id := interfaceName(name)
+		data := ospf.CiscoIOSXENativeOspfInterface{Name: id}
+		data.IP.RouterOspf.Ospf.ProcessID = []ospf.CiscoIOSXEOspfInterfaceProcessID{
+			{
+				ID:   process.ID,
+				Area: []ospf.CiscoIOSXEOspfInterfaceArea{{AreaID: d.Get("area").(int)}},
+			},
+		}
+		if list == "Loopback" {
+			data.Name, _ = strconv.Atoi(id)
+		} else if d.Get("point_to_point").(bool) {
+			data.IP.RouterOspf.Ospf.NetworkType = &ospf.CiscoIOSXEOspfInterfaceNetworkType{PointToPoint: null()}
+		}
+		interfaces.Interface[list] = append(interfaces.Interface[list], data)
+	}
+	return append(steps, newPayloadStep("/data/Cisco-IOS-XE-native:native/interface", interfaces))
+}
+
+func (*providerClient) resourceCiscoNativeOspfState(d *schema.ResourceData, process *ospf.CiscoIOSXEOspfProcessID, routerID *loopback.CiscoIOSXENativeLoopbackInterface, interfaces map[string]*ospf.CiscoIOSXEOspfInterfaceOspf) map[string]interface{} {
+	state := map[string]interface{}{
+		"router_id": process.RouterID,
+	}
+	for _, lp := range routerID.CiscoIOSXENativeLoopback {
+		if lp.IP.Address.Primary.Address == process.RouterID {
+			state["router_id"] = d.Get("router_id").(string)
+		}
+	}
+
+	passive := []interface{}{}
+	for _, v := range process.PassiveInterface.Interface {
+		passive = append(passive, v)
+	}
+	state["passive_interfaces"] = orderLike(d.Get("passive_interfaces").([]interface{}), passive)
+
+	// only interfaces running the process are part of the state, the area and network type are read from them
+	state["area"] = d.Get("area").(int)
+	pointToPoint := true
+	running := func(name string) bool {
+		o, ok := interfaces[name]
+		if !ok {
+			return false
+		}
+		for _, p := range o.ProcessID {
+			if p.ID == process.ID && len(p.Area) > 0 {
+				state["area"] = p.Area[0].AreaID
+				return true
+			}
+		}
+		return false
+	}
+	loopbacks := []interface{}{}
+	for _, v := range d.Get("loopbacks").([]interface{}) {
+		if running(fmt.Sprintf("Loopback%v", loopbackId(v.(string)))) {
+			loopbacks = append(loopbacks, v)
+		}
+	}
+	state["loopbacks"] = loopbacks
+	links := []interface{}{}
+	for _, v := range d.Get("interfaces").([]interface{}) {
+		if running(v.(string)) {
+			links = append(links, v)
+			nt := interfaces[v.(string)].NetworkType
+			pointToPoint = pointToPoint && nt != nil && nt.PointToPoint != nil
+		}
+	}
+	state["interfaces"] = links
+	if len(links) > 0 {
+		state["point_to_point"] = pointToPoint
+	}
+	return state
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnOspf(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	path := "Cisco-IOS-XE-native:native/router/Cisco-IOS-XE-ospf:router-ospf/ospf/process-id=1"
+	link := "Cisco-IOS-XE-native:native/interface/TenGigabitEthernet=1%2F1%2F2/ip/Cisco-IOS-XE-ospf:router-ospf"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckDevicesRemoved(f.Devices(), path),
+			testAccCheckDevicesRemoved(f.Devices(), "Cisco-IOS-XE-native:native/interface/Loopback=100/ip/Cisco-IOS-XE-ospf:router-ospf"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnOspfConfig(f, `"TenGigabitEthernet1/1/1", "TenGigabitEthernet1/1/2"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_ospf.test", "devices.#", "3"),
+					testAccCheckDevices(f.Spines, path, `"router-id":"100.119.100.1"`, `"Loopback100"`),
+					testAccCheckDevices(f.Leafs[:1], path, `"router-id":"100.119.100.11"`),
+					testAccCheckDevices(f.Leafs[1:], path, `"router-id":"100.119.100.12"`),
+					testAccCheckDevices(f.Devices(), "Cisco-IOS-XE-native:native/interface/Loopback=100/ip/Cisco-IOS-XE-ospf:router-ospf", `"area-id":0`),
+					testAccCheckDevices(f.Devices(), link, `"point-to-point"`),
+					resource.TestMatchResourceAttr("ciscoevpn_ospf.test", fmt.Sprintf("cli_preview.%v", f.Spines[0].Host()), regexp.MustCompile(`router-id 100\.119\.100\.1\n`)),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnOspfConfig(f, `"TenGigabitEthernet1/1/1"`),
+				Check:  testAccCheckDevicesRemoved(f.Devices(), link),
+			},
+		},
+	})
+}
+
+func testAccCiscoEvpnOspfConfig(f *testAccFabric, interfaces string) string {
+	return f.Config() + f.LoopbacksConfig(100) + fmt.Sprintf(`
+resource "ciscoevpn_ospf" "test" {
+  depends_on         = [ciscoevpn_loopback.spine0_100, ciscoevpn_loopback.leaf0_100, ciscoevpn_loopback.leaf1_100]
+  roles              = ["spines", "leafs"]
+  process_id         = 1
+  router_id          = "Loopback100"
+  loopbacks          = ["Loopback100"]
+  interfaces         = [%v]
+  passive_interfaces = ["Loopback100"]
+}
+`, interfaces)
+}
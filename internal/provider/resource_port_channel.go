@@ -0,0 +1,398 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/port_channel"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/iosxe"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
+)
+
+func resourceCiscoNativePortChannel() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Cisco Port-channel with its member interfaces, the VLANs of a L2 Port-channel are configured with ciscoevpn_access_interface",
+		CreateContext: resourceCiscoNativePortChannelCreate,
+		ReadContext:   resourceCiscoNativePortChannelRead,
+		UpdateContext: resourceCiscoNativePortChannelUpdate,
+		DeleteContext: resourceCiscoNativePortChannelDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativePortChannelImport,
+		},
+		CustomizeDiff: resourceCiscoNativePortChannelCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"host": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"port_channel": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(1, 128),
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Default:  "Managed by Terraform (ciscoevpn)",
+				Optional: true,
+			},
+			"members": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateInterface(),
+				},
+			},
+			"lacp_mode": {
+				Type:         schema.TypeString,
+				Default:      "active",
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"active", "passive", "on"}, false),
+			},
+			"min_links": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 8),
+			},
+			"mode": {
+				Type:         schema.TypeString,
+				Default:      "l2",
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"l2", "l3"}, false),
+			},
+			"ipv4_address": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsIPv4Address,
+			},
+			"ipv4_mask": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsIPv4Address,
+			},
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
+		},
+	}
+}
+
+// resourceCiscoNativePortChannelCustomizeDiff requires Ethernet members and L3 mode for the address
+func resourceCiscoNativePortChannelCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.NewValueKnown("members") {
+		seen := make(map[string]bool)
+		for _, v := range d.Get("members").([]interface{}) {
+			if _, _, err := interfaceSpeed(v.(string), "TenGigabitEthernet1/0/1"); err != nil {
+				return fmt.Errorf("members: %v", err)
+			}
+			if seen[v.(string)] {
+				return fmt.Errorf("members: %v is listed more than once", v.(string))
+			}
+			seen[v.(string)] = true
+		}
+		if n := d.Get("min_links").(int); n > len(seen) {
+			return fmt.Errorf("min_links %v is more than the %v members", n, len(seen))
+		}
+	}
+	if !d.NewValueKnown("mode") || !d.NewValueKnown("ipv4_address") || !d.NewValueKnown("ipv4_mask") {
+		return nil
+	}
+	address, mask := d.Get("ipv4_address").(string), d.Get("ipv4_mask").(string)
+	switch {
+	case d.Get("mode").(string) == "l2" && (address != "" || mask != ""):
+		return fmt.Errorf("ipv4_address and ipv4_mask are only supported with mode l3")
+	case (address == "") != (mask == ""):
+		return fmt.Errorf("ipv4_address and ipv4_mask are required together")
+	}
+	return nil
+}
+
+func resourceCiscoNativePortChannelCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco Port Channel CREATE")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	if err := c.resourceCiscoNativePortChannelApply(ctx, d); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%v_Port-channel%v", d.Get("host").(string), d.Get("port_channel").(int)))
+	return diags
+}
+
+func resourceCiscoNativePortChannelRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco Port Channel READ")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	body, err := c.readHost(ctx, d, resourceCiscoNativePortChannelPath(d))
+	data := &port_channel.CiscoIOSXENativePortChannel{}
+	if err == nil {
+		err = unmarshalBody(body, data)
+	}
+	if errors.Is(err, iosxe.ErrNotFound) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if len(data.PortChannel) == 0 {
+		log.Printf("[DEBUG] Port-channel%v not found on: %v\n", d.Get("port_channel").(int), d.Get("host").(string))
+		d.SetId("")
+		return diags
+	}
+
+	members, mode, err := c.resourceCiscoNativePortChannelMembers(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	pc := data.PortChannel[0]
+	state := map[string]interface{}{
+		"description":  pc.Description,
+		"members":      orderLike(d.Get("members").([]interface{}), members),
+		"mode":         "l3",
+		"ipv4_address": pc.IP.PrimaryAddress().Address,
+		"ipv4_mask":    pc.IP.PrimaryAddress().Mask,
+		"min_links":    0,
+	}
+	if pc.Switchport.Switchport {
+		state["mode"] = "l2"
+	}
+	if pc.PortChannel != nil {
+		state["min_links"] = pc.PortChannel.MinLinks
+	}
+	if mode != "" {
+		state["lacp_mode"] = mode
+	}
+	if err = setDriftState(d, map[string]map[string]interface{}{d.Get("host").(string): state}); err != nil {
+		return diag.FromErr(err)
+	}
+	_, lines := stepsPayload(c.resourceCiscoNativePortChannelData(d))
+	if err = setHostCli(d, lines); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativePortChannelUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco Port Channel UPDATE")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	// members removed from the bundle are released before the new config is applied
+	if d.HasChange("members") {
+		o, n := d.GetChange("members")
+		kept := make(map[string]bool)
+		for _, v := range n.([]interface{}) {
+			kept[v.(string)] = true
+		}
+		for _, v := range o.([]interface{}) {
+			if kept[v.(string)] {
+				continue
+			}
+			if err := c.resourceCiscoNativePortChannelRelease(ctx, d, v.(string)); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	// PATCH merges, the address and min-links are deleted before the new config is applied
+	leaves := []string{}
+	if d.HasChanges("mode", "ipv4_address", "ipv4_mask") {
+		leaves = append(leaves, "/ip/address")
+	}
+	if d.HasChange("min_links") {
+		leaves = append(leaves, "/Cisco-IOS-XE-ethernet:port-channel")
+	}
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "DELETE",
+		Config:  c.Config,
+		Device:  d.Get("host").(string),
+	}
+	for _, leaf := range leaves {
+		svc.Path = resourceCiscoNativePortChannelPath(d) + leaf
+		if _, err := iosxe.SingleSession(svc); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+	}
+
+	if err := c.resourceCiscoNativePortChannelApply(ctx, d); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceCiscoNativePortChannelDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Cisco Port Channel DELETE")
+	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	for _, v := range d.Get("members").([]interface{}) {
+		if err := c.resourceCiscoNativePortChannelRelease(ctx, d, v.(string)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "DELETE",
+		Path:    resourceCiscoNativePortChannelPath(d),
+		Config:  c.Config,
+		Device:  d.Get("host").(string),
+	}
+	if _, err := iosxe.SingleSession(svc); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return diags
+}
+
+func resourceCiscoNativePortChannelImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco Port Channel IMPORT")
+	parts, err := importHostID(d.Id(), "<host>:<port_channel>")
+	if err != nil {
+		return nil, err
+	}
+	id, err := importInt(strings.TrimPrefix(parts[1], "Port-channel"), "port_channel")
+	if err != nil {
+		return nil, err
+	}
+	d.Set("host", parts[0])
+	d.Set("port_channel", id)
+	d.SetId(fmt.Sprintf("%v_Port-channel%v", parts[0], id))
+	return importRead(ctx, d, meta, resourceCiscoNativePortChannelRead)
+}
+
+func resourceCiscoNativePortChannelPath(d *schema.ResourceData) string {
+	return interfacePath(fmt.Sprintf("Port-channel%v", d.Get("port_channel").(int)))
+}
+
+// resourceCiscoNativePortChannelMembers returns the interfaces of the host in the channel-group and the LACP mode
+func (c *providerClient) resourceCiscoNativePortChannelMembers(ctx context.Context, d *schema.ResourceData) ([]interface{}, string, error) {
+	body, err := c.readHost(ctx, d, "/data/Cisco-IOS-XE-native:native/interface")
+	data := &struct {
+		Interface map[string]json.RawMessage `json:"Cisco-IOS-XE-native:interface"`
+	}{}
+	if err == nil {
+		err = unmarshalBody(body, data)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	members := []interface{}{}
+	mode := ""
+	for name, raw := range data.Interface {
+		// only the Ethernet lists, the GET also returns containers like Port-channel-subinterface
+		list := name[strings.LastIndex(name, ":")+1:]
+		if !isEthernetList(list) {
+			continue
+		}
+		interfaces := []port_channel.CiscoIOSXENativePortChannelMember{}
+		if err := json.Unmarshal(raw, &interfaces); err != nil {
+			continue
+		}
+		for _, i := range interfaces {
+			if i.ChannelGroup != nil && i.ChannelGroup.Number == d.Get("port_channel").(int) {
+				members = append(members, fmt.Sprintf("%v%v", list, i.Name))
+				mode = i.ChannelGroup.Mode
+			}
+		}
+	}
+	return members, mode, nil
+}
+
+// resourceCiscoNativePortChannelData returns the Port-channel followed by its member interfaces
+func (*providerClient) resourceCiscoNativePortChannelData(d *schema.ResourceData) []payloadStep {
+	l2 := d.Get("mode").(string) == "l2"
+	pc := port_channel.CiscoIOSXENativePortChannelInterface{
+		Name:        d.Get("port_channel").(int),
+		Description: d.Get("description").(string),
+		Switchport:  port_channel.CiscoIOSXENativeSwitchportConf{Switchport: l2},
+	}
+	if v, ok := d.GetOk("ipv4_address"); ok && !l2 {
+		pc.IP.Address = &port_channel.CiscoIOSXENativePortChannelAddress{
+			Primary: port_channel.CiscoIOSXENativePortChannelPrimary{Address: v.(string), Mask: d.Get("ipv4_mask").(string)},
+		}
+	}
+	if v, ok := d.GetOk("min_links"); ok {
+		pc.PortChannel = &port_channel.CiscoIOSXEEthernetPortChannel{MinLinks: v.(int)}
+	}
+
+	members := &port_channel.CiscoIOSXENativePortChannelMembers{Interface: make(map[string][]port_channel.CiscoIOSXENativePortChannelMember)}
+	for _, v := range d.Get("members").([]interface{}) {
+		list, id := interfaceName(v.(string))
+		members.Interface[list] = append(members.Interface[list], port_channel.CiscoIOSXENativePortChannelMember{
+			Name:        id,
+			Description: fmt.Sprintf("Member of Port-channel%v", d.Get("port_channel").(int)),
+			Switchport:  port_channel.CiscoIOSXENativeSwitchportConf{Switchport: l2},
+			ChannelGroup: &port_channel.CiscoIOSXEEthernetChannelGroup{
+				Number: d.Get("port_channel").(int),
+				Mode:   d.Get("lacp_mode").(string),
+			},
+		})
+	}
+
+	return []payloadStep{
+		newPayloadStep(resourceCiscoNativePortChannelPath(d), &port_channel.CiscoIOSXENativePortChannel{
+			PortChannel: []port_channel.CiscoIOSXENativePortChannelInterface{pc},
+		}),
+		newPayloadStep("/data/Cisco-IOS-XE-native:native/interface", members),
+	}
+}
+
+// resourceCiscoNativePortChannelApply PATCHes the Port-channel and then bundles the members
+func (c *providerClient) resourceCiscoNativePortChannelApply(ctx context.Context, d *schema.ResourceData) error {
+	steps := c.resourceCiscoNativePortChannelData(d)
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "PATCH",
+		Config:  c.Config,
+		Device:  d.Get("host").(string),
+	}
+	payload, lines := stepsPayload(steps)
+	if svc.Config.Debug {
+		svc.Payload = payload
+		svc.CLI = lines
+		debugPayload(fmt.Sprintf("port_channel_%v_%v", svc.Device, d.Get("port_channel").(int)), svc)
+	}
+	for _, step := range steps {
+		svc.Path = step.Path
+		svc.Payload = step.Payload
+		svc.CLI = step.CLI
+		if _, err := iosxe.SingleSession(svc); err != nil {
+			return err
+		}
+	}
+	svc.Payload = payload
+	svc.CLI = lines
+	return c.setHostPreview(d, svc)
+}
+
+// resourceCiscoNativePortChannelRelease removes the member from the channel-group, the physical interface stays
+func (c *providerClient) resourceCiscoNativePortChannelRelease(ctx context.Context, d *schema.ResourceData, member string) error {
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "DELETE",
+		Config:  c.Config,
+		Device:  d.Get("host").(string),
+	}
+	for _, leaf := range []string{"/Cisco-IOS-XE-ethernet:channel-group", "/description", "/switchport-conf"} {
+		svc.Path = interfacePath(member) + leaf
+		if _, err := iosxe.SingleSession(svc); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
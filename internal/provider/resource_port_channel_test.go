@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/simulator"
+)
+
+func TestAccCiscoEvpnPortChannel(t *testing.T) {
+	f := newTestAccFabric(t, 1, 1)
+	border := []*simulator.Device{f.Leafs[0]}
+	pc := "Cisco-IOS-XE-native:native/interface/Port-channel=10"
+	member := "Cisco-IOS-XE-native:native/interface/TenGigabitEthernet=1%2F1%2F2"
+	subint := "Cisco-IOS-XE-native:native/interface/Port-channel-subinterface/Port-channel=10.253"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckDevicesRemoved(border, pc),
+			testAccCheckDevicesRemoved(border, subint),
+			testAccCheckDevicesRemoved(border, member+"/Cisco-IOS-XE-ethernet:channel-group"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCiscoEvpnPortChannelConfig(f, `"TenGigabitEthernet1/1/1"`, "l2", "active", `"100.119.10.1"`, "null"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ipv4_address and ipv4_mask are only supported with mode l3"),
+			},
+			{
+				Config: testAccCiscoEvpnPortChannelConfig(f, `"TenGigabitEthernet1/1/1", "TenGigabitEthernet1/1/2"`, "l3", "active", `"100.119.10.1"`, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDevices(border, pc, `"switchport":false`, `"address":"100.119.10.1"`, `"min-links":2`),
+					testAccCheckDevices(border, member, `"number":10`, `"mode":"active"`, `"description":"Member of Port-channel10"`),
+					testAccCheckDevices(border, subint, `"address":"100.119.253.10"`, `"vlan-id":253`),
+					resource.TestCheckResourceAttr("ciscoevpn_subinterface.test", "id", "Port-channel-subinterface/Port-channel/10.253"),
+					resource.TestMatchResourceAttr("ciscoevpn_port_channel.test", "cli_preview."+f.Leafs[0].Host(), regexp.MustCompile(`channel-group 10 mode active`)),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnPortChannelConfig(f, `"TenGigabitEthernet1/1/1"`, "l3", "passive", "null", "null"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDevicesRemoved(border, member+"/Cisco-IOS-XE-ethernet:channel-group"),
+					testAccCheckDevicesRemoved(border, pc+"/ip/address"),
+					testAccCheckDevicesRemoved(border, pc+"/Cisco-IOS-XE-ethernet:port-channel"),
+					testAccCheckDevices(border, "Cisco-IOS-XE-native:native/interface/TenGigabitEthernet=1%2F1%2F1", `"mode":"passive"`),
+				),
+			},
+			{
+				ResourceName:      "ciscoevpn_port_channel.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%v:Port-channel10", f.Leafs[0].Host()),
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:            "ciscoevpn_subinterface.test",
+				ImportState:             true,
+				ImportStateId:           fmt.Sprintf("%v:Port-channel10.253", f.Leafs[0].Host()),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"ipv4_remote"},
+			},
+		},
+	})
+}
+
+func testAccCiscoEvpnPortChannelConfig(f *testAccFabric, members string, mode string, lacp string, address string, minLinks string) string {
+	mask := "null"
+	if address != "null" {
+		mask = `"255.255.255.254"`
+	}
+	return f.Config() + fmt.Sprintf(`
+resource "ciscoevpn_port_channel" "test" {
+  host         = %q
+  port_channel = 10
+  members      = [%v]
+  mode         = %q
+  lacp_mode    = %q
+  ipv4_address = %v
+  ipv4_mask    = %v
+  min_links    = %v
+}
+
+resource "ciscoevpn_subinterface" "test" {
+  host         = %q
+  ethernet     = "${ciscoevpn_port_channel.test.port_channel}.253"
+  port_channel = true
+  dot1q        = 253
+  ipv4_address = "100.119.253.10"
+  ipv4_mask    = "255.255.255.252"
+}
+`, f.Leafs[0].Host(), members, mode, lacp, address, mask, minLinks, f.Leafs[0].Host())
+}
@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -22,6 +23,9 @@ func resourceCiscoNativeSubInterface() *schema.Resource {
 		ReadContext:   resourceCiscoNativeSubInterfaceRead,
 		UpdateContext: resourceCiscoNativeSubInterfaceUpdate,
 		DeleteContext: resourceCiscoNativeSubInterfaceDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativeSubInterfaceImport,
+		},
 		Schema: map[string]*schema.Schema{
 			"host": {
 				Type:         schema.TypeString,
@@ -38,8 +42,15 @@ func resourceCiscoNativeSubInterface() *schema.Resource {
 				Required: true,
 			},
 			"interface_speed": {
-				Type:     schema.TypeInt,
-				Required: true,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ExactlyOneOf: []string{"interface_speed", "port_channel"},
+			},
+			"port_channel": {
+				Type:         schema.TypeBool,
+				Optional:     true,
+				Description:  "The parent is a Port-channel (e.g. ciscoevpn_port_channel), ethernet is the subinterface e.g. 10.100",
+				ExactlyOneOf: []string{"interface_speed", "port_channel"},
 			},
 			"dot1q": {
 				Type:     schema.TypeInt,
@@ -64,6 +75,8 @@ func resourceCiscoNativeSubInterface() *schema.Resource {
 				Optional:     true,
 				ValidateFunc: validation.IsIPv4Address,
 			},
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
 		},
 	}
 }
@@ -76,9 +89,10 @@ func resourceCiscoNativeSubInterfaceCreate(ctx context.Context, d *schema.Resour
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Provider: c.Provider,
-		Device:   d.Get("host").(string),
+		Context: ctx,
+		Method:  "PATCH",
+		Config:  c.Config,
+		Device:  d.Get("host").(string),
 	}
 
 	data, uri := c.resourceCiscoNativeSubInterfaceData(d)
@@ -90,23 +104,54 @@ func resourceCiscoNativeSubInterfaceCreate(ctx context.Context, d *schema.Resour
 	if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 		svc.Payload = string(b)
 	}
+	svc.CLI = data.CLI()
 
-	if svc.Provider.Get("debug").(bool) {
-		debugJson(fmt.Sprintf("subint_%v", d.Get("ipv4_address").(string)), svc.Payload)
+	if svc.Config.Debug {
+		debugPayload(fmt.Sprintf("subint_%v", d.Get("ipv4_address").(string)), svc)
 	}
 
 	_, err = iosxe.SingleSession(svc)
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	if err = c.setHostPreview(d, svc); err != nil {
+		return diag.FromErr(err)
+	}
 
 	d.SetId(fmt.Sprintf("%v/%v", uri, d.Get("ethernet").(string)))
 	return diags
 }
 
 func resourceCiscoNativeSubInterfaceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	log.Println("[DEBUG] TODO")
+	log.Println("[DEBUG] Cisco Sub Interface READ")
 	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	body, err := c.readHost(ctx, d, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/%v", subInterfaceUri(d.Id())))
+	data := &subinterface.CiscoIOSXENativeEthernet{}
+	if err == nil {
+		err = unmarshalBody(body, data)
+	}
+	if errors.Is(err, iosxe.ErrNotFound) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	state := c.resourceCiscoNativeSubInterfaceState(data)
+	if state == nil {
+		d.SetId("")
+		return diags
+	}
+	if err = setDriftState(d, map[string]map[string]interface{}{d.Get("host").(string): state}); err != nil {
+		return diag.FromErr(err)
+	}
+	cli, _ := c.resourceCiscoNativeSubInterfaceData(d)
+	if err = setHostCli(d, cli.CLI()); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
@@ -125,6 +170,11 @@ func resourceCiscoNativeSubInterfaceUpdate(ctx context.Context, d *schema.Resour
 		d.Set("interface_speed", oldState)
 		return diag.Errorf("Not supported to change Interface Speed of Sub Interface")
 	}
+	if d.HasChange("port_channel") {
+		oldState, _ := d.GetChange("port_channel")
+		d.Set("port_channel", oldState)
+		return diag.Errorf("Not supported to change Port-channel parent of Sub Interface")
+	}
 	if d.HasChange("roles") {
 		oldState, _ := d.GetChange("roles")
 		d.Set("roles", oldState)
@@ -133,9 +183,10 @@ func resourceCiscoNativeSubInterfaceUpdate(ctx context.Context, d *schema.Resour
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Provider: c.Provider,
-		Device:   d.Get("host").(string),
+		Context: ctx,
+		Method:  "PATCH",
+		Config:  c.Config,
+		Device:  d.Get("host").(string),
 	}
 
 	data, uri := c.resourceCiscoNativeSubInterfaceData(d)
@@ -147,15 +198,19 @@ func resourceCiscoNativeSubInterfaceUpdate(ctx context.Context, d *schema.Resour
 	if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 		svc.Payload = string(b)
 	}
+	svc.CLI = data.CLI()
 
-	if svc.Provider.Get("debug").(bool) {
-		debugJson(fmt.Sprintf("subint_%v", d.Get("ipv4_address").(string)), svc.Payload)
+	if svc.Config.Debug {
+		debugPayload(fmt.Sprintf("subint_%v", d.Get("ipv4_address").(string)), svc)
 	}
 
 	_, err = iosxe.SingleSession(svc)
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	if err = c.setHostPreview(d, svc); err != nil {
+		return diag.FromErr(err)
+	}
 
 	d.SetId(fmt.Sprintf("%v/%v", uri, d.Get("ethernet").(string)))
 	return diags
@@ -169,17 +224,13 @@ func resourceCiscoNativeSubInterfaceDelete(ctx context.Context, d *schema.Resour
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "DELETE",
-		Provider: c.Provider,
-		Device:   d.Get("host").(string),
+		Context: ctx,
+		Method:  "DELETE",
+		Config:  c.Config,
+		Device:  d.Get("host").(string),
 	}
 
-	id := d.Id()
-	ethernet := strings.Split(id, "/")
-	slots := strings.Split(id, ethernet[0])
-	slot := slots[1]
-	slot = strings.ReplaceAll(slot[1:], "/", "%2F")
-	uri = fmt.Sprintf("%v=%v", ethernet[0], slot)
+	uri = subInterfaceUri(d.Id())
 	svc.Path = fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/%v", uri)
 
 	_, err = iosxe.SingleSession(svc)
@@ -191,6 +242,29 @@ func resourceCiscoNativeSubInterfaceDelete(ctx context.Context, d *schema.Resour
 	return diags
 }
 
+func resourceCiscoNativeSubInterfaceImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco Sub Interface IMPORT")
+	parts, err := importHostID(d.Id(), "<host>:<interface>")
+	if err != nil {
+		return nil, err
+	}
+	d.Set("host", parts[0])
+	if ethernet := strings.TrimPrefix(parts[1], "Port-channel"); ethernet != parts[1] {
+		d.Set("port_channel", true)
+		d.Set("ethernet", ethernet)
+		d.SetId(fmt.Sprintf("%v/%v", portChannelSubinterfaces, ethernet))
+		return importRead(ctx, d, meta, resourceCiscoNativeSubInterfaceRead)
+	}
+	speed, ethernet, err := interfaceSpeed(parts[1], "TwentyFiveGigE1/0/1.100")
+	if err != nil {
+		return nil, err
+	}
+	d.Set("interface_speed", speed)
+	d.Set("ethernet", ethernet)
+	d.SetId(fmt.Sprintf("%v/%v", interfaceSpeeds[speed], ethernet))
+	return importRead(ctx, d, meta, resourceCiscoNativeSubInterfaceRead)
+}
+
 func (*providerClient) resourceCiscoNativeSubInterfaceData(d *schema.ResourceData) (*subinterface.CiscoIOSXENativeEthernet, string) {
 	var uri string
 	data := &subinterface.CiscoIOSXENativeEthernet{}
@@ -198,6 +272,7 @@ func (*providerClient) resourceCiscoNativeSubInterfaceData(d *schema.ResourceDat
 	ethernet := &subinterface.CiscoIOSXENativeEthernetInterface{}
 	ethernet.Name = d.Get("ethernet").(string)
 	ethernet.Description = d.Get("description").(string)
+	ethernet.IP.Address = &subinterface.CiscoIOSXENativeEthernetInterfaceAddress{}
 	ethernet.IP.Address.Primary.Address = d.Get("ipv4_address").(string)
 	ethernet.IP.Address.Primary.Mask = d.Get("ipv4_mask").(string)
 
@@ -208,28 +283,109 @@ func (*providerClient) resourceCiscoNativeSubInterfaceData(d *schema.ResourceDat
 		ethernet.Encapsulation.Dot1Q.VlanID = v.(int)
 	}
 
-	// Handle IOS-XE Interface naming
-	switch d.Get("interface_speed").(int) {
+	if d.Get("port_channel").(bool) {
+		data.PortChannel = append(data.PortChannel, *ethernet)
+		return data, portChannelSubinterfaces
+	}
+	uri = ethernetData(data, d.Get("interface_speed").(int), ethernet)
+	return data, uri
+
+}
+
+// ethernetData adds the interface to the list of its IOS-XE interface naming and returns the name
+func ethernetData(data *subinterface.CiscoIOSXENativeEthernet, speed int, ethernet *subinterface.CiscoIOSXENativeEthernetInterface) string {
+	switch speed {
 	case 10:
 		data.Ten = append(data.Ten, *ethernet)
-		uri = "TenGigabitEthernet"
 	case 25:
 		data.TwentyFive = append(data.TwentyFive, *ethernet)
-		uri = "TwentyFiveGigE"
 	case 40:
 		data.Forty = append(data.Forty, *ethernet)
-		uri = "FortyGigabitEthernet"
 	case 100:
 		data.Hundred = append(data.Hundred, *ethernet)
-		uri = "HundredGigE"
 	case 400:
 		data.FourHundred = append(data.FourHundred, *ethernet)
-		uri = "FourHundredGigE"
 	default:
 		data.One = append(data.One, *ethernet)
-		uri = "GigabitEthernet"
 	}
+	if uri := interfaceSpeeds[speed]; uri != "" {
+		return uri
+	}
+	return interfaceSpeeds[1]
+}
 
-	return data, uri
+// interfaceSpeeds maps the interface_speed to the IOS-XE interface naming
+var interfaceSpeeds = map[int]string{
+	1:   "GigabitEthernet",
+	10:  "TenGigabitEthernet",
+	25:  "TwentyFiveGigE",
+	40:  "FortyGigabitEthernet",
+	100: "HundredGigE",
+	400: "FourHundredGigE",
+}
+
+// portChannelSubinterfaces is the list of the Port-channel subinterfaces, e.g. Port-channel10.100
+const portChannelSubinterfaces = "Port-channel-subinterface/Port-channel"
+
+// subInterfaceUri converts the ID (e.g. TenGigabitEthernet/1/0/1.100) in to the RESTCONF list key
+func subInterfaceUri(id string) string {
+	if name := strings.TrimPrefix(id, portChannelSubinterfaces+"/"); name != id {
+		return fmt.Sprintf("%v=%v", portChannelSubinterfaces, name)
+	}
+	ethernet := strings.Split(id, "/")
+	slots := strings.Split(id, ethernet[0])
+	slot := slots[1]
+	slot = strings.ReplaceAll(slot[1:], "/", "%2F")
+	return fmt.Sprintf("%v=%v", ethernet[0], slot)
+}
+
+// interfaceSpeed returns the interface_speed and the ethernet of an interface name, e.g. TenGigabitEthernet1/0/1
+func interfaceSpeed(name string, example string) (int, string, error) {
+	i := strings.IndexAny(name, "0123456789")
+	if i < 1 {
+		return 0, "", fmt.Errorf("unexpected interface %q, expected e.g. %v", name, example)
+	}
+	for k, v := range interfaceSpeeds {
+		if v == name[:i] {
+			return k, name[i:], nil
+		}
+	}
+	return 0, "", fmt.Errorf("unsupported interface type %q", name[:i])
+}
+
+// isEthernetList returns true for the interface lists of the Ethernet speeds, e.g. TenGigabitEthernet
+func isEthernetList(list string) bool {
+	for _, v := range interfaceSpeeds {
+		if v == list {
+			return true
+		}
+	}
+	return false
+}
 
+// ethernetInterfaces returns the interfaces of all IOS-XE interface namings and Port-channels
+func ethernetInterfaces(data *subinterface.CiscoIOSXENativeEthernet) []subinterface.CiscoIOSXENativeEthernetInterface {
+	var ethernet []subinterface.CiscoIOSXENativeEthernetInterface
+	ethernet = append(ethernet, data.FourHundred...)
+	ethernet = append(ethernet, data.Hundred...)
+	ethernet = append(ethernet, data.Forty...)
+	ethernet = append(ethernet, data.TwentyFive...)
+	ethernet = append(ethernet, data.Ten...)
+	ethernet = append(ethernet, data.One...)
+	ethernet = append(ethernet, data.PortChannel...)
+	return ethernet
+}
+
+func (*providerClient) resourceCiscoNativeSubInterfaceState(data *subinterface.CiscoIOSXENativeEthernet) map[string]interface{} {
+	ethernet := ethernetInterfaces(data)
+	if len(ethernet) == 0 {
+		return nil
+	}
+	return map[string]interface{}{
+		"description":  ethernet[0].Description,
+		"dot1q":        ethernet[0].Encapsulation.Dot1Q.VlanID,
+		"vrf":          ethernet[0].Vrf.Forwarding,
+		"ipv4_address": ethernet[0].IP.PrimaryAddress().Address,
+		"ipv4_mask":    ethernet[0].IP.PrimaryAddress().Mask,
+	}
 }
@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/simulator"
+)
+
+func TestAccCiscoEvpnSubInterface(t *testing.T) {
+	f := newTestAccFabric(t, 1, 1)
+	leaf := []*simulator.Device{f.Leafs[0]}
+	path := "Cisco-IOS-XE-native:native/interface/TenGigabitEthernet=1%2F1%2F1.253"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDevicesRemoved(leaf, path),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnSubInterfaceConfig(f, "100.119.253.10"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_subinterface.test", "id", "TenGigabitEthernet/1/1/1.253"),
+					testAccCheckDevices(leaf, path, `"address":"100.119.253.10"`, `"vlan-id":253`, `"green"`),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnSubInterfaceConfig(f, "100.119.253.14"),
+				Check:  testAccCheckDevices(leaf, path, `"address":"100.119.253.14"`),
+			},
+			{
+				ResourceName:      "ciscoevpn_subinterface.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%v:TenGigabitEthernet1/1/1.253", f.Leafs[0].Host()),
+				ImportStateVerify: true,
+				// The remote address isn't configured on the device
+				ImportStateVerifyIgnore: []string{"ipv4_remote"},
+			},
+		},
+	})
+}
+
+func testAccCiscoEvpnSubInterfaceConfig(f *testAccFabric, address string) string {
+	return f.Config() + fmt.Sprintf(`
+resource "ciscoevpn_vrf" "test" {
+  roles = ["leafs"]
+  name  = "green"
+  rd    = "1:1"
+}
+
+resource "ciscoevpn_subinterface" "test" {
+  host            = %q
+  ethernet        = "1/1/1.253"
+  interface_speed = 10
+  dot1q           = 253
+  vrf             = ciscoevpn_vrf.test.name
+  ipv4_address    = %q
+  ipv4_mask       = "255.255.255.252"
+  ipv4_remote     = "100.119.253.9"
+}
+`, f.Leafs[0].Host(), address)
+}
@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 
@@ -17,10 +18,14 @@ import (
 func resourceCiscoNativeSvi() *schema.Resource {
 	return &schema.Resource{
 		Description:   "Cisco SVI",
-		CreateContext: resourceCiscoNativeSviCreate,
+		CreateContext: transactional(resourceCiscoNativeSviCreate),
 		ReadContext:   resourceCiscoNativeSviRead,
-		UpdateContext: resourceCiscoNativeSviUpdate,
-		DeleteContext: resourceCiscoNativeSviDelete,
+		UpdateContext: transactional(resourceCiscoNativeSviUpdate),
+		DeleteContext: transactional(resourceCiscoNativeSviDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativeSviImport,
+		},
+		CustomizeDiff: devicesCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"roles": {
 				Type:     schema.TypeList,
@@ -51,17 +56,24 @@ func resourceCiscoNativeSvi() *schema.Resource {
 				Type:         schema.TypeString,
 				Optional:     true,
 				ValidateFunc: validation.IsIPv4Address,
+				ExactlyOneOf: []string{"ipv4_address", "unnumbered"},
+				RequiredWith: []string{"ipv4_mask"},
 			},
 			"ipv4_mask": {
 				Type:         schema.TypeString,
 				Optional:     true,
 				ValidateFunc: validation.IsIPv4Address,
+				RequiredWith: []string{"ipv4_address"},
 			},
 			"unnumbered": {
 				Type:         schema.TypeString,
 				Optional:     true,
 				ValidateFunc: validation.StringIsNotWhiteSpace,
+				ExactlyOneOf: []string{"ipv4_address", "unnumbered"},
 			},
+			"devices":           devicesSchema(),
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
 		},
 	}
 }
@@ -73,12 +85,14 @@ func resourceCiscoNativeSviCreate(ctx context.Context, d *schema.ResourceData, m
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Path:     "/data/Cisco-IOS-XE-native:native/interface/Vlan",
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    "/data/Cisco-IOS-XE-native:native/interface/Vlan",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 
+	devices := getDevices(d)
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
@@ -90,23 +104,52 @@ func resourceCiscoNativeSviCreate(ctx context.Context, d *schema.ResourceData, m
 		if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 			svc.Payload = string(b)
 		}
-		if svc.Provider.Get("debug").(bool) {
-			debugJson(fmt.Sprintf("svi_%v_%v", svc.Role, d.Get("svi_id").(int)), svc.Payload)
+		svc.CLI = data.CLI()
+		if svc.Config.Debug {
+			debugPayload(fmt.Sprintf("svi_%v_%v", svc.Role, d.Get("svi_id").(int)), svc)
 		}
 
-		_, err = iosxe.MultiSession(svc)
+		err = c.applyRole(svc, devices)
 		if err != nil {
+			setDevices(d, devices)
 			return diag.FromErr(err)
 		}
 	}
 
 	d.SetId(fmt.Sprintf("svi_%v", d.Get("svi_id").(int)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
 func resourceCiscoNativeSviRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	log.Println("[DEBUG] Cisco SVI READ") // TODO
+	log.Println("[DEBUG] Cisco SVI READ")
 	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	bodies, err := c.readRoles(ctx, d, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v", d.Get("svi_id").(int)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	states := make(map[string]map[string]interface{})
+	for host, body := range bodies {
+		data := &svi.CiscoIOSXENativeSvis{}
+		if err = unmarshalBody(body, data); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		if len(data.CiscoIOSXENativeVlan) == 0 {
+			log.Printf("[DEBUG] SVI %v not found on: %v\n", d.Get("svi_id").(int), host)
+			d.SetId("")
+			return diags
+		}
+		states[host] = c.resourceCiscoNativeSviState(data)
+	}
+
+	if err = setDevicesDriftState(d, states); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
@@ -120,18 +163,20 @@ func resourceCiscoNativeSviUpdate(ctx context.Context, d *schema.ResourceData, m
 		d.Set("svi_id", oldState)
 		return diag.Errorf("Not supported to change VLAN ID")
 	}
-	if d.HasChange("roles") {
-		oldState, _ := d.GetChange("roles")
-		d.Set("roles", oldState)
-		return diag.Errorf("Not supported to change Roles")
-	}
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Path:     fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v", d.Get("svi_id").(int)),
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v", d.Get("svi_id").(int)),
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	devices := getDevices(d)
+	if err = c.removeHosts(ctx, d, devices, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v", d.Get("svi_id").(int))); err != nil {
+		setDevices(d, devices)
+		return diag.FromErr(err)
 	}
 
 	roles := d.Get("roles").([]interface{})
@@ -145,17 +190,22 @@ func resourceCiscoNativeSviUpdate(ctx context.Context, d *schema.ResourceData, m
 		if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 			svc.Payload = string(b)
 		}
-		if svc.Provider.Get("debug").(bool) {
-			debugJson(fmt.Sprintf("svi_%v_%v", svc.Role, d.Get("svi_id").(int)), svc.Payload)
+		svc.CLI = data.CLI()
+		if svc.Config.Debug {
+			debugPayload(fmt.Sprintf("svi_%v_%v", svc.Role, d.Get("svi_id").(int)), svc)
 		}
 
-		_, err = iosxe.MultiSession(svc)
+		err = c.applyRole(svc, devices)
 		if err != nil {
+			setDevices(d, devices)
 			return diag.FromErr(err)
 		}
 	}
 
 	d.SetId(fmt.Sprintf("svi_%v", d.Get("svi_id").(int)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
@@ -166,16 +216,18 @@ func resourceCiscoNativeSviDelete(ctx context.Context, d *schema.ResourceData, m
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "DELETE",
-		Path:     fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v", d.Get("svi_id").(int)),
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "DELETE",
+		Path:    fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Vlan=%v", d.Get("svi_id").(int)),
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 
+	devices := getDevices(d)
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
-		_, err = iosxe.MultiSession(svc)
+		err = c.deleteRole(svc, devices)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -185,6 +237,25 @@ func resourceCiscoNativeSviDelete(ctx context.Context, d *schema.ResourceData, m
 	return diags
 }
 
+func resourceCiscoNativeSviImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco SVI IMPORT")
+	c, _ := meta.(*providerClient)
+	parts, err := importID(d.Id(), "<roles>:<svi_id>")
+	if err != nil {
+		return nil, err
+	}
+	id, err := importInt(parts[1], "svi_id")
+	if err != nil {
+		return nil, err
+	}
+	if err = c.importRoles(d, parts[0]); err != nil {
+		return nil, err
+	}
+	d.Set("svi_id", id)
+	d.SetId(fmt.Sprintf("svi_%v", id))
+	return importRead(ctx, d, meta, resourceCiscoNativeSviRead)
+}
+
 func (*providerClient) resourceCiscoNativeSviData(d *schema.ResourceData) *svi.CiscoIOSXENativeSvis {
 	data := &svi.CiscoIOSXENativeSvis{}
 	sviCfg := &svi.CiscoIOSXENativeSvi{}
@@ -206,10 +277,28 @@ func (*providerClient) resourceCiscoNativeSviData(d *schema.ResourceData) *svi.C
 		sviCfg.IP.Address = sviIp
 	} else if v, ok := d.GetOk("unnumbered"); ok {
 		sviCfg.IP.Unnumbered = v.(string)
-	} else {
-		log.Panicln("[PANIC] Either IPv4 or Unnumbered has to be used")
 	}
 
 	data.CiscoIOSXENativeVlan = append(data.CiscoIOSXENativeVlan, *sviCfg)
 	return data
 }
+
+func (*providerClient) resourceCiscoNativeSviState(data *svi.CiscoIOSXENativeSvis) map[string]interface{} {
+	sviCfg := data.CiscoIOSXENativeVlan[0]
+	state := map[string]interface{}{
+		"autostate":    sviCfg.AutoState,
+		"description":  sviCfg.Description,
+		"vrf":          "",
+		"ipv4_address": "",
+		"ipv4_mask":    "",
+		"unnumbered":   sviCfg.IP.Unnumbered,
+	}
+	if sviCfg.Vrf != nil {
+		state["vrf"] = sviCfg.Vrf.Forwarding
+	}
+	if sviCfg.IP.Address != nil {
+		state["ipv4_address"] = sviCfg.IP.Address.Primary.Address
+		state["ipv4_mask"] = sviCfg.IP.Address.Primary.Mask
+	}
+	return state
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnSvi(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	path := "Cisco-IOS-XE-native:native/interface/Vlan=101"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDevicesRemoved(f.Leafs, path),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnSviConfig(f, "100.119.101.1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_svi.test", "vrf", "green"),
+					testAccCheckDevices(f.Leafs, path, `"address":"100.119.101.1"`, `"green"`),
+					testAccCheckDevicesRemoved(f.Spines, path),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnSviConfig(f, "100.119.101.254"),
+				Check:  testAccCheckDevices(f.Leafs, path, `"address":"100.119.101.254"`),
+			},
+			{
+				// A failed leaf isn't hidden by a leaf without the SVI, the SVI stays in state
+				PreConfig: func() {
+					f.Leafs[0].FailRequests("GET", "interface/Vlan", 1, 500)
+					if err := f.Leafs[1].Delete(path); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:      testAccCiscoEvpnSviConfig(f, "100.119.101.254"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("1 of the hosts failed"),
+			},
+			{
+				// Without the failure the SVI missing on a leaf is created again
+				Config: testAccCiscoEvpnSviConfig(f, "100.119.101.254"),
+				Check:  testAccCheckDevices(f.Leafs, path, `"address":"100.119.101.254"`),
+			},
+		},
+	})
+}
+
+func testAccCiscoEvpnSviConfig(f *testAccFabric, address string) string {
+	return f.Config() + fmt.Sprintf(`
+resource "ciscoevpn_vrf" "test" {
+  roles = ["leafs"]
+  name  = "green"
+  rd    = "1:1"
+}
+
+resource "ciscoevpn_svi" "test" {
+  roles        = ["leafs"]
+  svi_id       = 101
+  vrf          = ciscoevpn_vrf.test.name
+  ipv4_address = %q
+  ipv4_mask    = "255.255.255.0"
+}
+`, address)
+}
@@ -3,8 +3,10 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -17,10 +19,14 @@ import (
 func resourceCiscoNativeVlan() *schema.Resource {
 	return &schema.Resource{
 		Description:   "Cisco VLAN",
-		CreateContext: resourceCiscoNativeVlanCreate,
+		CreateContext: transactional(resourceCiscoNativeVlanCreate),
 		ReadContext:   resourceCiscoNativeVlanRead,
-		UpdateContext: resourceCiscoNativeVlanUpdate,
-		DeleteContext: resourceCiscoNativeVlanDelete,
+		UpdateContext: transactional(resourceCiscoNativeVlanUpdate),
+		DeleteContext: transactional(resourceCiscoNativeVlanDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativeVlanImport,
+		},
+		CustomizeDiff: resourceCiscoNativeVlanCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"roles": {
 				Type:     schema.TypeList,
@@ -47,10 +53,49 @@ func resourceCiscoNativeVlan() *schema.Resource {
 				Type:     schema.TypeInt,
 				Required: true,
 			},
+			"devices":           devicesSchema(),
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
 		},
 	}
 }
 
+// plannedVlans holds the VLANs planned on each host, the resources using the VLANs
+// are planned after the ciscoevpn_vlan resources they reference
+type plannedVlans struct {
+	mu    sync.Mutex
+	hosts map[string]map[int]bool
+}
+
+func (p *plannedVlans) add(host string, id int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.hosts == nil {
+		p.hosts = make(map[string]map[int]bool)
+	}
+	if p.hosts[host] == nil {
+		p.hosts[host] = make(map[int]bool)
+	}
+	p.hosts[host][id] = true
+}
+
+func (p *plannedVlans) has(host string, id int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.hosts[host][id]
+}
+
+// resourceCiscoNativeVlanCustomizeDiff records the VLAN on the hosts of the roles for the plan of the ports
+func resourceCiscoNativeVlanCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	c, _ := meta.(*providerClient)
+	if d.NewValueKnown("vlan_id") && d.NewValueKnown("roles") {
+		for host := range c.roleHosts(d.Get("roles").([]interface{})) {
+			c.vlans.add(host, d.Get("vlan_id").(int))
+		}
+	}
+	return devicesCustomizeDiff(ctx, d, meta)
+}
+
 func resourceCiscoNativeVlanCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Println("[DEBUG] Cisco VLAN CREATE")
 	var diags diag.Diagnostics
@@ -58,12 +103,14 @@ func resourceCiscoNativeVlanCreate(ctx context.Context, d *schema.ResourceData,
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Path:     "/data/Cisco-IOS-XE-native:native/vlan",
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    "/data/Cisco-IOS-XE-native:native/vlan",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 
+	devices := getDevices(d)
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
@@ -75,23 +122,53 @@ func resourceCiscoNativeVlanCreate(ctx context.Context, d *schema.ResourceData,
 		if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 			svc.Payload = string(b)
 		}
-		if svc.Provider.Get("debug").(bool) {
-			debugJson(fmt.Sprintf("vlan_%v_%v", svc.Role, d.Get("vlan_id").(int)), svc.Payload)
+		svc.CLI = data.CLI()
+		if svc.Config.Debug {
+			debugPayload(fmt.Sprintf("vlan_%v_%v", svc.Role, d.Get("vlan_id").(int)), svc)
 		}
 
-		_, err = iosxe.MultiSession(svc)
+		err = c.applyRole(svc, devices)
 		if err != nil {
+			setDevices(d, devices)
 			return diag.FromErr(err)
 		}
 	}
 
 	d.SetId(fmt.Sprintf("vlan_%v", d.Get("vlan_id").(int)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
 func resourceCiscoNativeVlanRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	log.Println("[DEBUG] Cisco VLAN READ") // TODO
+	log.Println("[DEBUG] Cisco VLAN READ")
 	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	bodies, err := c.readRoles(ctx, d, "/data/Cisco-IOS-XE-native:native/vlan")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	states := make(map[string]map[string]interface{})
+	for host, body := range bodies {
+		data := &vlan.CiscoIOSXENativeVlans{}
+		if err = unmarshalBody(body, data); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		state := c.resourceCiscoNativeVlanState(d, data)
+		if state == nil {
+			log.Printf("[DEBUG] VLAN %v not found on: %v\n", d.Get("vlan_id").(int), host)
+			d.SetId("")
+			return diags
+		}
+		states[host] = state
+	}
+
+	if err = setDevicesDriftState(d, states); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
@@ -110,18 +187,20 @@ func resourceCiscoNativeVlanUpdate(ctx context.Context, d *schema.ResourceData,
 		d.Set("vni", oldState)
 		return diag.Errorf("Not supported to change VNI ID")
 	}
-	if d.HasChange("roles") {
-		oldState, _ := d.GetChange("roles")
-		d.Set("roles", oldState)
-		return diag.Errorf("Not supported to change Roles")
-	}
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Path:     "/data/Cisco-IOS-XE-native:native/vlan",
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    "/data/Cisco-IOS-XE-native:native/vlan",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	devices := getDevices(d)
+	if err = c.removeHosts(ctx, d, devices, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/vlan/configuration-entry=%v", d.Get("vlan_id").(int))); err != nil {
+		setDevices(d, devices)
+		return diag.FromErr(err)
 	}
 
 	roles := d.Get("roles").([]interface{})
@@ -135,17 +214,22 @@ func resourceCiscoNativeVlanUpdate(ctx context.Context, d *schema.ResourceData,
 		if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 			svc.Payload = string(b)
 		}
-		if svc.Provider.Get("debug").(bool) {
-			debugJson(fmt.Sprintf("vlan_%v_%v", svc.Role, d.Get("vlan_id").(int)), svc.Payload)
+		svc.CLI = data.CLI()
+		if svc.Config.Debug {
+			debugPayload(fmt.Sprintf("vlan_%v_%v", svc.Role, d.Get("vlan_id").(int)), svc)
 		}
 
-		_, err = iosxe.MultiSession(svc)
+		err = c.applyRole(svc, devices)
 		if err != nil {
+			setDevices(d, devices)
 			return diag.FromErr(err)
 		}
 		d.SetId(fmt.Sprintf("vlan_%v", d.Get("vlan_id").(int)))
 	}
 
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
@@ -156,16 +240,18 @@ func resourceCiscoNativeVlanDelete(ctx context.Context, d *schema.ResourceData,
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "DELETE",
-		Path:     fmt.Sprintf("/data/Cisco-IOS-XE-native:native/vlan/configuration-entry=%v", d.Get("vlan_id").(int)),
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "DELETE",
+		Path:    fmt.Sprintf("/data/Cisco-IOS-XE-native:native/vlan/configuration-entry=%v", d.Get("vlan_id").(int)),
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 
+	devices := getDevices(d)
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
-		_, err = iosxe.MultiSession(svc)
+		err = c.deleteRole(svc, devices)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -175,6 +261,25 @@ func resourceCiscoNativeVlanDelete(ctx context.Context, d *schema.ResourceData,
 	return diags
 }
 
+func resourceCiscoNativeVlanImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco VLAN IMPORT")
+	c, _ := meta.(*providerClient)
+	parts, err := importID(d.Id(), "<roles>:<vlan_id>")
+	if err != nil {
+		return nil, err
+	}
+	id, err := importInt(parts[1], "vlan_id")
+	if err != nil {
+		return nil, err
+	}
+	if err = c.importRoles(d, parts[0]); err != nil {
+		return nil, err
+	}
+	d.Set("vlan_id", id)
+	d.SetId(fmt.Sprintf("vlan_%v", id))
+	return importRead(ctx, d, meta, resourceCiscoNativeVlanRead)
+}
+
 func (*providerClient) resourceCiscoNativeVlanData(d *schema.ResourceData) *vlan.CiscoIOSXENativeVlans {
 	data := &vlan.CiscoIOSXENativeVlan{}
 	vlanCfg := &vlan.CiscoIOSXEVlanConfigurationEntry{}
@@ -206,3 +311,36 @@ func (*providerClient) resourceCiscoNativeVlanData(d *schema.ResourceData) *vlan
 	data.CiscoIOSXEVlanVlanList = append(data.CiscoIOSXEVlanVlanList, *vlanList)
 	return &vlan.CiscoIOSXENativeVlans{*data}
 }
+
+func (*providerClient) resourceCiscoNativeVlanState(d *schema.ResourceData, data *vlan.CiscoIOSXENativeVlans) map[string]interface{} {
+	var state map[string]interface{}
+	id := d.Get("vlan_id").(int)
+
+	for _, v := range data.CiscoIOSXENativeVlan.CiscoIOSXEVlanVlanList {
+		if v.ID == id {
+			state = make(map[string]interface{})
+			if v.Name == fmt.Sprintf("ManagedByTerraform_%v", id) {
+				state["name"] = "ManagedByTerraform"
+			} else {
+				state["name"] = v.Name
+			}
+		}
+	}
+	if state == nil {
+		return nil
+	}
+
+	state["vni"] = 0
+	state["evpn_instance"] = 0
+	for _, v := range data.CiscoIOSXENativeVlan.CiscoIOSXEVlanConfigurationEntry {
+		if v.VlanID == fmt.Sprintf("%v", id) {
+			if v.Member.EvpnInstance != nil {
+				state["evpn_instance"] = v.Member.EvpnInstance.EvpnInstance
+				state["vni"] = v.Member.EvpnInstance.Vni
+			} else {
+				state["vni"] = v.Member.Vni
+			}
+		}
+	}
+	return state
+}
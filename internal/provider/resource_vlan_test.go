@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnVlan(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	path := "Cisco-IOS-XE-native:native/vlan/configuration-entry=101"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDevicesRemoved(f.Leafs, path),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnVlanConfig(f, "green"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_vlan.test", "vni", "10101"),
+					testAccCheckDevices(f.Leafs, path, `"vni":10101`, `"evpn-instance":101`),
+					testAccCheckDevices(f.Leafs, "Cisco-IOS-XE-native:native/vlan/vlan-list=101", `"name":"green"`),
+					testAccCheckDevicesRemoved(f.Spines, path),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnVlanConfig(f, "blue"),
+				Check:  testAccCheckDevices(f.Leafs, "Cisco-IOS-XE-native:native/vlan/vlan-list=101", `"name":"blue"`),
+			},
+		},
+	})
+}
+
+func testAccCiscoEvpnVlanConfig(f *testAccFabric, name string) string {
+	return f.Config() + fmt.Sprintf(`
+resource "ciscoevpn_evpn_instance" "test" {
+  roles         = ["leafs"]
+  instance_id   = 101
+  vlan_based    = true
+  encapsulation = "vxlan"
+}
+
+resource "ciscoevpn_vlan" "test" {
+  roles         = ["leafs"]
+  vlan_id       = 101
+  name          = %q
+  evpn_instance = ciscoevpn_evpn_instance.test.instance_id
+  vni           = 10101
+}
+`, name)
+}
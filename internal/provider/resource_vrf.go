@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 
@@ -16,10 +17,14 @@ import (
 func resourceCiscoNativeVrf() *schema.Resource {
 	return &schema.Resource{
 		Description:   "Cisco VRF",
-		CreateContext: resourceCiscoNativeVrfCreate,
+		CreateContext: transactional(resourceCiscoNativeVrfCreate),
 		ReadContext:   resourceCiscoNativeVrfRead,
-		UpdateContext: resourceCiscoNativeVrfUpdate,
-		DeleteContext: resourceCiscoNativeVrfDelete,
+		UpdateContext: transactional(resourceCiscoNativeVrfUpdate),
+		DeleteContext: transactional(resourceCiscoNativeVrfDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCiscoNativeVrfImport,
+		},
+		CustomizeDiff: devicesCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"roles": {
 				Type:     schema.TypeList,
@@ -46,6 +51,9 @@ func resourceCiscoNativeVrf() *schema.Resource {
 				Default:  true,
 				Optional: true,
 			},
+			"devices":           devicesSchema(),
+			"rendered_payloads": renderedPayloadsSchema(),
+			"cli_preview":       cliPreviewSchema(),
 		},
 	}
 }
@@ -57,12 +65,14 @@ func resourceCiscoNativeVrfCreate(ctx context.Context, d *schema.ResourceData, m
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Path:     "/data/Cisco-IOS-XE-native:native/vrf/definition",
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    "/data/Cisco-IOS-XE-native:native/vrf/definition",
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 
+	devices := getDevices(d)
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
@@ -74,23 +84,52 @@ func resourceCiscoNativeVrfCreate(ctx context.Context, d *schema.ResourceData, m
 		if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 			svc.Payload = string(b)
 		}
-		if svc.Provider.Get("debug").(bool) {
-			debugJson(fmt.Sprintf("vrf_%v_%v", svc.Role, d.Get("name").(string)), svc.Payload)
+		svc.CLI = data.CLI()
+		if svc.Config.Debug {
+			debugPayload(fmt.Sprintf("vrf_%v_%v", svc.Role, d.Get("name").(string)), svc)
 		}
 
-		_, err = iosxe.MultiSession(svc)
+		err = c.applyRole(svc, devices)
 		if err != nil {
+			setDevices(d, devices)
 			return diag.FromErr(err)
 		}
 	}
 
 	d.SetId(fmt.Sprintf("%v", d.Get("name").(string)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
 func resourceCiscoNativeVrfRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	log.Println("[DEBUG] TODO")
+	log.Println("[DEBUG] Cisco VRF READ")
 	var diags diag.Diagnostics
+
+	c, _ := meta.(*providerClient)
+	bodies, err := c.readRoles(ctx, d, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/vrf/definition=%v", d.Get("name").(string)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	states := make(map[string]map[string]interface{})
+	for host, body := range bodies {
+		data := &vrf.CiscoIOSXENativeVrf{}
+		if err = unmarshalBody(body, data); err != nil && !errors.Is(err, iosxe.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+		if len(data.CiscoIOSXENativeDefinition) == 0 {
+			log.Printf("[DEBUG] VRF %v not found on: %v\n", d.Get("name").(string), host)
+			d.SetId("")
+			return diags
+		}
+		states[host] = c.CiscoIOSXENativeVrfState(data)
+	}
+
+	if err = setDevicesDriftState(d, states); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
@@ -104,17 +143,19 @@ func resourceCiscoNativeVrfUpdate(ctx context.Context, d *schema.ResourceData, m
 		d.Set("name", oldState)
 		return diag.Errorf("Not supported to change Name of VRF")
 	}
-	if d.HasChange("roles") {
-		oldState, _ := d.GetChange("roles")
-		d.Set("roles", oldState)
-		return diag.Errorf("Not supported to change Roles")
-	}
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "PATCH",
-		Path:     fmt.Sprintf("/data/Cisco-IOS-XE-native:native/vrf/definition=%v", d.Get("name").(string)),
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "PATCH",
+		Path:    fmt.Sprintf("/data/Cisco-IOS-XE-native:native/vrf/definition=%v", d.Get("name").(string)),
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	devices := getDevices(d)
+	if err = c.removeHosts(ctx, d, devices, fmt.Sprintf("/data/Cisco-IOS-XE-native:native/vrf/definition=%v", d.Get("name").(string))); err != nil {
+		setDevices(d, devices)
+		return diag.FromErr(err)
 	}
 
 	roles := d.Get("roles").([]interface{})
@@ -128,17 +169,22 @@ func resourceCiscoNativeVrfUpdate(ctx context.Context, d *schema.ResourceData, m
 		if b, err := json.MarshalIndent(data, "", "\t"); err == nil {
 			svc.Payload = string(b)
 		}
-		if svc.Provider.Get("debug").(bool) {
-			debugJson(fmt.Sprintf("vrf_%v_%v", svc.Role, d.Get("name").(string)), svc.Payload)
+		svc.CLI = data.CLI()
+		if svc.Config.Debug {
+			debugPayload(fmt.Sprintf("vrf_%v_%v", svc.Role, d.Get("name").(string)), svc)
 		}
 
-		_, err = iosxe.MultiSession(svc)
+		err = c.applyRole(svc, devices)
 		if err != nil {
+			setDevices(d, devices)
 			return diag.FromErr(err)
 		}
 	}
 
 	d.SetId(fmt.Sprintf("%v", d.Get("name").(string)))
+	if err = setDevices(d, devices); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
@@ -149,16 +195,18 @@ func resourceCiscoNativeVrfDelete(ctx context.Context, d *schema.ResourceData, m
 
 	c, _ := meta.(*providerClient)
 	svc := &service.Client{
-		Method:   "DELETE",
-		Path:     fmt.Sprintf("/data/Cisco-IOS-XE-native:native/vrf/definition=%v", d.Get("name").(string)),
-		Provider: c.Provider,
-		Devices:  c.Devices.List(),
+		Context: ctx,
+		Method:  "DELETE",
+		Path:    fmt.Sprintf("/data/Cisco-IOS-XE-native:native/vrf/definition=%v", d.Get("name").(string)),
+		Config:  c.Config,
+		Devices: c.Devices.List(),
 	}
 
+	devices := getDevices(d)
 	roles := d.Get("roles").([]interface{})
 	for _, role := range roles {
 		svc.Role = role.(string)
-		_, err = iosxe.MultiSession(svc)
+		err = c.deleteRole(svc, devices)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -168,11 +216,31 @@ func resourceCiscoNativeVrfDelete(ctx context.Context, d *schema.ResourceData, m
 	return diags
 }
 
-func (*providerClient) CiscoIOSXENativeVrfData(d *schema.ResourceData) *vrf.CiscoIOSXENativeVrf {
+func resourceCiscoNativeVrfImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Println("[DEBUG] Cisco VRF IMPORT")
+	c, _ := meta.(*providerClient)
+	parts, err := importID(d.Id(), "<roles>:<name>")
+	if err != nil {
+		return nil, err
+	}
+	if err = c.importRoles(d, parts[0]); err != nil {
+		return nil, err
+	}
+	d.Set("name", parts[1])
+	d.SetId(parts[1])
+	return importRead(ctx, d, meta, resourceCiscoNativeVrfRead)
+}
+
+func (c *providerClient) CiscoIOSXENativeVrfData(d *schema.ResourceData) *vrf.CiscoIOSXENativeVrf {
+	return c.vrfDefinitionData(d.Get("name").(string), d)
+}
+
+// vrfDefinitionData builds the VRF from the rd, ipv4 and ipv6 attributes of the resource
+func (*providerClient) vrfDefinitionData(name string, d *schema.ResourceData) *vrf.CiscoIOSXENativeVrf {
 	data := &vrf.CiscoIOSXENativeVrf{}
 	vrfData := &vrf.CiscoIOSXENativeDefinition{}
 
-	vrfData.Name = d.Get("name").(string)
+	vrfData.Name = name
 	vrfData.Rd = d.Get("rd").(string)
 
 	withoutStiching := &vrf.CiscoIOSXENativeDefinitionWithoutStitching{
@@ -198,3 +266,14 @@ func (*providerClient) CiscoIOSXENativeVrfData(d *schema.ResourceData) *vrf.Cisc
 	return data
 
 }
+
+func (*providerClient) CiscoIOSXENativeVrfState(data *vrf.CiscoIOSXENativeVrf) map[string]interface{} {
+	vrfData := data.CiscoIOSXENativeDefinition[0]
+	ipv4 := vrfData.AddressFamily.Ipv4.RouteTarget.ImportRouteTarget
+	ipv6 := vrfData.AddressFamily.Ipv6.RouteTarget.ImportRouteTarget
+	return map[string]interface{}{
+		"rd":   vrfData.Rd,
+		"ipv4": len(ipv4.WithoutStitching)+len(ipv4.WithStitching) > 0,
+		"ipv6": len(ipv6.WithoutStitching)+len(ipv6.WithStitching) > 0,
+	}
+}
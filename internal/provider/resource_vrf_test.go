@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCiscoEvpnVrf(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	path := "Cisco-IOS-XE-native:native/vrf/definition=green"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDevicesRemoved(f.Devices(), path),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnVrfConfig(f, "1:1", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_vrf.test", "name", "green"),
+					resource.TestCheckResourceAttr("ciscoevpn_vrf.test", "devices.#", "2"),
+					resource.TestCheckResourceAttr("ciscoevpn_vrf.test", "cli_preview.%", "2"),
+					resource.TestMatchResourceAttr("ciscoevpn_vrf.test", "cli_preview."+f.Leafs[0].Host(), regexp.MustCompile(`vrf definition green\n rd 1:1`)),
+					testAccCheckDevices(f.Leafs, path, `"rd":"1:1"`, `"ipv4"`),
+					testAccCheckDevicesRemoved(f.Spines, path),
+				),
+			},
+			{
+				Config: testAccCiscoEvpnVrfConfig(f, "1:2", true),
+				Check:  testAccCheckDevices(f.Leafs, path, `"rd":"1:2"`, `"ipv6"`),
+			},
+		},
+	})
+}
+
+// TestAccCiscoEvpnVrfDrift checks that a change on one of the devices shows up in the plan and is reverted by the apply
+func TestAccCiscoEvpnVrfDrift(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	path := "Cisco-IOS-XE-native:native/vrf/definition=green"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDevicesRemoved(f.Devices(), path),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnVrfConfig(f, "1:1", false),
+				Check:  testAccCheckDevices(f.Leafs, path, `"rd":"1:1"`),
+			},
+			{
+				PreConfig: func() {
+					if err := f.Leafs[1].Set(path+"/rd", `{"Cisco-IOS-XE-native:rd":"9:9"}`); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             testAccCiscoEvpnVrfConfig(f, "1:1", false),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccCiscoEvpnVrfConfig(f, "1:1", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ciscoevpn_vrf.test", "rd", "1:1"),
+					resource.TestCheckResourceAttr("ciscoevpn_vrf.test", "devices.0.status", "applied"),
+					resource.TestCheckResourceAttr("ciscoevpn_vrf.test", "devices.1.status", "applied"),
+					testAccCheckDevices(f.Leafs, path, `"rd":"1:1"`),
+				),
+			},
+		},
+	})
+}
+
+func testAccCiscoEvpnVrfConfig(f *testAccFabric, rd string, ipv6 bool) string {
+	return f.Config() + fmt.Sprintf(`
+resource "ciscoevpn_vrf" "test" {
+  roles = ["leafs"]
+  name  = "green"
+  rd    = %q
+  ipv4  = true
+  ipv6  = %v
+}
+`, rd, ipv6)
+}
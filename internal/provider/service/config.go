@@ -0,0 +1,59 @@
+package service
+
+import "sync"
+
+// Config is the provider configuration, resolved once when the provider is configured.
+// The sessions of all hosts read it at the same time, the settings must not change afterwards.
+type Config struct {
+	Username   string
+	Password   string
+	Insecure   bool
+	Timeout    int
+	CAFile     string
+	ProxyURL   string
+	ProxyCreds string
+
+	Retries          int
+	RetryMinBackoff  int
+	RetryMaxBackoff  int
+	RetryMaxElapsed  int
+	RetryStatusCodes []int
+	RetryErrors      []string
+
+	Parallelism   int
+	Transactional bool
+
+	Transport             string
+	NetconfPort           int
+	NetconfDatastore      string
+	NetconfConfirmTimeout int
+	KnownHostsFile        string
+
+	CassetteMode string
+	CassetteDir  string
+
+	DryRun     bool
+	PlanReport string
+	Debug      bool
+
+	mu      sync.Mutex
+	clients map[string]interface{}
+}
+
+// HostClient returns the client of the host, it's created with newClient on the first call and reused afterwards
+func (c *Config) HostClient(host string, newClient func() (interface{}, error)) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v, ok := c.clients[host]; ok {
+		return v, nil
+	}
+	v, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+	if c.clients == nil {
+		c.clients = make(map[string]interface{})
+	}
+	c.clients[host] = v
+	return v, nil
+}
@@ -1,13 +1,34 @@
 package service
 
-import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+import (
+	"context"
+)
 
 type Client struct {
-	Method   string
-	Path     string
-	Payload  string
-	Provider schema.ResourceData
-	Device   string
-	Devices  []interface{}
-	Role     string
+	Context context.Context
+	Method  string
+	Path    string
+	Payload string
+	CLI     []string
+	Config  *Config
+	Device  string
+	Devices []interface{}
+	Role    string
+	Hosts   []interface{}
+}
+
+// Transport sends the requests of a Client to a single device.
+// Paths and payloads are RESTCONF paths and YANG JSON, whatever the protocol used on the wire.
+type Transport interface {
+	// Get returns the YANG JSON of the path
+	Get(ctx context.Context, path string) (string, error)
+	// Merge merges the payload into the config of the path (PATCH)
+	Merge(ctx context.Context, path string, payload string) error
+	// Replace replaces the config of the path with the payload (PUT)
+	Replace(ctx context.Context, path string, payload string) error
+	// Post creates the payload under the path or invokes the RPC of an /operations path
+	Post(ctx context.Context, path string, payload string) (string, error)
+	// Delete removes the config of the path
+	Delete(ctx context.Context, path string) error
+	Close() error
 }
@@ -0,0 +1,282 @@
+package simulator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/iosxe"
+)
+
+// segment is a node of a RESTCONF path, e.g. Cisco-IOS-XE-native:native or Loopback=100
+type segment struct {
+	Module string
+	Name   string
+	Keys   []string
+}
+
+func parsePath(escaped string) ([]segment, error) {
+	segments := []segment{}
+	for _, raw := range strings.Split(strings.Trim(escaped, "/"), "/") {
+		if raw == "" {
+			continue
+		}
+		s := segment{}
+		if i := strings.Index(raw, "="); i >= 0 {
+			for _, key := range strings.Split(raw[i+1:], ",") {
+				k, err := url.PathUnescape(key)
+				if err != nil {
+					return nil, err
+				}
+				s.Keys = append(s.Keys, k)
+			}
+			raw = raw[:i]
+		}
+		name, err := url.PathUnescape(raw)
+		if err != nil {
+			return nil, err
+		}
+		s.Module, s.Name = splitName(name)
+		segments = append(segments, s)
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("empty path")
+	}
+	return segments, nil
+}
+
+// splitName splits the module prefix of a member, e.g. Cisco-IOS-XE-bgp:bgp
+func splitName(name string) (string, string) {
+	if i := strings.Index(name, ":"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
+
+func keyNames(name string, keys int) []string {
+	names := append([]string{}, iosxe.ListKeys(name)...)
+	for len(names) < keys {
+		names = append(names, "name")
+	}
+	return names
+}
+
+// member returns the stored name of the member, members are matched on the name without module
+func member(obj map[string]interface{}, name string) (string, bool) {
+	_, local := splitName(name)
+	for k := range obj {
+		if _, l := splitName(k); l == local {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+func entryMatches(entry interface{}, names []string, keys []string) bool {
+	obj, ok := entry.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for i, key := range keys {
+		k, ok := member(obj, names[i])
+		if !ok || fmt.Sprint(obj[k]) != key {
+			return false
+		}
+	}
+	return true
+}
+
+// location is a node in the datastore, a member of parent or an entry of the list in the member
+type location struct {
+	Parent map[string]interface{}
+	Member string
+	Index  int
+	Module string
+}
+
+func (l *location) value() interface{} {
+	v := l.Parent[l.Member]
+	if l.Index >= 0 {
+		return v.([]interface{})[l.Index]
+	}
+	return v
+}
+
+func (l *location) set(v interface{}) {
+	if l.Index >= 0 {
+		l.Parent[l.Member].([]interface{})[l.Index] = v
+		return
+	}
+	l.Parent[l.Member] = v
+}
+
+func (l *location) remove() {
+	if l.Index < 0 {
+		delete(l.Parent, l.Member)
+		return
+	}
+	list := l.Parent[l.Member].([]interface{})
+	list = append(list[:l.Index], list[l.Index+1:]...)
+	if len(list) == 0 {
+		delete(l.Parent, l.Member)
+		return
+	}
+	l.Parent[l.Member] = list
+}
+
+// locate walks the path in the datastore, missing containers and list entries are created with create set
+func locate(root map[string]interface{}, path []segment, create bool) (*location, bool) {
+	cur := root
+	module := ""
+	var loc *location
+	for i := 0; i < len(path); i++ {
+		s := path[i]
+		name, ok := member(cur, s.Name)
+		if _, list := cur[name].([]interface{}); ok && list && len(s.Keys) == 0 && i < len(path)-1 {
+			// The key of a list entry as the next segment, e.g. ipv4/unicast
+			i++
+			s.Keys = []string{path[i].Name}
+		}
+		if !ok {
+			if !create {
+				return nil, false
+			}
+			name = s.Name
+			if s.Module != "" {
+				name = s.Module + ":" + s.Name
+			}
+			if len(s.Keys) > 0 {
+				cur[name] = []interface{}{}
+			} else {
+				cur[name] = make(map[string]interface{})
+			}
+		}
+		switch {
+		case s.Module != "":
+			module = s.Module
+		default:
+			if m, _ := splitName(name); m != "" {
+				module = m
+			}
+		}
+		loc = &location{Parent: cur, Member: name, Index: -1, Module: module}
+
+		if len(s.Keys) > 0 {
+			list, ok := cur[name].([]interface{})
+			if !ok {
+				return nil, false
+			}
+			names := keyNames(s.Name, len(s.Keys))
+			loc.Index = -1
+			for j, entry := range list {
+				if entryMatches(entry, names, s.Keys) {
+					loc.Index = j
+				}
+			}
+			if loc.Index < 0 {
+				if !create {
+					return nil, false
+				}
+				entry := make(map[string]interface{})
+				for j, key := range s.Keys {
+					entry[names[j]] = key
+				}
+				cur[name] = append(list, entry)
+				loc.Index = len(list)
+			}
+		}
+		if i == len(path)-1 {
+			break
+		}
+		next, ok := loc.value().(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		cur = next
+	}
+	return loc, true
+}
+
+// merge merges src into dst like a RESTCONF PATCH, list entries are matched on their keys
+func merge(dst, src interface{}, name string) interface{} {
+	switch s := src.(type) {
+	case map[string]interface{}:
+		d, ok := dst.(map[string]interface{})
+		if !ok {
+			return src
+		}
+		for k, v := range s {
+			if existing, ok := member(d, k); ok {
+				_, local := splitName(k)
+				d[existing] = merge(d[existing], v, local)
+			} else {
+				d[k] = v
+			}
+		}
+		return d
+	case []interface{}:
+		d, ok := dst.([]interface{})
+		if !ok {
+			return src
+		}
+		for _, e := range s {
+			if i := findEntry(d, e, name); i >= 0 {
+				d[i] = merge(d[i], e, name)
+			} else {
+				d = append(d, e)
+			}
+		}
+		return d
+	}
+	return src
+}
+
+// findEntry returns the index of the list entry with the keys of e, leaf-list entries are matched on their value
+func findEntry(list []interface{}, e interface{}, name string) int {
+	obj, ok := e.(map[string]interface{})
+	if !ok {
+		for i, v := range list {
+			if fmt.Sprint(v) == fmt.Sprint(e) {
+				return i
+			}
+		}
+		return -1
+	}
+	names := iosxe.ListKeys(name)
+	if len(names) == 0 {
+		names = []string{"name"}
+	}
+	keys := []string{}
+	for _, n := range names {
+		k, ok := member(obj, n)
+		if !ok {
+			return -1
+		}
+		keys = append(keys, fmt.Sprint(obj[k]))
+	}
+	for i, v := range list {
+		if entryMatches(v, names, keys) {
+			return i
+		}
+	}
+	return -1
+}
+
+// decode reads a payload, numbers are kept as json.Number so they are encoded unchanged
+func decode(payload []byte) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	if err := dec.Decode(&data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func deepCopy(v map[string]interface{}) map[string]interface{} {
+	b, _ := json.Marshal(v)
+	c, _ := decode(b)
+	return c
+}
@@ -0,0 +1,391 @@
+// Package simulator is an in-memory IOS-XE RESTCONF device for the acceptance tests.
+//
+// Every Device serves HTTPS on a local port and keeps its own JSON datastore.
+// PATCH merges, PUT replaces, POST creates and DELETE removes the node of the path.
+// The RPCs of the transactional apply (copy, delete and rollback) work on snapshots of the datastore.
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const mimeType = "application/yang-data+json"
+
+// Device is a simulated IOS-XE switch
+type Device struct {
+	Username string
+	Password string
+
+	server      *httptest.Server
+	mu          sync.Mutex
+	data        map[string]interface{}
+	checkpoints map[string]map[string]interface{}
+	locked      int
+	failures    []*failure
+	requests    []string
+}
+
+// failure answers matching requests with an error status
+type failure struct {
+	method string
+	path   string
+	count  int
+	status int
+}
+
+func (f *failure) match(r *http.Request) bool {
+	return f.count > 0 && (f.method == "" || f.method == r.Method) && strings.Contains(r.URL.Path, f.path)
+}
+
+// NewDevice starts a device with an empty native config
+func NewDevice(username, password string) *Device {
+	d := &Device{
+		Username: username,
+		Password: password,
+		data: map[string]interface{}{
+			"Cisco-IOS-XE-native:native": make(map[string]interface{}),
+		},
+		checkpoints: make(map[string]map[string]interface{}),
+	}
+	d.server = httptest.NewTLSServer(http.HandlerFunc(d.serve))
+	return d
+}
+
+// Host returns the address of the device, as used in the roles of the provider
+func (d *Device) Host() string {
+	return d.server.Listener.Addr().String()
+}
+
+func (d *Device) Close() {
+	d.server.Close()
+}
+
+// LockDatabase answers the next n requests with 409, like a device busy with another config session
+func (d *Device) LockDatabase(n int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.locked = n
+}
+
+// FailRequests answers the next n requests with the method and a path containing path with the status,
+// an empty method matches every method, e.g. FailRequests("GET", "interface/Vlan", 1, 500)
+func (d *Device) FailRequests(method, path string, n int, status int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.failures = append(d.failures, &failure{method: method, path: path, count: n, status: status})
+}
+
+// Requests returns the requests served so far as "METHOD path"
+func (d *Device) Requests() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string{}, d.requests...)
+}
+
+// Checkpoints returns the names of the checkpoints taken by the copy RPC and not deleted yet
+func (d *Device) Checkpoints() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	names := []string{}
+	for name := range d.checkpoints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the YANG JSON of the path in the datastore, e.g. Cisco-IOS-XE-native:native/interface/Loopback=100
+func (d *Device) Get(path string) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	segments, err := parsePath(path)
+	if err != nil {
+		return "", false
+	}
+	body, err := d.get(segments, nil)
+	if err != nil {
+		return "", false
+	}
+	return string(body), true
+}
+
+// Set replaces the node of the path with the payload, e.g. to seed operational data
+func (d *Device) Set(path string, payload string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	return d.write(http.MethodPut, segments, []byte(payload))
+}
+
+// Delete removes the node of the path from the datastore, e.g. to simulate an out-of-band change
+func (d *Device) Delete(path string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	return d.write(http.MethodDelete, segments, nil)
+}
+
+// restconfError is the body of a RESTCONF error
+func restconfError(w http.ResponseWriter, status int, tag, message string) {
+	w.Header().Set("Content-Type", mimeType)
+	w.WriteHeader(status)
+	body := map[string]interface{}{
+		"ietf-restconf:errors": map[string]interface{}{
+			"error": []interface{}{
+				map[string]interface{}{
+					"error-type":    "application",
+					"error-tag":     tag,
+					"error-message": message,
+				},
+			},
+		},
+	}
+	b, _ := json.Marshal(body)
+	w.Write(b)
+}
+
+type statusError struct {
+	Status  int
+	Tag     string
+	Message string
+}
+
+func (e *statusError) Error() string {
+	return e.Message
+}
+
+func (d *Device) serve(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.requests = append(d.requests, fmt.Sprintf("%v %v", r.Method, r.URL.RequestURI()))
+
+	if user, password, ok := r.BasicAuth(); !ok || user != d.Username || password != d.Password {
+		restconfError(w, http.StatusUnauthorized, "access-denied", "authentication failed")
+		return
+	}
+	if d.locked > 0 {
+		d.locked--
+		restconfError(w, http.StatusConflict, "in-use", "database locked")
+		return
+	}
+	for _, f := range d.failures {
+		if f.match(r) {
+			f.count--
+			restconfError(w, f.status, "operation-failed", "injected failure")
+			return
+		}
+	}
+
+	path := r.URL.EscapedPath()
+	var err error
+	var body []byte
+	switch {
+	case strings.HasPrefix(path, "/restconf/data/"):
+		var segments []segment
+		if segments, err = parsePath(strings.TrimPrefix(path, "/restconf/data/")); err != nil {
+			break
+		}
+		if r.Method == http.MethodGet {
+			body, err = d.get(segments, fields(r))
+			break
+		}
+		payload, _ := io.ReadAll(r.Body)
+		err = d.write(r.Method, segments, payload)
+	case strings.HasPrefix(path, "/restconf/operations/") && r.Method == http.MethodPost:
+		payload, _ := io.ReadAll(r.Body)
+		body, err = d.rpc(strings.TrimPrefix(path, "/restconf/operations/"), payload)
+	default:
+		err = &statusError{http.StatusNotFound, "invalid-value", "unknown resource"}
+	}
+
+	if err != nil {
+		se, ok := err.(*statusError)
+		if !ok {
+			se = &statusError{http.StatusBadRequest, "malformed-message", err.Error()}
+		}
+		restconfError(w, se.Status, se.Tag, se.Message)
+		return
+	}
+	if body == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Content-Type", mimeType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// fields returns the fields of the query, they are separated by ";" which r.URL.Query drops
+func fields(r *http.Request) []string {
+	var f []string
+	for _, param := range strings.Split(r.URL.RawQuery, "&") {
+		if !strings.HasPrefix(param, "fields=") {
+			continue
+		}
+		value, _ := url.QueryUnescape(strings.TrimPrefix(param, "fields="))
+		for _, field := range strings.Split(value, ";") {
+			if field != "" {
+				f = append(f, field)
+			}
+		}
+	}
+	return f
+}
+
+func (d *Device) get(segments []segment, fields []string) ([]byte, error) {
+	loc, ok := locate(d.data, segments, false)
+	if !ok {
+		return nil, &statusError{http.StatusNotFound, "invalid-value", "uri keypath not found"}
+	}
+	value := loc.value()
+	if loc.Index >= 0 {
+		value = []interface{}{value}
+	}
+	if len(fields) > 0 {
+		value = selectFields(value, fields)
+	}
+	_, name := splitName(loc.Member)
+	return json.Marshal(map[string]interface{}{
+		fmt.Sprintf("%v:%v", loc.Module, name): value,
+	})
+}
+
+func selectFields(value interface{}, fields []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		selected := make(map[string]interface{})
+		for _, f := range fields {
+			if k, ok := member(v, f); ok {
+				selected[k] = v[k]
+			}
+		}
+		return selected
+	case []interface{}:
+		selected := []interface{}{}
+		for _, e := range v {
+			selected = append(selected, selectFields(e, fields))
+		}
+		return selected
+	}
+	return value
+}
+
+// write applies a PATCH, PUT, POST or DELETE on the datastore
+func (d *Device) write(method string, segments []segment, payload []byte) error {
+	if method == http.MethodDelete {
+		loc, ok := locate(d.data, segments, false)
+		if !ok {
+			return &statusError{http.StatusNotFound, "invalid-value", "uri keypath not found"}
+		}
+		loc.remove()
+		return nil
+	}
+
+	data, err := decode(payload)
+	if err != nil {
+		return err
+	}
+	last := segments[len(segments)-1]
+	if len(data) == 1 {
+		for k, v := range data {
+			module, name := splitName(k)
+			if name != last.Name {
+				break
+			}
+			if last.Module == "" {
+				// A new node is stored with the module of the payload, like the device does for augmented nodes
+				segments[len(segments)-1].Module = module
+			}
+			// The payload is the node of the path
+			if method == http.MethodPost {
+				if _, ok := locate(d.data, segments, false); ok && len(last.Keys) > 0 {
+					return &statusError{http.StatusConflict, "data-exists", "object already exists"}
+				}
+			}
+			loc, ok := locate(d.data, segments, true)
+			if !ok {
+				return &statusError{http.StatusBadRequest, "invalid-value", "invalid path"}
+			}
+			if list, ok := v.([]interface{}); ok && loc.Index >= 0 && len(list) == 1 {
+				v = list[0]
+			}
+			if method == http.MethodPut {
+				loc.set(v)
+			} else {
+				loc.set(merge(loc.value(), v, last.Name))
+			}
+			return nil
+		}
+	}
+
+	// The payload are children of the node of the path
+	loc, ok := locate(d.data, segments, true)
+	if !ok {
+		return &statusError{http.StatusBadRequest, "invalid-value", "invalid path"}
+	}
+	obj, ok := loc.value().(map[string]interface{})
+	if !ok {
+		return &statusError{http.StatusBadRequest, "invalid-value", "path isn't a container"}
+	}
+	if method == http.MethodPut {
+		for k := range obj {
+			delete(obj, k)
+		}
+	}
+	merge(obj, map[string]interface{}(data), last.Name)
+	return nil
+}
+
+// rpc runs the RPCs of the transactional apply on snapshots of the datastore
+func (d *Device) rpc(name string, payload []byte) ([]byte, error) {
+	data, err := decode(payload)
+	if err != nil {
+		return nil, err
+	}
+	input := make(map[string]interface{})
+	for _, v := range data {
+		if obj, ok := v.(map[string]interface{}); ok {
+			input = obj
+		}
+	}
+	output := func(module string, result string) ([]byte, error) {
+		return json.Marshal(map[string]interface{}{
+			module + ":output": map[string]interface{}{"result": result},
+		})
+	}
+
+	switch name {
+	case "Cisco-IOS-XE-rpc:copy":
+		if input["source-drop-node-name"] != "running-config" {
+			return nil, &statusError{http.StatusBadRequest, "invalid-value", "unsupported copy source"}
+		}
+		dst := fmt.Sprint(input["destination-drop-node-name"])
+		d.checkpoints[dst] = deepCopy(d.data)
+		return output("Cisco-IOS-XE-rpc", "Copy operation was successful")
+	case "Cisco-IOS-XE-rpc:delete":
+		delete(d.checkpoints, fmt.Sprint(input["filename-drop-node-name"]))
+		return output("Cisco-IOS-XE-rpc", "Delete operation was successful")
+	case "cisco-ia:rollback":
+		checkpoint, ok := d.checkpoints[fmt.Sprint(input["target-url"])]
+		if !ok {
+			return nil, &statusError{http.StatusBadRequest, "invalid-value", "rollback file not found"}
+		}
+		d.data = deepCopy(checkpoint)
+		return output("cisco-ia", "Rollback successful")
+	}
+	return nil, &statusError{http.StatusNotFound, "invalid-value", "unknown operation"}
+}
@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/rpc"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/iosxe"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
+)
+
+type applyFunc = func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics
+
+// transactional wraps the create, update or delete of a role resource. With the provider in transactional mode
+// a checkpoint of the running config is taken on every device first, if the apply fails all devices are rolled back.
+// The rollback restores the whole running config, the transactions are serialized per host so it doesn't
+// undo the apply of another resource.
+func transactional(apply applyFunc) applyFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		c, _ := meta.(*providerClient)
+		if !c.Config.Transactional || c.Config.DryRun {
+			return apply(ctx, d, meta)
+		}
+
+		hosts := c.roleHosts(d.Get("roles").([]interface{}))
+		for host, state := range getDevices(d) {
+			hosts[host] = state.Role
+		}
+		if len(hosts) == 0 {
+			return apply(ctx, d, meta)
+		}
+		checkpoint := fmt.Sprintf("flash:terraform-%v.cfg", time.Now().UnixNano())
+		svc := &service.Client{
+			Context: ctx,
+			Method:  "POST",
+			Config:  c.Config,
+		}
+		for host := range hosts {
+			svc.Hosts = append(svc.Hosts, host)
+		}
+		sort.Slice(svc.Hosts, func(i, j int) bool { return svc.Hosts[i].(string) < svc.Hosts[j].(string) })
+		defer c.transactions.lock(svc.Hosts)()
+
+		log.Printf("[DEBUG] Transaction checkpoint %v on: %v\n", checkpoint, svc.Hosts)
+		svc.Path = "/operations/Cisco-IOS-XE-rpc:copy"
+		svc.Payload = rpcPayload(&rpc.CiscoIOSXERpcCopy{
+			Input: rpc.CiscoIOSXERpcCopyInput{
+				SourceDropNodeName:      "running-config",
+				DestinationDropNodeName: checkpoint,
+			},
+		})
+		if data, err := iosxe.MultiSession(svc); err != nil {
+			taken := []interface{}{}
+			for host := range data {
+				taken = append(taken, host)
+			}
+			c.removeCheckpoint(svc, taken, checkpoint)
+			return diag.Errorf("Unable to take checkpoint, nothing applied: %v", err)
+		}
+
+		diags := apply(ctx, d, meta)
+		if diags.HasError() {
+			log.Printf("[DEBUG] Transaction rollback %v on: %v\n", checkpoint, svc.Hosts)
+			// The apply has failed, the rollback shouldn't be cancelled with it
+			svc.Context = context.Background()
+			svc.Path = "/operations/cisco-ia:rollback"
+			svc.Payload = rpcPayload(&rpc.CiscoIARollback{
+				Input: rpc.CiscoIARollbackInput{
+					TargetURL: checkpoint,
+					Verbose:   true,
+				},
+			})
+			_, err := iosxe.MultiSession(svc)
+			if err != nil {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Error,
+					Summary:  "Rollback failed",
+					Detail:   fmt.Sprintf("Devices might be partially configured, checkpoint %v kept on the devices. Error - %v", checkpoint, err),
+				})
+			} else {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  "Rolled back",
+					Detail:   fmt.Sprintf("All devices were rolled back to checkpoint %v", checkpoint),
+				})
+			}
+
+			devices := getDevices(d)
+			for _, host := range svc.Hosts {
+				if state, ok := devices[host.(string)]; ok {
+					state.Status = deviceFailed
+				}
+			}
+			setDevices(d, devices)
+			if err != nil {
+				return diags
+			}
+		}
+		svc.Context = context.Background()
+		c.removeCheckpoint(svc, svc.Hosts, checkpoint)
+		return diags
+	}
+}
+
+// hostLocks holds a mutex per host
+type hostLocks struct {
+	mu    sync.Mutex
+	hosts map[string]*sync.Mutex
+}
+
+// lock locks the sorted hosts in order and returns the unlock
+func (l *hostLocks) lock(hosts []interface{}) func() {
+	l.mu.Lock()
+	if l.hosts == nil {
+		l.hosts = make(map[string]*sync.Mutex)
+	}
+	locks := []*sync.Mutex{}
+	for _, host := range hosts {
+		m, ok := l.hosts[host.(string)]
+		if !ok {
+			m = &sync.Mutex{}
+			l.hosts[host.(string)] = m
+		}
+		locks = append(locks, m)
+	}
+	l.mu.Unlock()
+
+	for _, m := range locks {
+		m.Lock()
+	}
+	return func() {
+		for i := len(locks) - 1; i >= 0; i-- {
+			locks[i].Unlock()
+		}
+	}
+}
+
+// removeCheckpoint deletes the checkpoint file from the hosts
+func (c *providerClient) removeCheckpoint(svc *service.Client, hosts []interface{}, checkpoint string) {
+	if len(hosts) == 0 {
+		return
+	}
+	svc.Hosts = hosts
+	svc.Path = "/operations/Cisco-IOS-XE-rpc:delete"
+	svc.Payload = rpcPayload(&rpc.CiscoIOSXERpcDelete{
+		Input: rpc.CiscoIOSXERpcDeleteInput{
+			FilenameDropNodeName: checkpoint,
+		},
+	})
+	if _, err := iosxe.MultiSession(svc); err != nil {
+		log.Println("[DEBUG] Unable to remove checkpoint: ", err)
+	}
+}
+
+func rpcPayload(data interface{}) string {
+	b, _ := json.MarshalIndent(data, "", "\t")
+	return string(b)
+}
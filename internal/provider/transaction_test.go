@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/simulator"
+)
+
+func TestAccCiscoEvpnTransaction(t *testing.T) {
+	f := newTestAccFabric(t, 1, 2)
+	f.Settings = "  transactional = true"
+	path := "Cisco-IOS-XE-native:native/vrf/definition=green"
+	// the checks run before the next step, the steps with an error don't run their checks
+	check := func(checks ...resource.TestCheckFunc) func() {
+		return func() {
+			if err := resource.ComposeTestCheckFunc(checks...)(nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDevicesRemoved(f.Devices(), path),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCiscoEvpnVrfConfig(f, "1:1", false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDevices(f.Leafs, path, `"rd":"1:1"`),
+					testAccCheckCheckpoints(f.Devices(), 0),
+				),
+			},
+			{
+				// The update fails on one leaf, the other one is rolled back
+				PreConfig: func() {
+					f.Leafs[1].FailRequests("PATCH", "vrf/definition", 1, 500)
+				},
+				Config:      testAccCiscoEvpnVrfConfig(f, "1:2", false),
+				ExpectError: regexp.MustCompile("500 Internal Server Error"),
+			},
+			{
+				// The checkpoint fails on one leaf, nothing is applied
+				PreConfig: func() {
+					check(
+						testAccCheckDevices(f.Leafs, path, `"rd":"1:1"`),
+						testAccCheckCheckpoints(f.Devices(), 0),
+					)()
+					f.Leafs[1].FailRequests("POST", "Cisco-IOS-XE-rpc:copy", 1, 500)
+				},
+				Config:      testAccCiscoEvpnVrfConfig(f, "1:2", false),
+				ExpectError: regexp.MustCompile("Unable to take checkpoint, nothing applied"),
+			},
+			{
+				// The rollback fails, the checkpoints are kept on the devices
+				PreConfig: func() {
+					check(
+						testAccCheckDevices(f.Leafs, path, `"rd":"1:1"`),
+						testAccCheckCheckpoints(f.Devices(), 0),
+					)()
+					f.Leafs[1].FailRequests("PATCH", "vrf/definition", 1, 500)
+					f.Leafs[0].FailRequests("POST", "cisco-ia:rollback", 1, 500)
+				},
+				Config:      testAccCiscoEvpnVrfConfig(f, "1:2", false),
+				ExpectError: regexp.MustCompile("Rollback failed"),
+			},
+			{
+				// The delete fails on one leaf, the other one is rolled back
+				PreConfig: func() {
+					check(
+						testAccCheckDevices(f.Leafs[:1], path, `"rd":"1:2"`),
+						testAccCheckDevices(f.Leafs[1:], path, `"rd":"1:1"`),
+						testAccCheckCheckpoints(f.Leafs, 1),
+					)()
+					f.Leafs[1].FailRequests("DELETE", "vrf/definition", 1, 500)
+				},
+				Config:      f.Config(),
+				ExpectError: regexp.MustCompile("500 Internal Server Error"),
+			},
+			{
+				PreConfig: check(
+					testAccCheckDevices(f.Leafs[:1], path, `"rd":"1:2"`),
+					testAccCheckDevices(f.Leafs[1:], path, `"rd":"1:1"`),
+				),
+				Config: testAccCiscoEvpnVrfConfig(f, "1:1", false),
+				Check:  testAccCheckDevices(f.Leafs, path, `"rd":"1:1"`),
+			},
+		},
+	})
+}
+
+// testAccCheckCheckpoints checks the number of checkpoints kept on the devices
+func testAccCheckCheckpoints(devices []*simulator.Device, n int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, d := range devices {
+			if checkpoints := d.Checkpoints(); len(checkpoints) != n {
+				return fmt.Errorf("expected %v checkpoints on %v, got %v", n, d.Host(), checkpoints)
+			}
+		}
+		return nil
+	}
+}
+
+func TestHostLocks(t *testing.T) {
+	var locks hostLocks
+	var mu sync.Mutex
+	running := map[string]bool{}
+	var wg sync.WaitGroup
+	for _, hosts := range [][]interface{}{{"a", "b"}, {"b", "c"}, {"a", "c"}, {"c"}} {
+		wg.Add(1)
+		go func(hosts []interface{}) {
+			defer wg.Done()
+			defer locks.lock(hosts)()
+			mu.Lock()
+			for _, host := range hosts {
+				if running[host.(string)] {
+					t.Errorf("%v locked twice", host)
+				}
+				running[host.(string)] = true
+			}
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			for _, host := range hosts {
+				running[host.(string)] = false
+			}
+			mu.Unlock()
+		}(hosts)
+	}
+	wg.Wait()
+}
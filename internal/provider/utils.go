@@ -1,14 +1,39 @@
 package provider
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"os"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/model/loopback"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/iosxe"
+	"github.com/robertcsapo/terraform-provider-ciscoevpn/internal/provider/service"
 )
 
+// debugPayload writes the payload and the IOS-XE CLI of the request in to the debug folder
+func debugPayload(name string, svc *service.Client) {
+	debugJson(name, svc.Payload)
+	if len(svc.CLI) == 0 {
+		return
+	}
+	log.Printf("[DEBUG] IOS-XE CLI of %v:\n%v\n", name, strings.Join(svc.CLI, "\n"))
+	if err := os.WriteFile(fmt.Sprintf("debug/%v.cli", name), []byte(strings.Join(svc.CLI, "\n")+"\n"), 0644); err != nil {
+		log.Println("[WARN] Can't create debug CLI ", err)
+	}
+}
+
 func debugJson(name string, payload string) {
 	var err error
 	b := []byte(payload)
@@ -16,18 +41,86 @@ func debugJson(name string, payload string) {
 
 	if _, err := os.Stat(folder); errors.Is(err, os.ErrNotExist) {
 		if err = os.Mkdir(folder, os.ModePerm); err != nil {
-			log.Panicln("[PANIC] Can't create debug folder ", err)
+			log.Println("[WARN] Can't create debug folder ", err)
+			return
 		}
 	}
 	if err = os.WriteFile(fmt.Sprintf("%v%v.json", folder, name), b, 0644); err != nil {
-		log.Panicln("[PANIC] Can't create debug payload ", err)
+		log.Println("[WARN] Can't create debug payload ", err)
 	}
 }
 
+var loopbackRe = regexp.MustCompile("^(Loopback)?([0-9]+)$")
+
+// validateLoopback accepts a Loopback interface as Loopback<id> or <id>
+func validateLoopback() schema.SchemaValidateDiagFunc {
+	return validation.ToDiagFunc(validation.StringMatch(loopbackRe, "expected a Loopback interface, e.g. Loopback0"))
+}
+
+// suppressLoopbackDiff compares the Loopback ids, the devices return Loopback<id> for an attribute configured as <id>
+func suppressLoopbackDiff(k, old, new string, d *schema.ResourceData) bool {
+	return loopbackId(old) == loopbackId(new)
+}
+
+var interfaceRe = regexp.MustCompile("^([A-Za-z-]+)([0-9][0-9/.:]*)$")
+
+// validateInterface accepts an IOS-XE interface name, e.g. TenGigabitEthernet1/0/1
+func validateInterface() schema.SchemaValidateFunc {
+	return validation.StringMatch(interfaceRe, "expected an interface, e.g. TenGigabitEthernet1/0/1")
+}
+
+// interfaceName splits the interface in to the YANG list (e.g. TenGigabitEthernet) and the name (e.g. 1/0/1)
+func interfaceName(name string) (string, string) {
+	data := interfaceRe.FindStringSubmatch(name)
+	if data == nil {
+		return "", name
+	}
+	return data[1], data[2]
+}
+
+// interfacePath is the RESTCONF path of the interface, e.g. TenGigabitEthernet=1%2F0%2F1
+func interfacePath(name string) string {
+	list, id := interfaceName(name)
+	return fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/%v=%v", list, strings.ReplaceAll(id, "/", "%2F"))
+}
+
 func loopbackId(loopback string) string {
-	re := regexp.MustCompile("[0-9]+")
-	data := re.FindAllString(loopback, 1)
-	return data[0]
+	data := loopbackRe.FindStringSubmatch(loopback)
+	if data == nil {
+		return loopback
+	}
+	return data[2]
+}
+
+// loopbackNumber returns the Loopback id of the attribute, target is the host or role the payload is built for
+func loopbackNumber(d *schema.ResourceData, attr string, target string) (int, diag.Diagnostics) {
+	id, err := strconv.Atoi(loopbackId(d.Get(attr).(string)))
+	if err != nil {
+		return 0, attrDiag(attr, target, fmt.Errorf("invalid Loopback interface %q", d.Get(attr).(string)))
+	}
+	return id, nil
+}
+
+// attrDiag reports the error on the attribute for the host or role
+func attrDiag(attr string, target string, err error) diag.Diagnostics {
+	return diag.Diagnostics{
+		{
+			Severity:      diag.Error,
+			Summary:       err.Error(),
+			Detail:        fmt.Sprintf("Attribute %v on %v: %v", attr, target, err),
+			AttributePath: cty.GetAttrPath(attr),
+		},
+	}
+}
+
+// roleTarget describes the role and its hosts for diagnostics
+func (c *providerClient) roleTarget(role string) string {
+	hosts := []string{}
+	for _, host := range iosxe.HostRoles(c.Devices.List(), role) {
+		hosts = append(hosts, host.(string))
+	}
+	sort.Strings(hosts)
+	return fmt.Sprintf("role %v (%v)", role, strings.Join(hosts, ", "))
 }
 
 func null() []string {
@@ -36,3 +129,165 @@ func null() []string {
 	empty = append(empty, null.String)
 	return empty
 }
+
+// readRoles GETs the path from every device in the roles of the resource,
+// the body of the hosts without the path is empty so only the other errors are returned
+func (c *providerClient) readRoles(ctx context.Context, d *schema.ResourceData, path string) (map[string]string, error) {
+	bodies := make(map[string]string)
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "GET",
+		Path:    path,
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+	}
+
+	roles := d.Get("roles").([]interface{})
+	for _, role := range roles {
+		svc.Role = role.(string)
+		data, err := iosxe.MultiSession(svc)
+		for host, body := range data {
+			bodies[host] = body
+		}
+		notFound, err := iosxe.SplitNotFound(err)
+		if err != nil {
+			return bodies, err
+		}
+		for _, host := range notFound {
+			bodies[host] = ""
+		}
+	}
+	return bodies, nil
+}
+
+// loopbackAddresses returns the IPv4 address of the Loopback on each host of the role
+func (c *providerClient) loopbackAddresses(ctx context.Context, role string, id int) (map[string]string, error) {
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "GET",
+		Path:    fmt.Sprintf("/data/Cisco-IOS-XE-native:native/interface/Loopback=%v", id),
+		Config:  c.Config,
+		Devices: c.Devices.List(),
+		Role:    role,
+	}
+	bodies, err := iosxe.MultiSession(svc)
+	if err != nil {
+		return nil, err
+	}
+	addresses := make(map[string]string)
+	for host, body := range bodies {
+		data := &loopback.CiscoIOSXENativeLoopbackInterface{}
+		if err = unmarshalBody(body, data); err != nil {
+			return nil, err
+		}
+		for _, lp := range data.CiscoIOSXENativeLoopback {
+			if lp.Name == id && lp.IP.Address.Primary.Address != "" {
+				addresses[host] = lp.IP.Address.Primary.Address
+			}
+		}
+		if addresses[host] == "" {
+			return nil, fmt.Errorf("no IPv4 address on Loopback%v of %v", id, host)
+		}
+	}
+	return addresses, nil
+}
+
+// readHost GETs the path from the device of a single host resource
+func (c *providerClient) readHost(ctx context.Context, d stateGetter, path string) (string, error) {
+	svc := &service.Client{
+		Context: ctx,
+		Method:  "GET",
+		Path:    path,
+		Config:  c.Config,
+		Device:  d.Get("host").(string),
+	}
+	return iosxe.SingleSession(svc)
+}
+
+// unmarshalBody decodes the RESTCONF body into the yang model, an empty body means no data
+func unmarshalBody(body string, data interface{}) error {
+	if body == "" {
+		return iosxe.ErrNotFound
+	}
+	return json.Unmarshal([]byte(body), data)
+}
+
+// setDriftState sets the state read from the devices. The first device (sorted by host)
+// which differs from the current state wins, so drift on any device in the roles shows up in the plan.
+func setDriftState(d *schema.ResourceData, states map[string]map[string]interface{}) error {
+	var state map[string]interface{}
+	hosts := make([]string, 0, len(states))
+	for host := range states {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	for _, host := range hosts {
+		state = states[host]
+		if stateDrift(d, state) {
+			log.Println("[DEBUG] Drift detected on: ", host)
+			break
+		}
+	}
+	for k, v := range state {
+		if err := d.Set(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func stateDrift(d *schema.ResourceData, state map[string]interface{}) bool {
+	for k, v := range state {
+		if fmt.Sprint(d.Get(k)) != fmt.Sprint(v) {
+			log.Printf("[DEBUG] Drift in %v: %v != %v\n", k, d.Get(k), v)
+			return true
+		}
+	}
+	return false
+}
+
+// orderLike keeps the order of the current list for entries found on the device,
+// entries only found on the device are appended
+func orderLike(current []interface{}, remote []interface{}) []interface{} {
+	ordered := []interface{}{}
+	found := make(map[string]bool)
+	for _, r := range remote {
+		found[fmt.Sprint(r)] = true
+	}
+	for _, v := range current {
+		if found[fmt.Sprint(v)] {
+			ordered = append(ordered, v)
+			delete(found, fmt.Sprint(v))
+		}
+	}
+	for _, r := range remote {
+		if found[fmt.Sprint(r)] {
+			ordered = append(ordered, r)
+			delete(found, fmt.Sprint(r))
+		}
+	}
+	return ordered
+}
+
+// vlanRange expands IOS-XE vlan lists like "10,20-22" in to single vlans
+func vlanRange(vlans string) []interface{} {
+	data := []interface{}{}
+	for _, v := range strings.Split(vlans, ",") {
+		r := strings.Split(strings.TrimSpace(v), "-")
+		start, err := strconv.Atoi(r[0])
+		if err != nil {
+			continue
+		}
+		end := start
+		if len(r) == 2 {
+			if end, err = strconv.Atoi(r[1]); err != nil {
+				continue
+			}
+		}
+		for i := start; i <= end; i++ {
+			data = append(data, i)
+		}
+	}
+	return data
+}